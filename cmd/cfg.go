@@ -34,8 +34,10 @@ when installing the key in a volume.`,
 
 func init() {
 	var (
+		cmdApply            commands.CmdObjectApply
 		cmdCreate           commands.CmdObjectCreate
 		cmdDelete           commands.CmdObjectDelete
+		cmdDoc              commands.CmdKeywordDoc
 		cmdEdit             commands.CmdObjectEdit
 		cmdEditConfig       commands.CmdObjectEditConfig
 		cmdEval             commands.CmdObjectEval
@@ -66,8 +68,10 @@ func init() {
 
 	cmdAdd.Init(kind, head, &selectorFlag)
 	cmdChange.Init(kind, head, &selectorFlag)
+	cmdApply.Init(kind, head, &selectorFlag)
 	cmdCreate.Init(kind, head, &selectorFlag)
 	cmdDelete.Init(kind, head, &selectorFlag)
+	cmdDoc.Init(kind, head)
 	cmdDecode.Init(kind, head, &selectorFlag)
 	cmdEdit.Init(kind, head, &selectorFlag)
 	cmdEditConfig.Init(kind, cmdEdit.Command, &selectorFlag)
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/commands"
+)
+
+var (
+	subClusterEdit = &cobra.Command{
+		Use:     "edit",
+		Short:   "edit information about the cluster",
+		Aliases: []string{"edi", "ed"},
+	}
+	subClusterPrint = &cobra.Command{
+		Use:     "print",
+		Short:   "print information about the cluster",
+		Aliases: []string{"prin", "pri", "pr"},
+	}
+	subClusterValidate = &cobra.Command{
+		Use:     "validate",
+		Short:   "validate the cluster",
+		Aliases: []string{"validat", "valida", "valid", "val"},
+	}
+	subCluster = &cobra.Command{
+		Use:   "cluster",
+		Short: "Manage the cluster configuration",
+		Long: `Cluster configuration object subsystem.
+
+The cluster configuration, stored in cluster.conf, hosts the cluster
+name, secret and nodes, the heartbeats, arbitrators, stonith and pools
+definitions. Its keywords are the same as node.conf, where they can be
+overriden for a node.
+`,
+	}
+
+	// clusterSelector is the selector of the cluster configuration
+	// object, the only object the cluster commands act on.
+	clusterSelector = "cluster"
+)
+
+func init() {
+	var (
+		cmdDoc            commands.CmdKeywordDoc
+		cmdEditConfig     commands.CmdObjectEditConfig
+		cmdEval           commands.CmdObjectEval
+		cmdGet            commands.CmdObjectGet
+		cmdPrintConfig    commands.CmdObjectPrintConfig
+		cmdSet            commands.CmdObjectSet
+		cmdUnset          commands.CmdObjectUnset
+		cmdValidateConfig commands.CmdObjectValidateConfig
+	)
+
+	kind := "ccfg"
+	head := subCluster
+	subEdit := subClusterEdit
+	subPrint := subClusterPrint
+	subValidate := subClusterValidate
+	root := rootCmd
+
+	root.AddCommand(head)
+	head.AddCommand(subEdit)
+	head.AddCommand(subPrint)
+	head.AddCommand(subValidate)
+
+	cmdDoc.Init("cluster", head)
+	cmdEditConfig.Init(kind, subEdit, &clusterSelector)
+	cmdEval.Init(kind, head, &clusterSelector)
+	cmdGet.Init(kind, head, &clusterSelector)
+	cmdPrintConfig.Init(kind, subPrint, &clusterSelector)
+	cmdSet.Init(kind, head, &clusterSelector)
+	cmdUnset.Init(kind, head, &clusterSelector)
+	cmdValidateConfig.Init(kind, subValidate, &clusterSelector)
+}
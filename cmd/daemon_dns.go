@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints"
+)
+
+var daemonDNSCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Serve the cluster dns zone to a PowerDNS remote backend, through the node dns unix socket.",
+	Run:   daemonDNSCmdRun,
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonDNSCmd)
+}
+
+func daemonDNSCmdRun(_ *cobra.Command, _ []string) {
+	err := entrypoints.DaemonDNS{
+		Server: serverFlag,
+	}.Do()
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
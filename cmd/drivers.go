@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	_ "opensvc.com/opensvc/drivers/poolloop"
 	_ "opensvc.com/opensvc/drivers/poolshm"
+	_ "opensvc.com/opensvc/drivers/poolvg"
 	_ "opensvc.com/opensvc/drivers/resappforking"
 	_ "opensvc.com/opensvc/drivers/resappsimple"
 	_ "opensvc.com/opensvc/drivers/resdiskloop"
@@ -9,6 +11,7 @@ import (
 	_ "opensvc.com/opensvc/drivers/resfsdir"
 	_ "opensvc.com/opensvc/drivers/resfsflag"
 	_ "opensvc.com/opensvc/drivers/resfshost"
+	_ "opensvc.com/opensvc/drivers/resipcni"
 	_ "opensvc.com/opensvc/drivers/resiphost"
 	_ "opensvc.com/opensvc/drivers/resiproute"
 	_ "opensvc.com/opensvc/drivers/resvol"
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/commands"
+)
+
+var (
+	networkCmd = &cobra.Command{
+		Use:     "network",
+		Short:   "Manage cluster backend networks",
+		Long:    ` A network is an ip address provider for the ip.cni resources. The network setup installs the CNI configuration files from the network#* node configuration sections.`,
+		Aliases: []string{"net"},
+	}
+)
+
+func init() {
+	var (
+		cmdNetworkLs    commands.NetworkLs
+		cmdNetworkSetup commands.NetworkSetup
+		cmdNetworkShow  commands.NetworkShow
+	)
+	rootCmd.AddCommand(networkCmd)
+
+	cmdNetworkLs.Init(networkCmd)
+	cmdNetworkSetup.Init(networkCmd)
+	cmdNetworkShow.Init(networkCmd)
+}
@@ -10,6 +10,10 @@ var (
 		Use:   "node",
 		Short: "Manage a opensvc cluster node",
 	}
+	nodeConfigCmd = &cobra.Command{
+		Use:   "config",
+		Short: "manage the node and installed objects configurations",
+	}
 	nodePrintCmd = &cobra.Command{
 		Use:   "print",
 		Short: "Print node",
@@ -20,18 +24,25 @@ var (
 	}
 
 	cmdNodeChecks            commands.CmdNodeChecks
+	cmdNodeConfigUpgrade     commands.CmdNodeConfigUpgrade
+	cmdNodeDoc               commands.CmdKeywordDoc
 	cmdNodeLs                commands.NodeLs
 	cmdNodePrintCapabilities commands.NodePrintCapabilities
 	cmdNodeScanCapabilities  commands.NodeScanCapabilities
+	cmdNodeRotateSecret      commands.CmdNodeRotateSecret
 )
 
 func init() {
 	rootCmd.AddCommand(nodeCmd)
+	nodeCmd.AddCommand(nodeConfigCmd)
 	nodeCmd.AddCommand(nodePrintCmd)
 	nodeCmd.AddCommand(nodeScanCmd)
 
 	cmdNodeChecks.Init(nodeCmd)
+	cmdNodeConfigUpgrade.Init(nodeConfigCmd)
+	cmdNodeDoc.Init("node", nodeCmd)
 	cmdNodeLs.Init(nodeCmd)
 	cmdNodePrintCapabilities.Init(nodePrintCmd)
 	cmdNodeScanCapabilities.Init(nodeScanCmd)
+	cmdNodeRotateSecret.Init(nodeCmd)
 }
@@ -20,12 +20,14 @@ var (
 
 func init() {
 	var (
+		cmdPoolGC     commands.PoolGC
 		cmdPoolLs     commands.PoolLs
 		cmdPoolStatus commands.PoolStatus
 	)
 	rootCmd.AddCommand(poolCmd)
 	poolCmd.AddCommand(poolCreateCmd)
 
+	cmdPoolGC.Init(poolCmd)
 	cmdPoolLs.Init(poolCmd)
 	cmdPoolStatus.Init(poolCmd)
 }
@@ -0,0 +1,10 @@
+package cmd
+
+import (
+	"opensvc.com/opensvc/core/commands"
+)
+
+func init() {
+	var cmdSchema commands.CmdSchema
+	cmdSchema.Init(rootCmd)
+}
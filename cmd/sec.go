@@ -30,12 +30,26 @@ when installing the key in a volume.`,
 		Short:   "print information about the object",
 		Aliases: []string{"prin", "pri", "pr"},
 	}
+	subSecCert = &cobra.Command{
+		Use:   "cert",
+		Short: "manage the x509 certificate stored as a keyset",
+	}
+	subSecGen = &cobra.Command{
+		Use:   "gen",
+		Short: "generate x509 material from the object keywords",
+	}
+	subSecImport = &cobra.Command{
+		Use:   "import",
+		Short: "import x509 material",
+	}
 )
 
 func init() {
 	var (
+		cmdApply            commands.CmdObjectApply
 		cmdCreate           commands.CmdObjectCreate
 		cmdDelete           commands.CmdObjectDelete
+		cmdDoc              commands.CmdKeywordDoc
 		cmdEdit             commands.CmdObjectEdit
 		cmdEditConfig       commands.CmdObjectEditConfig
 		cmdEval             commands.CmdObjectEval
@@ -55,6 +69,13 @@ func init() {
 		cmdKeys    commands.CmdKeystoreKeys
 		cmdRemove  commands.CmdKeystoreRemove
 		cmdGenCert commands.CmdSecGenCert
+		cmdRekey   commands.CmdSecRekey
+
+		cmdCertInfo   commands.CmdSecCertInfo
+		cmdCertRenew  commands.CmdSecCertRenew
+		cmdExport     commands.CmdSecExport
+		cmdGenCSR     commands.CmdSecGenCSR
+		cmdImportCert commands.CmdSecImportCert
 	)
 
 	kind := "sec"
@@ -64,23 +85,34 @@ func init() {
 
 	root.AddCommand(head)
 	head.AddCommand(subPrint)
+	head.AddCommand(subSecCert)
+	head.AddCommand(subSecGen)
+	head.AddCommand(subSecImport)
 
 	cmdAdd.Init(kind, head, &selectorFlag)
+	cmdCertInfo.Init(kind, subSecCert, &selectorFlag)
+	cmdCertRenew.Init(kind, subSecCert, &selectorFlag)
 	cmdChange.Init(kind, head, &selectorFlag)
+	cmdApply.Init(kind, head, &selectorFlag)
 	cmdCreate.Init(kind, head, &selectorFlag)
 	cmdDelete.Init(kind, head, &selectorFlag)
+	cmdDoc.Init(kind, head)
 	cmdDecode.Init(kind, head, &selectorFlag)
 	cmdEdit.Init(kind, head, &selectorFlag)
 	cmdEditConfig.Init(kind, cmdEdit.Command, &selectorFlag)
 	cmdEval.Init(kind, head, &selectorFlag)
+	cmdExport.Init(kind, head, &selectorFlag)
 	cmdGenCert.Init(kind, head, &selectorFlag)
+	cmdGenCSR.Init(kind, subSecGen, &selectorFlag)
 	cmdGet.Init(kind, head, &selectorFlag)
+	cmdImportCert.Init(kind, subSecImport, &selectorFlag)
 	cmdKeys.Init(kind, head, &selectorFlag)
 	cmdLs.Init(kind, head, &selectorFlag)
 	cmdMonitor.Init(kind, head, &selectorFlag)
 	cmdPrintConfig.Init(kind, subPrint, &selectorFlag)
 	cmdPrintConfigMtime.Init(kind, cmdPrintConfig.Command, &selectorFlag)
 	cmdPrintStatus.Init(kind, subPrint, &selectorFlag)
+	cmdRekey.Init(kind, head, &selectorFlag)
 	cmdRemove.Init(kind, head, &selectorFlag)
 	cmdSet.Init(kind, head, &selectorFlag)
 	cmdStatus.Init(kind, head, &selectorFlag)
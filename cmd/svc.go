@@ -6,12 +6,21 @@ import (
 )
 
 var (
+	subSvcConfig = &cobra.Command{
+		Use:   "config",
+		Short: "manage the object configuration revisions and upgrades",
+	}
 	subSvcEdit = &cobra.Command{
 		Use:     "edit",
 		Short:   "edit information about the object",
 		Aliases: []string{"edi", "ed"},
 	}
 
+	subSvcValidate = &cobra.Command{
+		Use:     "validate",
+		Short:   "validate the object",
+		Aliases: []string{"validat", "valida", "valid", "val"},
+	}
 	subSvcPrint = &cobra.Command{
 		Use:     "print",
 		Short:   "print information about the object",
@@ -32,8 +41,14 @@ isolate lifecycles or to abstract cluster-specific knowledge.
 
 func init() {
 	var (
+		cmdApply            commands.CmdObjectApply
+		cmdConfigDiff       commands.CmdObjectConfigDiff
+		cmdConfigHistory    commands.CmdObjectConfigHistory
+		cmdConfigRestore    commands.CmdObjectConfigRestore
+		cmdConfigUpgrade    commands.CmdObjectConfigUpgrade
 		cmdCreate           commands.CmdObjectCreate
 		cmdDelete           commands.CmdObjectDelete
+		cmdDoc              commands.CmdKeywordDoc
 		cmdEditConfig       commands.CmdObjectEditConfig
 		cmdEval             commands.CmdObjectEval
 		cmdFreeze           commands.CmdObjectFreeze
@@ -45,6 +60,8 @@ func init() {
 		cmdPrintStatus      commands.CmdObjectPrintStatus
 		cmdPrintSchedule    commands.CmdObjectPrintSchedule
 		cmdProvision        commands.CmdObjectProvision
+		cmdRefreshData      commands.CmdObjectRefreshData
+		cmdResize           commands.CmdObjectResize
 		cmdSet              commands.CmdObjectSet
 		cmdStart            commands.CmdObjectStart
 		cmdStatus           commands.CmdObjectStatus
@@ -52,20 +69,31 @@ func init() {
 		cmdUnfreeze         commands.CmdObjectUnfreeze
 		cmdUnprovision      commands.CmdObjectUnprovision
 		cmdUnset            commands.CmdObjectUnset
+		cmdValidateConfig   commands.CmdObjectValidateConfig
 	)
 
 	kind := "svc"
 	head := subSvc
+	subConfig := subSvcConfig
 	subEdit := subSvcEdit
 	subPrint := subSvcPrint
+	subValidate := subSvcValidate
 	root := rootCmd
 
 	root.AddCommand(head)
+	head.AddCommand(subConfig)
 	head.AddCommand(subEdit)
 	head.AddCommand(subPrint)
+	head.AddCommand(subValidate)
 
+	cmdApply.Init(kind, head, &selectorFlag)
+	cmdConfigDiff.Init(kind, subConfig, &selectorFlag)
+	cmdConfigHistory.Init(kind, subConfig, &selectorFlag)
+	cmdConfigRestore.Init(kind, subConfig, &selectorFlag)
+	cmdConfigUpgrade.Init(kind, subConfig, &selectorFlag)
 	cmdCreate.Init(kind, head, &selectorFlag)
 	cmdDelete.Init(kind, head, &selectorFlag)
+	cmdDoc.Init(kind, head)
 	cmdEditConfig.Init(kind, subEdit, &selectorFlag)
 	cmdEval.Init(kind, head, &selectorFlag)
 	cmdFreeze.Init(kind, head, &selectorFlag)
@@ -77,6 +105,8 @@ func init() {
 	cmdPrintStatus.Init(kind, subPrint, &selectorFlag)
 	cmdPrintSchedule.Init(kind, subPrint, &selectorFlag)
 	cmdProvision.Init(kind, head, &selectorFlag)
+	cmdRefreshData.Init(kind, head, &selectorFlag)
+	cmdResize.Init(kind, head, &selectorFlag)
 	cmdSet.Init(kind, head, &selectorFlag)
 	cmdStart.Init(kind, head, &selectorFlag)
 	cmdStatus.Init(kind, head, &selectorFlag)
@@ -84,4 +114,5 @@ func init() {
 	cmdUnfreeze.Init(kind, head, &selectorFlag)
 	cmdUnprovision.Init(kind, head, &selectorFlag)
 	cmdUnset.Init(kind, head, &selectorFlag)
+	cmdValidateConfig.Init(kind, subValidate, &selectorFlag)
 }
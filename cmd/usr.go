@@ -19,12 +19,18 @@ grants are embedded in the trusted bearer tokens.`,
 		Short:   "print information about the object",
 		Aliases: []string{"prin", "pri", "pr"},
 	}
+	subUsrToken = &cobra.Command{
+		Use:   "token",
+		Short: "manage the user bearer tokens",
+	}
 )
 
 func init() {
 	var (
+		cmdApply            commands.CmdObjectApply
 		cmdCreate           commands.CmdObjectCreate
 		cmdDelete           commands.CmdObjectDelete
+		cmdDoc              commands.CmdKeywordDoc
 		cmdEdit             commands.CmdObjectEdit
 		cmdEditConfig       commands.CmdObjectEditConfig
 		cmdEval             commands.CmdObjectEval
@@ -36,6 +42,7 @@ func init() {
 		cmdPrintStatus      commands.CmdObjectPrintStatus
 		cmdSet              commands.CmdObjectSet
 		cmdStatus           commands.CmdObjectStatus
+		cmdTokenCreate      commands.CmdUsrTokenCreate
 		cmdUnset            commands.CmdObjectUnset
 	)
 
@@ -46,9 +53,12 @@ func init() {
 
 	root.AddCommand(head)
 	head.AddCommand(subPrint)
+	head.AddCommand(subUsrToken)
 
+	cmdApply.Init(kind, head, &selectorFlag)
 	cmdCreate.Init(kind, head, &selectorFlag)
 	cmdDelete.Init(kind, head, &selectorFlag)
+	cmdDoc.Init(kind, head)
 	cmdEdit.Init(kind, head, &selectorFlag)
 	cmdEditConfig.Init(kind, cmdEdit.Command, &selectorFlag)
 	cmdEval.Init(kind, head, &selectorFlag)
@@ -60,5 +70,6 @@ func init() {
 	cmdPrintStatus.Init(kind, subPrint, &selectorFlag)
 	cmdSet.Init(kind, head, &selectorFlag)
 	cmdStatus.Init(kind, head, &selectorFlag)
+	cmdTokenCreate.Init(kind, subUsrToken, &selectorFlag)
 	cmdUnset.Init(kind, head, &selectorFlag)
 }
@@ -18,22 +18,44 @@ Volumes and their subdirectories can be mounted inside containers.
 
 A volume can host cfg and sec keys projections.`,
 	}
+	subVolConfig = &cobra.Command{
+		Use:   "config",
+		Short: "manage the object configuration revisions and upgrades",
+	}
 	subVolEdit = &cobra.Command{
 		Use:     "edit",
 		Short:   "edit information about the object",
 		Aliases: []string{"edi", "ed"},
 	}
+	subVolValidate = &cobra.Command{
+		Use:     "validate",
+		Short:   "validate the object",
+		Aliases: []string{"validat", "valida", "valid", "val"},
+	}
 	subVolPrint = &cobra.Command{
 		Use:     "print",
 		Short:   "print information about the object",
 		Aliases: []string{"prin", "pri", "pr"},
 	}
+	subVolSnapshot = &cobra.Command{
+		Use:   "snapshot",
+		Short: "manage the volume snapshots",
+		Long: `Volume snapshots are handled by the pool the volume was allocated from.
+
+Only the pool types advertising the snap capability support snapshots.`,
+	}
 )
 
 func init() {
 	var (
+		cmdApply            commands.CmdObjectApply
+		cmdConfigDiff       commands.CmdObjectConfigDiff
+		cmdConfigHistory    commands.CmdObjectConfigHistory
+		cmdConfigRestore    commands.CmdObjectConfigRestore
+		cmdConfigUpgrade    commands.CmdObjectConfigUpgrade
 		cmdCreate           commands.CmdObjectCreate
 		cmdDelete           commands.CmdObjectDelete
+		cmdDoc              commands.CmdKeywordDoc
 		cmdEditConfig       commands.CmdObjectEditConfig
 		cmdEval             commands.CmdObjectEval
 		cmdFreeze           commands.CmdObjectFreeze
@@ -45,27 +67,44 @@ func init() {
 		cmdPrintStatus      commands.CmdObjectPrintStatus
 		cmdPrintSchedule    commands.CmdObjectPrintSchedule
 		cmdProvision        commands.CmdObjectProvision
+		cmdResize           commands.CmdObjectResize
 		cmdSet              commands.CmdObjectSet
+		cmdSnapshotCreate   commands.CmdVolSnapshotCreate
+		cmdSnapshotDelete   commands.CmdVolSnapshotDelete
+		cmdSnapshotLs       commands.CmdVolSnapshotLs
+		cmdSnapshotRollback commands.CmdVolSnapshotRollback
 		cmdStart            commands.CmdObjectStart
 		cmdStatus           commands.CmdObjectStatus
 		cmdStop             commands.CmdObjectStop
 		cmdUnfreeze         commands.CmdObjectUnfreeze
 		cmdUnprovision      commands.CmdObjectUnprovision
 		cmdUnset            commands.CmdObjectUnset
+		cmdValidateConfig   commands.CmdObjectValidateConfig
 	)
 
 	kind := "vol"
 	head := subVol
+	subConfig := subVolConfig
 	subEdit := subVolEdit
 	subPrint := subVolPrint
+	subValidate := subVolValidate
 	root := rootCmd
 
 	root.AddCommand(head)
+	head.AddCommand(subConfig)
 	head.AddCommand(subEdit)
 	head.AddCommand(subPrint)
+	head.AddCommand(subValidate)
+	head.AddCommand(subVolSnapshot)
 
+	cmdApply.Init(kind, head, &selectorFlag)
+	cmdConfigDiff.Init(kind, subConfig, &selectorFlag)
+	cmdConfigHistory.Init(kind, subConfig, &selectorFlag)
+	cmdConfigRestore.Init(kind, subConfig, &selectorFlag)
+	cmdConfigUpgrade.Init(kind, subConfig, &selectorFlag)
 	cmdCreate.Init(kind, head, &selectorFlag)
 	cmdDelete.Init(kind, head, &selectorFlag)
+	cmdDoc.Init(kind, head)
 	cmdEditConfig.Init(kind, subEdit, &selectorFlag)
 	cmdEval.Init(kind, head, &selectorFlag)
 	cmdFreeze.Init(kind, head, &selectorFlag)
@@ -77,11 +116,17 @@ func init() {
 	cmdPrintStatus.Init(kind, subPrint, &selectorFlag)
 	cmdPrintSchedule.Init(kind, subPrint, &selectorFlag)
 	cmdProvision.Init(kind, head, &selectorFlag)
+	cmdResize.Init(kind, head, &selectorFlag)
 	cmdSet.Init(kind, head, &selectorFlag)
+	cmdSnapshotCreate.Init(kind, subVolSnapshot, &selectorFlag)
+	cmdSnapshotDelete.Init(kind, subVolSnapshot, &selectorFlag)
+	cmdSnapshotLs.Init(kind, subVolSnapshot, &selectorFlag)
+	cmdSnapshotRollback.Init(kind, subVolSnapshot, &selectorFlag)
 	cmdStart.Init(kind, head, &selectorFlag)
 	cmdStatus.Init(kind, head, &selectorFlag)
 	cmdStop.Init(kind, head, &selectorFlag)
 	cmdUnfreeze.Init(kind, head, &selectorFlag)
 	cmdUnprovision.Init(kind, head, &selectorFlag)
 	cmdUnset.Init(kind, head, &selectorFlag)
+	cmdValidateConfig.Init(kind, subValidate, &selectorFlag)
 }
@@ -18,6 +18,7 @@ type (
 		insecureSkipVerify bool
 		clientCertificate  string
 		clientKey          string
+		bearerToken        string
 		requester          api.Requester
 	}
 )
@@ -100,6 +101,16 @@ func WithKey(s string) funcopt.O {
 	})
 }
 
+// WithBearerToken sets the token sent in the Authorization header of the
+// http/2 requests, like a token issued by "usr token create".
+func WithBearerToken(s string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.bearerToken = s
+		return nil
+	})
+}
+
 // configure allocates a new requester with a requester for the server found in Config,
 // or for the server found in Context.
 func (t *T) configure() error {
@@ -133,14 +144,14 @@ func (t *T) newRequester() (err error) {
 	case strings.HasPrefix(t.url, reqjsonrpc.InetPrefix):
 		t.requester, err = reqjsonrpc.New(t.url)
 	case strings.HasPrefix(t.url, reqh2.UDSPrefix):
-		t.requester, err = reqh2.NewUDS(t.url)
+		t.requester, err = reqh2.NewUDS(t.url, t.bearerToken)
 	case strings.HasSuffix(t.url, "h2.sock"):
-		t.requester, err = reqh2.NewUDS(t.url)
+		t.requester, err = reqh2.NewUDS(t.url, t.bearerToken)
 	case strings.HasPrefix(t.url, reqh2.InetPrefix):
-		t.requester, err = reqh2.NewInet(t.url, t.clientCertificate, t.clientKey, t.insecureSkipVerify)
+		t.requester, err = reqh2.NewInet(t.url, t.clientCertificate, t.clientKey, t.insecureSkipVerify, t.bearerToken)
 	default:
 		t.url = ""
-		t.requester, err = reqh2.NewUDS(t.url)
+		t.requester, err = reqh2.NewUDS(t.url, t.bearerToken)
 	}
 	return err
 }
@@ -155,6 +166,9 @@ func (t *T) loadContext() error {
 		t.insecureSkipVerify = context.Cluster.InsecureSkipVerify
 		t.clientCertificate = context.User.ClientCertificate
 		t.clientKey = context.User.ClientKey
+		if t.bearerToken == "" {
+			t.bearerToken = context.User.Token
+		}
 	}
 	return nil
 }
@@ -23,8 +23,9 @@ import (
 type (
 	// T is the agent HTTP/2 requester
 	T struct {
-		Client http.Client `json:"-"`
-		URL    string      `json:"url"`
+		Client      http.Client `json:"-"`
+		URL         string      `json:"url"`
+		BearerToken string      `json:"-"`
 	}
 )
 
@@ -47,11 +48,11 @@ func defaultUDSPath() string {
 	return filepath.FromSlash(fmt.Sprintf("%s/lsnr/h2.sock", rawconfig.Node.Paths.Var))
 }
 
-func NewUDS(url string) (*T, error) {
+func NewUDS(url, bearerToken string) (*T, error) {
 	if url == "" {
 		url = defaultUDSPath()
 	}
-	r := &T{}
+	r := &T{BearerToken: bearerToken}
 	tp := &http2.Transport{
 		AllowHTTP: true,
 		DialTLS: func(network, addr string, cfg *tls.Config) (con net.Conn, err error) {
@@ -77,17 +78,22 @@ func NewUDS(url string) (*T, error) {
 	return r, nil
 }
 
-func NewInet(url, clientCertificate, clientKey string, insecureSkipVerify bool) (*T, error) {
-	r := &T{}
-	cer, err := tls.LoadX509KeyPair(clientCertificate, clientKey)
-	if err != nil {
-		return nil, err
+// NewInet returns a requester for the https api. The x509 client
+// certificate is optional if a bearer token is set.
+func NewInet(url, clientCertificate, clientKey string, insecureSkipVerify bool, bearerToken string) (*T, error) {
+	r := &T{BearerToken: bearerToken}
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+	if clientCertificate != "" || bearerToken == "" {
+		cer, err := tls.LoadX509KeyPair(clientCertificate, clientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cer}
 	}
 	tp := &http2.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: insecureSkipVerify,
-			Certificates:       []tls.Certificate{cer},
-		},
+		TLSClientConfig: tlsConfig,
 	}
 	r.URL = url
 	r.Client = http.Client{Transport: tp}
@@ -102,6 +108,9 @@ func (t T) newRequest(method string, r request.T) (*http.Request, error) {
 		return nil, err
 	}
 	req.Header.Set("o-node", r.Node)
+	if t.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+t.BearerToken)
+	}
 	return req, nil
 }
 
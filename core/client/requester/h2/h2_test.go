@@ -0,0 +1,22 @@
+package reqh2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/client/request"
+)
+
+func TestNewRequestBearerToken(t *testing.T) {
+	r := *request.New()
+	r.Action = "object_status"
+
+	req, err := T{URL: "https://localhost:1215", BearerToken: "abc"}.newRequest("GET", r)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer abc", req.Header.Get("Authorization"))
+
+	req, err = T{URL: "https://localhost:1215"}.newRequest("GET", r)
+	require.NoError(t, err)
+	assert.Empty(t, req.Header.Get("Authorization"))
+}
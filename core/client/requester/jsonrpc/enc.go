@@ -23,6 +23,10 @@ type (
 		NodeName    string
 		Key         string
 		Data        []byte
+
+		// AltKeys are the keys tried by Decrypt when Key fails, like the
+		// previous cluster secret during a secret rotation.
+		AltKeys []string
 	}
 	encryptedMessage struct {
 		ClusterName string `json:"clustername"`
@@ -38,6 +42,7 @@ func NewMessage(b []byte) *Message {
 		NodeName:    hostname.Hostname(),
 		ClusterName: rawconfig.Node.Cluster.Name,
 		Key:         rawconfig.Node.Cluster.Secret,
+		AltKeys:     rawconfig.Node.Cluster.Secrets()[1:],
 		Data:        b,
 	}
 	return m
@@ -46,14 +51,22 @@ func NewMessage(b []byte) *Message {
 // Decrypt decrypts the message, if the nodename found in the message is a
 // cluster node.
 func (m *Message) Decrypt() ([]byte, error) {
-	key := []byte(m.Key)
 	msg := &encryptedMessage{}
 	err := json.Unmarshal(m.Data, msg)
 	if err != nil {
 		return nil, err
 	}
 	// TODO: test nodename and clustername, plug blacklist
-	return decode(msg.Data, msg.IV, key)
+	b, err := decode(msg.Data, msg.IV, []byte(m.Key))
+	if err == nil {
+		return b, nil
+	}
+	for _, key := range m.AltKeys {
+		if b, altErr := decode(msg.Data, msg.IV, []byte(key)); altErr == nil {
+			return b, nil
+		}
+	}
+	return nil, err
 }
 
 // Encrypt encrypts the message and returns a json with head keys describing
@@ -44,10 +44,11 @@ type (
 	}
 
 	// user hosts the certificate and private to use to connect to the remote
-	// cluster.
+	// cluster, or the bearer token issued by "usr token create".
 	user struct {
 		ClientCertificate string `json:"client_certificate"`
 		ClientKey         string `json:"client_key"`
+		Token             string `json:"token,omitempty"`
 	}
 )
 
@@ -0,0 +1,147 @@
+package clusterdns
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/status"
+)
+
+func testStatus() cluster.Status {
+	newInstance := func(ipaddr string, st status.T, expose []interface{}) instance.Status {
+		return instance.Status{
+			Resources: map[string]resource.ExposedStatus{
+				"ip#0": {
+					Status: st,
+					Info: map[string]interface{}{
+						"ipaddr": ipaddr,
+						"expose": expose,
+					},
+				},
+				"fs#0": {
+					Status: status.Up,
+				},
+			},
+		}
+	}
+	data := cluster.Status{}
+	data.Cluster.Name = "Clu1"
+	data.Monitor.Nodes = map[string]cluster.NodeStatus{
+		"n1": {
+			Services: cluster.NodeServices{
+				Status: map[string]instance.Status{
+					"web":          newInstance("10.0.0.1", status.Up, []interface{}{"80/tcp:8080", "53/udp"}),
+					"ns1/svc/db":   newInstance("fd00::1", status.Up, nil),
+					"ns1/svc/down": newInstance("10.0.0.9", status.Down, nil),
+					"ns1/vol/v1":   newInstance("10.0.0.8", status.Up, nil),
+				},
+			},
+		},
+		"n2": {
+			Services: cluster.NodeServices{
+				Status: map[string]instance.Status{
+					"web": newInstance("10.0.0.2", status.Up, []interface{}{"80/tcp:8080"}),
+				},
+			},
+		},
+	}
+	return data
+}
+
+func TestNewZone(t *testing.T) {
+	z := NewZone(testStatus(), 1)
+	assert.Equal(t, "clu1.", z.Name)
+	assert.Equal(t, []Record{
+		{Type: "SRV", Name: "_53._udp.web.root.svc.clu1.", Content: "0 10 53 web.root.svc.clu1.", TTL: DefaultTTL},
+		{Type: "SRV", Name: "_80._tcp.web.root.svc.clu1.", Content: "0 10 80 web.root.svc.clu1.", TTL: DefaultTTL},
+		{Type: "AAAA", Name: "db.ns1.svc.clu1.", Content: "fd00::1", TTL: DefaultTTL},
+		{Type: "A", Name: "web.root.svc.clu1.", Content: "10.0.0.1", TTL: DefaultTTL},
+		{Type: "A", Name: "web.root.svc.clu1.", Content: "10.0.0.2", TTL: DefaultTTL},
+	}, z.Records)
+
+	assert.Len(t, z.Lookup("WEB.root.svc.clu1", "A"), 2)
+	assert.Len(t, z.Lookup("web.root.svc.clu1.", "ANY"), 2)
+	assert.Len(t, z.Lookup("web.root.svc.clu1.", "AAAA"), 0)
+	assert.Len(t, z.Lookup("down.ns1.svc.clu1.", "ANY"), 0)
+	soa := z.Lookup("clu1.", "SOA")
+	require.Len(t, soa, 1)
+	assert.Equal(t, "ns.clu1. hostmaster.clu1. 1 7200 3600 432000 60", soa[0].Content)
+}
+
+func TestServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clusterdns")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "dns", "pdns.sock")
+
+	srv := New(
+		WithSocket(socket),
+		WithStatusGetter(func() (cluster.Status, error) { return testStatus(), nil }),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- srv.ListenAndServe(ctx) }()
+
+	var conn net.Conn
+	for i := 0; i < 50; i++ {
+		if conn, err = net.Dial("unix", socket); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.NoError(t, err)
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	call := func(req string) map[string]interface{} {
+		_, err := conn.Write([]byte(req + "\n"))
+		require.NoError(t, err)
+		var resp map[string]interface{}
+		require.NoError(t, json.NewDecoder(r).Decode(&resp))
+		return resp
+	}
+
+	resp := call(`{"method": "initialize", "parameters": {"path": "` + socket + `", "timeout": "2000"}}`)
+	assert.Equal(t, true, resp["result"])
+
+	resp = call(`{"method": "lookup", "parameters": {"qtype": "A", "qname": "web.root.svc.clu1.", "remote": "127.0.0.1", "zone-id": -1}}`)
+	l, ok := resp["result"].([]interface{})
+	require.True(t, ok)
+	assert.Len(t, l, 2)
+
+	resp = call(`{"method": "lookup", "parameters": {"qtype": "SRV", "qname": "_80._tcp.web.root.svc.clu1."}}`)
+	l, ok = resp["result"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, l, 1)
+	assert.Equal(t, "0 10 80 web.root.svc.clu1.", l[0].(map[string]interface{})["content"])
+
+	resp = call(`{"method": "getAllDomains", "parameters": {"include_disabled": true}}`)
+	l, ok = resp["result"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, l, 1)
+	assert.Equal(t, "clu1.", l[0].(map[string]interface{})["zone"])
+
+	resp = call(`{"method": "unknownMethod", "parameters": {}}`)
+	assert.Equal(t, false, resp["result"])
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not stop")
+	}
+}
@@ -0,0 +1,268 @@
+// Package clusterdns serves the cluster dns zone to a PowerDNS server
+// configured with a remote backend using the unix socket connector.
+//
+// The zone contains the <name>.<namespace>.svc.<clustername> A and AAAA
+// records of the up ip resources of the svc instances, and the
+// _<port>._<proto>.<name>.<namespace>.svc.<clustername> SRV records of
+// their expose keyword elements.
+//
+// Example PowerDNS configuration:
+//
+// launch=remote
+// remote-connection-string=unix:path=/var/lib/opensvc/dns/pdns.sock
+//
+package clusterdns
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	// StatusGetter returns the cluster status the zone is built from.
+	StatusGetter func() (cluster.Status, error)
+
+	// T is the dns backend server.
+	T struct {
+		socket   string
+		getter   StatusGetter
+		interval time.Duration
+		log      zerolog.Logger
+
+		mu       sync.RWMutex
+		zone     *Zone
+		listener net.Listener
+	}
+
+	request struct {
+		Method     string                 `json:"method"`
+		Parameters map[string]interface{} `json:"parameters"`
+	}
+
+	response struct {
+		Result interface{} `json:"result"`
+		Log    []string    `json:"log,omitempty"`
+	}
+
+	domainInfo struct {
+		ID             int    `json:"id"`
+		Zone           string `json:"zone"`
+		Kind           string `json:"kind"`
+		Serial         uint32 `json:"serial"`
+		NotifiedSerial uint32 `json:"notified_serial"`
+	}
+)
+
+const (
+	DefaultInterval = 5 * time.Second
+)
+
+// New allocates a dns backend server.
+func New(opts ...funcopt.O) *T {
+	t := &T{
+		interval: DefaultInterval,
+		log:      zerolog.Nop(),
+		zone:     &Zone{Records: []Record{}},
+	}
+	_ = funcopt.Apply(t, opts...)
+	return t
+}
+
+// WithSocket sets the path of the unix socket to listen on.
+func WithSocket(s string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.socket = s
+		return nil
+	})
+}
+
+// WithStatusGetter sets the function returning the cluster status.
+func WithStatusGetter(f StatusGetter) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.getter = f
+		return nil
+	})
+}
+
+// WithInterval sets the zone refresh interval.
+func WithInterval(d time.Duration) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.interval = d
+		return nil
+	})
+}
+
+// WithLogger sets the server logger.
+func WithLogger(l zerolog.Logger) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.log = l
+		return nil
+	})
+}
+
+// Zone returns the zone currently served.
+func (t *T) Zone() *Zone {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.zone
+}
+
+// Refresh rebuilds the zone from the cluster status.
+func (t *T) Refresh() error {
+	if t.getter == nil {
+		return nil
+	}
+	data, err := t.getter()
+	if err != nil {
+		return err
+	}
+	zone := NewZone(data, uint32(time.Now().Unix()))
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if zone.Equal(t.zone) {
+		return nil
+	}
+	if zone.Serial <= t.zone.Serial {
+		zone.Serial = t.zone.Serial + 1
+	}
+	t.log.Debug().Msgf("zone %s updated: %d records, serial %d", zone.Name, len(zone.Records), zone.Serial)
+	t.zone = zone
+	return nil
+}
+
+// ListenAndServe listens on the unix socket and serves the backend
+// requests until the context is done.
+func (t *T) ListenAndServe(ctx context.Context) error {
+	if err := os.MkdirAll(filepath.Dir(t.socket), 0700); err != nil {
+		return err
+	}
+	if err := os.Remove(t.socket); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	l, err := net.Listen("unix", t.socket)
+	if err != nil {
+		return err
+	}
+	return t.Serve(ctx, l)
+}
+
+// Serve serves the backend requests received on the listener until the
+// context is done.
+func (t *T) Serve(ctx context.Context, l net.Listener) error {
+	t.mu.Lock()
+	t.listener = l
+	t.mu.Unlock()
+	if err := t.Refresh(); err != nil {
+		t.log.Error().Err(err).Msg("zone refresh")
+	}
+	go t.refreshLoop(ctx)
+	go func() {
+		<-ctx.Done()
+		_ = l.Close()
+	}()
+	t.log.Info().Msgf("listen on %s", l.Addr())
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			default:
+				return err
+			}
+		}
+		go t.handle(conn)
+	}
+}
+
+func (t *T) refreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.Refresh(); err != nil {
+				t.log.Error().Err(err).Msg("zone refresh")
+			}
+		}
+	}
+}
+
+func (t *T) handle(conn net.Conn) {
+	defer conn.Close()
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+	for {
+		var req request
+		if err := dec.Decode(&req); err != nil {
+			if err != io.EOF {
+				t.log.Debug().Err(err).Msg("decode request")
+			}
+			return
+		}
+		if err := enc.Encode(t.Do(req.Method, req.Parameters)); err != nil {
+			t.log.Debug().Err(err).Msg("encode response")
+			return
+		}
+	}
+}
+
+// Do returns the response to a remote backend method call.
+func (t *T) Do(method string, parameters map[string]interface{}) response {
+	zone := t.Zone()
+	switch method {
+	case "initialize":
+		return response{Result: true}
+	case "lookup":
+		qname, _ := parameters["qname"].(string)
+		qtype, _ := parameters["qtype"].(string)
+		return response{Result: zone.Lookup(qname, qtype)}
+	case "list":
+		name, _ := parameters["zonename"].(string)
+		if !strings.EqualFold(name, zone.Name) && !strings.EqualFold(name+".", zone.Name) {
+			return response{Result: false}
+		}
+		return response{Result: zone.All()}
+	case "getAllDomains":
+		return response{Result: []domainInfo{zone.domainInfo()}}
+	case "getDomainInfo":
+		name, _ := parameters["name"].(string)
+		if !strings.EqualFold(name, zone.Name) && !strings.EqualFold(name+".", zone.Name) {
+			return response{Result: false}
+		}
+		return response{Result: zone.domainInfo()}
+	case "getAllDomainMetadata":
+		return response{Result: map[string][]string{}}
+	case "getDomainMetadata":
+		return response{Result: []string{}}
+	default:
+		return response{Result: false}
+	}
+}
+
+func (t Zone) domainInfo() domainInfo {
+	return domainInfo{
+		ID:             1,
+		Zone:           t.Name,
+		Kind:           "native",
+		Serial:         t.Serial,
+		NotifiedSerial: t.Serial,
+	}
+}
@@ -0,0 +1,205 @@
+package clusterdns
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/expose"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/status"
+)
+
+type (
+	// Record is a resource record, formatted as expected by the
+	// PowerDNS remote backend.
+	Record struct {
+		Type    string `json:"qtype"`
+		Name    string `json:"qname"`
+		Content string `json:"content"`
+		TTL     int    `json:"ttl"`
+	}
+
+	// Zone is the list of records served for a cluster. The SOA record
+	// is not included in Records.
+	Zone struct {
+		Name    string
+		Serial  uint32
+		Records []Record
+	}
+)
+
+const (
+	// DefaultTTL is the ttl of the served records. It is short, as the
+	// records follow the instances placement.
+	DefaultTTL = 60
+)
+
+// ZoneName returns the dns zone name of a cluster.
+func ZoneName(clusterName string) string {
+	return strings.ToLower(clusterName) + "."
+}
+
+// ServiceName returns the fqdn of the A/AAAA records of an object.
+func ServiceName(p path.T, clusterName string) string {
+	ns := p.Namespace
+	if ns == "" {
+		ns = "root"
+	}
+	return strings.ToLower(fmt.Sprintf("%s.%s.svc.%s", p.Name, ns, ZoneName(clusterName)))
+}
+
+// NewZone returns the zone built from the cluster status. Each up ip
+// resource of the svc instances produces a A or AAAA record, and each
+// element of its expose keyword a SRV record.
+func NewZone(data cluster.Status, serial uint32) *Zone {
+	z := &Zone{
+		Name:    ZoneName(data.Cluster.Name),
+		Serial:  serial,
+		Records: make([]Record, 0),
+	}
+	seen := make(map[Record]bool)
+	add := func(r Record) {
+		if seen[r] {
+			return
+		}
+		seen[r] = true
+		z.Records = append(z.Records, r)
+	}
+	for _, ndata := range data.Monitor.Nodes {
+		for ps, idata := range ndata.Services.Status {
+			p, err := path.Parse(ps)
+			if err != nil || p.Kind != kind.Svc {
+				continue
+			}
+			name := ServiceName(p, data.Cluster.Name)
+			for rid, rdata := range idata.Resources {
+				if !strings.HasPrefix(rid, "ip#") || rdata.Status != status.Up {
+					continue
+				}
+				ip := infoIP(rdata.Info)
+				if ip == nil {
+					continue
+				}
+				add(addrRecord(name, ip))
+				for _, e := range infoExpose(rdata.Info) {
+					add(srvRecord(name, e))
+				}
+			}
+		}
+	}
+	sort.Slice(z.Records, func(i, j int) bool {
+		a, b := z.Records[i], z.Records[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Content < b.Content
+	})
+	return z
+}
+
+func (t Zone) soa() Record {
+	return Record{
+		Type:    "SOA",
+		Name:    t.Name,
+		Content: fmt.Sprintf("ns.%s hostmaster.%s %d 7200 3600 432000 %d", t.Name, t.Name, t.Serial, DefaultTTL),
+		TTL:     DefaultTTL,
+	}
+}
+
+// All returns the zone records, including the SOA record.
+func (t Zone) All() []Record {
+	return append([]Record{t.soa()}, t.Records...)
+}
+
+// Equal returns true if the zones have the same name and records.
+func (t Zone) Equal(o *Zone) bool {
+	if o == nil || t.Name != o.Name || len(t.Records) != len(o.Records) {
+		return false
+	}
+	for i := range t.Records {
+		if t.Records[i] != o.Records[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Lookup returns the records matching the query name and type. The
+// ANY type matches all records of the name.
+func (t Zone) Lookup(qname, qtype string) []Record {
+	l := make([]Record, 0)
+	qname = strings.ToLower(qname)
+	if !strings.HasSuffix(qname, ".") {
+		qname += "."
+	}
+	qtype = strings.ToUpper(qtype)
+	for _, r := range t.All() {
+		if r.Name != qname {
+			continue
+		}
+		if qtype != "ANY" && qtype != r.Type {
+			continue
+		}
+		l = append(l, r)
+	}
+	return l
+}
+
+func addrRecord(name string, ip net.IP) Record {
+	r := Record{
+		Name:    name,
+		Content: ip.String(),
+		TTL:     DefaultTTL,
+	}
+	if ip.To4() != nil {
+		r.Type = "A"
+	} else {
+		r.Type = "AAAA"
+	}
+	return r
+}
+
+func srvRecord(name string, e expose.T) Record {
+	return Record{
+		Type:    "SRV",
+		Name:    fmt.Sprintf("_%d._%s.%s", e.Port, e.Protocol, name),
+		Content: fmt.Sprintf("0 10 %d %s", e.Port, name),
+		TTL:     DefaultTTL,
+	}
+}
+
+func infoIP(info map[string]interface{}) net.IP {
+	s, ok := info["ipaddr"].(string)
+	if !ok {
+		return nil
+	}
+	return net.ParseIP(s)
+}
+
+func infoExpose(info map[string]interface{}) expose.L {
+	l := make([]string, 0)
+	switch v := info["expose"].(type) {
+	case []string:
+		l = v
+	case []interface{}:
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				l = append(l, s)
+			}
+		}
+	}
+	exposes := make(expose.L, 0)
+	for _, s := range l {
+		if e, err := expose.Parse(s); err == nil {
+			exposes = append(exposes, e)
+		}
+	}
+	return exposes
+}
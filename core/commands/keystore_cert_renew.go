@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdSecCertRenew is the cobra flag set of the cert renew command.
+	CmdSecCertRenew struct {
+		object.OptsCertRenew
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdSecCertRenew) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsCertRenew)
+}
+
+func (t *CmdSecCertRenew) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "renew",
+		Short: "renew the x509 certificate stored as a keyset",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdSecCertRenew) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.Global.NodeSelector),
+		objectaction.WithRemoteAction("cert renew"),
+		objectaction.WithRemoteOptions(map[string]interface{}{
+			"if_expires_within": t.IfExpiresWithin,
+			"force":             t.Force,
+		}),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			return nil, object.NewFromPath(p).(object.SecureKeystorer).CertRenew(t.OptsCertRenew)
+		}),
+	).Do()
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdSecExport is the cobra flag set of the export command.
+	CmdSecExport struct {
+		object.OptsExport
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdSecExport) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsExport)
+}
+
+func (t *CmdSecExport) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "export",
+		Short: "export the private key and certificate chain for use by other applications",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdSecExport) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.Global.NodeSelector),
+		objectaction.WithRemoteAction("export"),
+		objectaction.WithRemoteOptions(map[string]interface{}{
+			"format":   t.Format,
+			"password": t.Password,
+		}),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			return object.NewFromPath(p).(object.SecureKeystorer).Export(t.OptsExport)
+		}),
+	).Do()
+}
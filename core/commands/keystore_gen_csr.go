@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdSecGenCSR is the cobra flag set of the gen csr command.
+	CmdSecGenCSR struct {
+		object.OptsGenCSR
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdSecGenCSR) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsGenCSR)
+}
+
+func (t *CmdSecGenCSR) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "csr",
+		Short: "create a certificate signing request from the subject keywords",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdSecGenCSR) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.Global.NodeSelector),
+		objectaction.WithRemoteAction("gen csr"),
+		//objectaction.WithRemoteOptions(map[string]interface{}{}),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			return object.NewFromPath(p).(object.SecureKeystorer).GenCSR(t.OptsGenCSR)
+		}),
+	).Do()
+}
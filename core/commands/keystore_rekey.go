@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdSecRekey is the cobra flag set of the rekey command.
+	CmdSecRekey struct {
+		object.OptsRekey
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdSecRekey) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsRekey)
+}
+
+func (t *CmdSecRekey) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rekey",
+		Short: "re-encrypt the keys with the current cluster secret",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdSecRekey) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.Global.NodeSelector),
+		objectaction.WithRemoteAction("rekey"),
+		//objectaction.WithRemoteOptions(map[string]interface{}{}),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			return nil, object.NewFromPath(p).(object.SecureKeystorer).Rekey(t.OptsRekey)
+		}),
+	).Do()
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/output"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+type (
+	// CmdKeywordDoc is the cobra flag set of the doc command.
+	CmdKeywordDoc struct {
+		Color   string `flag:"color"`
+		Format  string `flag:"format"`
+		Keyword string `flag:"kw"`
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+// The kind is a object kind, node or cluster.
+func (t *CmdKeywordDoc) Init(kind string, parent *cobra.Command) {
+	cmd := t.cmd(kind)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdKeywordDoc) cmd(kind string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "doc",
+		Short: "print the documentation of a configuration keyword",
+		Long:  "The keyword section can be a driver group name, like fs in fs.mnt_opt, or a section name, like fs#1. The keyword is documented for each driver supporting it.",
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := t.run(kind); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func (t *CmdKeywordDoc) run(kind string) error {
+	schema, err := object.NewSchema(kind)
+	if err != nil {
+		return err
+	}
+	data := schema.Lookup(key.Parse(t.Keyword))
+	if len(data) == 0 {
+		return fmt.Errorf("%s: keyword not found in the %s configuration", t.Keyword, kind)
+	}
+	output.Renderer{
+		Format:        t.Format,
+		Color:         t.Color,
+		Data:          data,
+		HumanRenderer: func() string { return data.Render() },
+		Colorize:      rawconfig.Node.Colorize,
+	}.Print()
+	return nil
+}
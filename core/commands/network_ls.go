@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/output"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+type (
+	// NetworkLs is the cobra flag set of the command.
+	NetworkLs struct {
+		Global object.OptsGlobal
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NetworkLs) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *NetworkLs) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ls",
+		Short: "list the cluster networks",
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *NetworkLs) run() {
+	data := make([]string, 0)
+	for _, n := range object.NewNode().Networks() {
+		data = append(data, n.Name())
+	}
+	output.Renderer{
+		Format: t.Global.Format,
+		Color:  t.Global.Color,
+		Data:   data,
+		HumanRenderer: func() string {
+			s := ""
+			for _, e := range data {
+				s += e + "\n"
+			}
+			return s
+		},
+		Colorize: rawconfig.Node.Colorize,
+	}.Print()
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// NetworkSetup is the cobra flag set of the command.
+	NetworkSetup struct {
+		Global object.OptsGlobal
+		Name   string `flag:"networkname"`
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NetworkSetup) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *NetworkSetup) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "setup",
+		Short: "install the cni configuration files and the node settings of the networks",
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *NetworkSetup) run() {
+	if err := object.NewNode().SetupNetworks(t.Name); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/output"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+type (
+	// NetworkShow is the cobra flag set of the command.
+	NetworkShow struct {
+		Global object.OptsGlobal
+		Name   string `flag:"networkname"`
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NetworkShow) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *NetworkShow) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "show",
+		Short:   "show the cluster networks configuration",
+		Aliases: []string{"sho", "sh"},
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *NetworkShow) run() {
+	data := object.NewNode().ShowNetworksByName(t.Name)
+	output.Renderer{
+		Format:   t.Global.Format,
+		Color:    t.Global.Color,
+		Data:     data,
+		Colorize: rawconfig.Node.Colorize,
+		HumanRenderer: func() string {
+			return data.Render()
+		},
+	}.Print()
+}
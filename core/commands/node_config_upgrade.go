@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// CmdNodeConfigUpgrade is the cobra flag set of the node config upgrade command.
+	CmdNodeConfigUpgrade struct {
+		object.OptsNodeConfigUpgrade
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdNodeConfigUpgrade) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsNodeConfigUpgrade)
+}
+
+func (t *CmdNodeConfigUpgrade) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "upgrade",
+		Short: "rewrite the node and installed objects configurations to the current keyword names and value formats",
+		Long:  "The keys set with a deprecated keyword name are renamed, and the values written in a legacy format are converted. With --dry-run, the changes are displayed but not applied.",
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *CmdNodeConfigUpgrade) run() {
+	nodeaction.New(
+		nodeaction.WithLocal(t.Global.Local),
+		nodeaction.WithRemoteNodes(t.Global.NodeSelector),
+		nodeaction.WithFormat(t.Global.Format),
+		nodeaction.WithColor(t.Global.Color),
+		nodeaction.WithServer(t.Global.Server),
+		nodeaction.WithRemoteAction("config_upgrade"),
+		nodeaction.WithRemoteOptions(map[string]interface{}{
+			"dry-run": t.Global.DryRun,
+		}),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return object.NewNode().ConfigUpgrade(t.OptsNodeConfigUpgrade)
+		}),
+	).Do()
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// CmdNodeRotateSecret is the cobra flag set of the rotate-secret command.
+	CmdNodeRotateSecret struct {
+		object.OptsNodeRotateSecret
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdNodeRotateSecret) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsNodeRotateSecret)
+}
+
+func (t *CmdNodeRotateSecret) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate-secret",
+		Short: "Replace the cluster secret, still accepting the previous secret during a grace period",
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *CmdNodeRotateSecret) run() {
+	// generate the new secret here, so all selected nodes get the same.
+	if t.Secret == "" {
+		s, err := object.NewClusterSecret()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		t.Secret = s
+	}
+	nodeaction.New(
+		nodeaction.WithLocal(t.Global.Local),
+		nodeaction.WithRemoteNodes(t.Global.NodeSelector),
+		nodeaction.WithFormat(t.Global.Format),
+		nodeaction.WithColor(t.Global.Color),
+		nodeaction.WithServer(t.Global.Server),
+		nodeaction.WithRemoteAction("rotate-secret"),
+		nodeaction.WithRemoteOptions(map[string]interface{}{
+			"secret": t.Secret,
+			"grace":  t.Grace.String(),
+		}),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return nil, object.NewNode().RotateSecret(t.OptsNodeRotateSecret)
+		}),
+	).Do()
+}
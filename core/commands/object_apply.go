@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdObjectApply is the cobra flag set of the apply command.
+	CmdObjectApply struct {
+		object.OptsApply
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectApply) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsApply)
+}
+
+func (t *CmdObjectApply) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "apply",
+		Short: "converge the object configuration to a json or yaml document",
+		Long:  "The keys differing from the document are set, and the keys and sections not in the document are unset. The object id is preserved. The document is read from the local filesystem, so the action is always local.",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectApply) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(true),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			return object.NewFromPath(p).(object.Applier).Apply(t.OptsApply)
+		}),
+	).Do()
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdObjectConfigDiff is the cobra flag set of the config diff command.
+	CmdObjectConfigDiff struct {
+		object.OptsConfigDiff
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectConfigDiff) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsConfigDiff)
+}
+
+func (t *CmdObjectConfigDiff) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff",
+		Short: "show the key differences between two revisions of the object configuration",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectConfigDiff) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.Global.NodeSelector),
+		objectaction.WithRemoteAction("config_diff"),
+		objectaction.WithRemoteOptions(map[string]interface{}{
+			"rev": t.Revs,
+		}),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			return object.NewFromPath(p).(object.ConfigRevisioner).ConfigDiff(t.OptsConfigDiff)
+		}),
+	).Do()
+}
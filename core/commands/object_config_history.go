@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdObjectConfigHistory is the cobra flag set of the config history command.
+	CmdObjectConfigHistory struct {
+		object.OptsConfigHistory
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectConfigHistory) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsConfigHistory)
+}
+
+func (t *CmdObjectConfigHistory) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "history",
+		Short: "list the saved revisions of the object configuration",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectConfigHistory) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.Global.NodeSelector),
+		objectaction.WithRemoteAction("config_history"),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			return object.NewFromPath(p).(object.ConfigRevisioner).ConfigHistory(t.OptsConfigHistory)
+		}),
+	).Do()
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdObjectConfigRestore is the cobra flag set of the config restore command.
+	CmdObjectConfigRestore struct {
+		object.OptsConfigRestore
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectConfigRestore) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsConfigRestore)
+}
+
+func (t *CmdObjectConfigRestore) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore",
+		Short: "commit a saved revision of the object configuration",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectConfigRestore) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.Global.NodeSelector),
+		objectaction.WithRemoteAction("config_restore"),
+		objectaction.WithRemoteOptions(map[string]interface{}{
+			"rev": t.Rev,
+		}),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			return nil, object.NewFromPath(p).(object.ConfigRevisioner).ConfigRestore(t.OptsConfigRestore)
+		}),
+	).Do()
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdObjectConfigUpgrade is the cobra flag set of the config upgrade command.
+	CmdObjectConfigUpgrade struct {
+		object.OptsConfigUpgrade
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectConfigUpgrade) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsConfigUpgrade)
+}
+
+func (t *CmdObjectConfigUpgrade) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "upgrade",
+		Short: "rewrite the object configuration to the current keyword names and value formats",
+		Long:  "The keys set with a deprecated keyword name are renamed, and the values written in a legacy format are converted. With --dry-run, the changes are displayed but not applied.",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectConfigUpgrade) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.Global.NodeSelector),
+		objectaction.WithRemoteAction("config_upgrade"),
+		objectaction.WithRemoteOptions(map[string]interface{}{
+			"dry-run": t.Global.DryRun,
+		}),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			return object.NewFromPath(p).(object.ConfigUpgrader).ConfigUpgrade(t.OptsConfigUpgrade)
+		}),
+	).Do()
+}
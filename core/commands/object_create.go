@@ -60,6 +60,7 @@ func (t *CmdObjectCreate) runErr(selector *string, kind string) error {
 		create.WithConfig(t.Config),
 		create.WithKeywords(t.Keywords),
 		create.WithRestore(t.Restore),
+		create.WithFromSnapshot(t.FromSnapshot),
 	)
 	if err != nil {
 		return err
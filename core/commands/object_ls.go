@@ -10,7 +10,8 @@ import (
 type (
 	// CmdObjectLs is the cobra flag set of the ls command.
 	CmdObjectLs struct {
-		Global object.OptsGlobal
+		Global  object.OptsGlobal
+		Extends string `flag:"extends"`
 	}
 )
 
@@ -38,5 +39,6 @@ func (t *CmdObjectLs) run(selector *string, kind string) {
 		Color:          t.Global.Color,
 		Local:          t.Global.Local,
 		Server:         t.Global.Server,
+		Extends:        t.Extends,
 	}.Do()
 }
@@ -13,6 +13,7 @@ import (
 	"opensvc.com/opensvc/core/clientcontext"
 	"opensvc.com/opensvc/core/flag"
 	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
 	"opensvc.com/opensvc/core/output"
 	"opensvc.com/opensvc/core/path"
 	"opensvc.com/opensvc/core/rawconfig"
@@ -57,11 +58,25 @@ func (t *CmdObjectPrintConfig) extract(selector string, c *client.T) (result, er
 		data[p.String()], err = t.extractOne(p, c)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "%s: %s", p, err)
+			continue
+		}
+		if output.New(t.Global.Format) != output.Human {
+			data[p.String()] = t.structured(p, data[p.String()])
 		}
 	}
 	return data, nil
 }
 
+// structured returns the configuration data with the list keyword values
+// converted to lists, for the json and yaml formats.
+func (t *CmdObjectPrintConfig) structured(p path.T, data rawconfig.T) rawconfig.T {
+	c := object.NewConfigurerFromPath(p).Config()
+	if c == nil {
+		return data
+	}
+	return c.Structured(data)
+}
+
 func (t *CmdObjectPrintConfig) extractOne(p path.T, c *client.T) (rawconfig.T, error) {
 	if data, err := t.extractFromDaemon(p, c); err == nil {
 		return data, nil
@@ -78,7 +93,7 @@ func (t *CmdObjectPrintConfig) extractLocal(p path.T) (rawconfig.T, error) {
 	if c == nil {
 		return rawconfig.T{}, fmt.Errorf("path %s: no configuration", p)
 	}
-	return c.Raw(), nil
+	return obj.PrintConfig(t.OptsPrintConfig)
 }
 
 func (t *CmdObjectPrintConfig) extractFromDaemon(p path.T, c *client.T) (rawconfig.T, error) {
@@ -129,6 +144,10 @@ func (t *CmdObjectPrintConfig) run(selector *string, kind string) {
 		err  error
 	)
 	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	if t.Matrix {
+		t.runMatrix(mergedSelector)
+		return
+	}
 	if c, err = client.New(client.WithURL(t.Global.Server)); err != nil {
 		log.Error().Err(err).Msg("")
 		os.Exit(1)
@@ -174,3 +193,19 @@ func (t *CmdObjectPrintConfig) run(selector *string, kind string) {
 		}.Print()
 	}
 }
+
+// runMatrix prints the configuration values evaluated for each node of
+// the selected objects. The daemon api does not support this evaluation,
+// so the action is always local.
+func (t *CmdObjectPrintConfig) runMatrix(selector string) {
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(true),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithObjectSelector(selector),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			return object.NewConfigurerFromPath(p).PrintConfigMatrix(t.OptsPrintConfig)
+		}),
+	).Do()
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdObjectRefreshData is the cobra flag set of the refresh-data command.
+	CmdObjectRefreshData struct {
+		object.OptsRefreshData
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectRefreshData) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdObjectRefreshData) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "refresh-data",
+		Short: "re-install the changed cfg and sec keys projected in volumes, and signal their users",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectRefreshData) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.Global.NodeSelector),
+		objectaction.WithRemoteAction("refresh-data"),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			o, ok := object.NewFromPath(p).(object.DataRefresher)
+			if !ok {
+				return nil, nil
+			}
+			return nil, o.RefreshData(t.OptsRefreshData)
+		}),
+	).Do()
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdObjectResize is the cobra flag set of the resize command.
+	CmdObjectResize struct {
+		object.OptsResize
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectResize) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdObjectResize) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "resize",
+		Short: "resize the volume devices and filesystems, and update the size keywords",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectResize) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.Global.NodeSelector),
+		objectaction.WithRemoteAction("resize"),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			o, ok := object.NewFromPath(p).(object.Resizer)
+			if !ok {
+				return nil, nil
+			}
+			return nil, o.Resize(t.OptsResize)
+		}),
+	).Do()
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdObjectValidateConfig is the cobra flag set of the validate config command.
+	CmdObjectValidateConfig struct {
+		object.OptsValidateConfig
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectValidateConfig) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsValidateConfig)
+}
+
+func (t *CmdObjectValidateConfig) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:     "config",
+		Short:   "verify the object configuration syntax and report the problems found",
+		Aliases: []string{"confi", "conf", "con", "co", "c", "cf", "cfg"},
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectValidateConfig) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.Global.NodeSelector),
+		objectaction.WithRemoteAction("validate_config"),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			return object.NewFromPath(p).(object.ConfigValidater).ValidateConfig(t.OptsValidateConfig)
+		}),
+	).Do()
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// PoolGC is the cobra flag set of the command.
+	PoolGC struct {
+		object.OptsNodePoolGC
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *PoolGC) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsNodePoolGC)
+}
+
+func (t *PoolGC) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "gc",
+		Short: "unprovision and delete the stopped volumes no longer referenced by any object of the cluster",
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *PoolGC) run() {
+	nodeaction.New(
+		nodeaction.WithLocal(t.Global.Local),
+		nodeaction.WithRemoteNodes(t.Global.NodeSelector),
+		nodeaction.WithFormat(t.Global.Format),
+		nodeaction.WithColor(t.Global.Color),
+		nodeaction.WithServer(t.Global.Server),
+		nodeaction.WithRemoteAction("pool gc"),
+		nodeaction.WithRemoteOptions(map[string]interface{}{
+			"dry-run": t.Global.DryRun,
+		}),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return object.NewNode().PoolGC(t.OptsNodePoolGC)
+		}),
+	).Do()
+}
@@ -69,11 +69,21 @@ func (t *PoolStatus) run() {
 }
 
 func (t *PoolStatus) extractLocal() (pool.StatusList, error) {
+	var l pool.StatusList
+	node := object.NewNode()
 	if t.Name == "" {
-		return object.NewNode().ShowPools(), nil
+		l = node.ShowPools()
 	} else {
-		return object.NewNode().ShowPoolsByName(t.Name), nil
+		l = node.ShowPoolsByName(t.Name)
 	}
+	if !t.Verbose {
+		return l, nil
+	}
+	volumes, err := node.PoolVolumes()
+	if err != nil {
+		return l, err
+	}
+	return l.WithVolumes(volumes), nil
 }
 
 func (t *PoolStatus) extractDaemon() (pool.StatusList, error) {
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// CmdSchema is the cobra flag set of the schema command.
+	CmdSchema struct {
+		Kind   string `flag:"schemakind"`
+		Format string `flag:"schemaformat"`
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdSchema) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdSchema) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "export the keywords of a configuration kind as a json schema or a markdown documentation",
+		Long:  "The json schema describes the json representation of the configuration, as printed by the print config command, and can be used by editors and linters.",
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := t.run(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func (t *CmdSchema) run() error {
+	schema, err := object.NewSchema(t.Kind)
+	if err != nil {
+		return err
+	}
+	switch t.Format {
+	case "json-schema":
+		b, err := json.MarshalIndent(schema.JSONSchema(), "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	case "markdown":
+		fmt.Print(schema.Markdown())
+	default:
+		return fmt.Errorf("unsupported schema format %s", t.Format)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdUsrTokenCreate is the cobra flag set of the token create command.
+	CmdUsrTokenCreate struct {
+		object.OptsTokenCreate
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdUsrTokenCreate) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsTokenCreate)
+}
+
+func (t *CmdUsrTokenCreate) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "create",
+		Short: "create a signed bearer token embedding the user grants",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdUsrTokenCreate) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.Global.NodeSelector),
+		objectaction.WithRemoteAction("token create"),
+		objectaction.WithRemoteOptions(map[string]interface{}{
+			"duration": t.Duration.String(),
+		}),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			return object.NewFromPath(p).(object.Usrer).TokenCreate(t.OptsTokenCreate)
+		}),
+	).Do()
+}
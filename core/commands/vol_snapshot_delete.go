@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdVolSnapshotDelete is the cobra flag set of the snapshot delete command.
+	CmdVolSnapshotDelete struct {
+		object.OptsSnapshotDelete
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdVolSnapshotDelete) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsSnapshotDelete)
+}
+
+func (t *CmdVolSnapshotDelete) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete",
+		Short: "delete a volume snapshot",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdVolSnapshotDelete) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.Global.NodeSelector),
+		objectaction.WithRemoteAction("snapshot delete"),
+		objectaction.WithRemoteOptions(map[string]interface{}{
+			"name": t.Name,
+		}),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			return nil, object.NewFromPath(p).(object.VolSnapshotter).SnapshotDelete(t.OptsSnapshotDelete)
+		}),
+	).Do()
+}
@@ -5,12 +5,18 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/iancoleman/orderedmap"
+	"github.com/pkg/errors"
 	"opensvc.com/opensvc/core/client"
 	"opensvc.com/opensvc/core/clientcontext"
+	"opensvc.com/opensvc/core/drivergroup"
+	"opensvc.com/opensvc/core/kind"
 	"opensvc.com/opensvc/core/object"
 	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/pool"
 	"opensvc.com/opensvc/core/rawconfig"
 	"opensvc.com/opensvc/core/xconfig"
 	"opensvc.com/opensvc/util/file"
@@ -27,6 +33,8 @@ type (
 		template  string
 		keywords  []string
 		restore   bool
+
+		fromSnapshot string
 	}
 	Pivot map[string]rawconfig.T
 )
@@ -102,6 +110,20 @@ func WithRestore(v bool) funcopt.O {
 	})
 }
 
+//
+// WithFromSnapshot sets the <svc path>@<snapshot name> reference of the
+// service whose volumes snapshot data is cloned into the new service
+// volumes. If no configuration nor template is set, the new service
+// configuration is a copy of the source service configuration.
+//
+func WithFromSnapshot(s string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.fromSnapshot = s
+		return nil
+	})
+}
+
 func New(opts ...funcopt.O) (*T, error) {
 	t := &T{}
 	if err := funcopt.Apply(t, opts...); err != nil {
@@ -116,6 +138,8 @@ func (t T) Do() error {
 		return fmt.Errorf("--config and --template are conflicting")
 	case t.template != "":
 		return t.fromTemplate()
+	case t.config == "" && t.fromSnapshot != "":
+		return t.fromSnapshotSource()
 	case t.config == "":
 		return t.fromScratch()
 	case t.config == "-" || t.config == "/dev/stdin" || t.config == "stdin":
@@ -182,8 +206,19 @@ func (t T) fromStdin() error {
 	}
 }
 
+func (t T) fromSnapshotSource() error {
+	if pivot, err := t.rawFromSnapshotSource(); err != nil {
+		return err
+	} else {
+		return t.fromData(pivot)
+	}
+}
+
 func (t T) fromData(pivot Pivot) error {
 	// TODO: kws
+	if err := t.setCloneFrom(pivot); err != nil {
+		return err
+	}
 	if clientcontext.IsSet() {
 		return t.submit(pivot)
 	}
@@ -206,6 +241,117 @@ func (t T) rawFromConfig() (Pivot, error) {
 	}
 }
 
+// parseFromSnapshot splits a <svc path>@<snapshot name> reference. The
+// source service must be in <namespace>, the namespace of the created
+// object, which is also the default namespace of the source path.
+func parseFromSnapshot(s string, namespace string) (path.T, string, error) {
+	l := strings.SplitN(s, "@", 2)
+	if len(l) != 2 || l[1] == "" {
+		return path.T{}, "", fmt.Errorf("invalid --from-snapshot value '%s': expected <svc path>@<snapshot name>", s)
+	}
+	if err := pool.ValidateSnapshotName(l[1]); err != nil {
+		return path.T{}, "", errors.Wrapf(err, "invalid --from-snapshot value '%s'", s)
+	}
+	p, err := path.ParseIn(l[0], namespace)
+	if err != nil {
+		return path.T{}, "", err
+	}
+	if p.Namespace != namespace {
+		return path.T{}, "", fmt.Errorf("invalid --from-snapshot value '%s': %s is not in the %s namespace", s, p, namespace)
+	}
+	return p, l[1], nil
+}
+
+func (t T) rawFromSnapshotSource() (Pivot, error) {
+	pivot := make(Pivot)
+	p, _, err := parseFromSnapshot(t.fromSnapshot, t.path.Namespace)
+	if err != nil {
+		return pivot, err
+	}
+	o := object.NewFromPath(p)
+	if !o.(object.Baser).Exists() {
+		return pivot, fmt.Errorf("%s does not exist", p)
+	}
+	c := o.(object.Configurer).Config().Raw()
+	if v, ok := c.Data.Get("DEFAULT"); ok {
+		// the new object must not share the source object id
+		section := v.(orderedmap.OrderedMap)
+		section.Delete("id")
+		c.Data.Set("DEFAULT", section)
+	}
+	pivot[t.path.String()] = c
+	return pivot, nil
+}
+
+// setCloneFrom sets the clone_from keyword of the pivot volume resources
+// matching the volume resources of the --from-snapshot source service.
+func (t T) setCloneFrom(pivot Pivot) error {
+	if t.fromSnapshot == "" {
+		return nil
+	}
+	p, name, err := parseFromSnapshot(t.fromSnapshot, t.path.Namespace)
+	if err != nil {
+		return err
+	}
+	vols, err := sourceVols(p)
+	if err != nil {
+		return err
+	}
+	return pivotSetCloneFrom(pivot, vols, name)
+}
+
+// sourceVols returns the volume object paths indexed by volume resource
+// id of the object <p>.
+func sourceVols(p path.T) (map[string]path.T, error) {
+	type volNamer interface {
+		VolName() string
+	}
+	m := make(map[string]path.T)
+	o, ok := object.NewFromPath(p).(object.ResourceLister)
+	if !ok {
+		return m, fmt.Errorf("%s has no resources", p)
+	}
+	for _, r := range o.Resources() {
+		if r.ID().DriverGroup() != drivergroup.Volume {
+			continue
+		}
+		i, ok := r.(volNamer)
+		if !ok {
+			continue
+		}
+		vp, err := path.New(i.VolName(), p.Namespace, kind.Vol.String())
+		if err != nil {
+			return m, err
+		}
+		m[r.RID()] = vp
+	}
+	if len(m) == 0 {
+		return m, fmt.Errorf("%s has no volume resources", p)
+	}
+	return m, nil
+}
+
+func pivotSetCloneFrom(pivot Pivot, vols map[string]path.T, name string) error {
+	for opath, c := range pivot {
+		if c.Data == nil {
+			continue
+		}
+		for rid, vp := range vols {
+			v, ok := c.Data.Get(rid)
+			if !ok {
+				continue
+			}
+			section, ok := v.(orderedmap.OrderedMap)
+			if !ok {
+				return fmt.Errorf("%s: section %s format error", opath, rid)
+			}
+			section.Set("clone_from", vp.String()+"@"+name)
+			c.Data.Set(rid, section)
+		}
+	}
+	return nil
+}
+
 func rawFromConfigURI(p path.T, u uri.T) (Pivot, error) {
 	fpath, err := u.Fetch()
 	if err != nil {
@@ -218,6 +364,20 @@ func rawFromConfigURI(p path.T, u uri.T) (Pivot, error) {
 
 func rawFromConfigFile(p path.T, fpath string) (Pivot, error) {
 	pivot := make(Pivot)
+	switch filepath.Ext(fpath) {
+	case ".json", ".yaml", ".yml":
+		b, err := ioutil.ReadFile(fpath)
+		if err != nil {
+			return pivot, err
+		}
+		c, err := rawconfig.ParseStructured(b)
+		if err != nil {
+			return pivot, err
+		}
+		pivot[p.String()] = c
+		fmt.Print("parsed... ")
+		return pivot, nil
+	}
 	c, err := xconfig.NewObject(fpath)
 	if err != nil {
 		return pivot, err
@@ -252,6 +412,11 @@ func rawFromStdinNested(namespace string) (Pivot, error) {
 		}
 		return rawFromBytesFlat(p, b)
 	}
+	for opath, c := range pivot {
+		if pivot[opath], err = c.Normalize(); err != nil {
+			return pivot, err
+		}
+	}
 	return pivot, nil
 }
 
@@ -290,11 +455,11 @@ func rawFromStdinFlat(p path.T) (Pivot, error) {
 
 func rawFromBytesFlat(p path.T, b []byte) (Pivot, error) {
 	pivot := make(Pivot)
-	c := &rawconfig.T{}
-	if err := json.Unmarshal(b, c); err != nil {
+	c, err := rawconfig.ParseStructured(b)
+	if err != nil {
 		return pivot, err
 	}
-	pivot[p.String()] = *c
+	pivot[p.String()] = c
 	return pivot, nil
 }
 
@@ -0,0 +1,50 @@
+package create
+
+import (
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestParseFromSnapshot(t *testing.T) {
+	p, name, err := parseFromSnapshot("prod/svc/db@daily", "prod")
+	require.NoError(t, err)
+	assert.Equal(t, "prod/svc/db", p.String())
+	assert.Equal(t, "daily", name)
+
+	p, _, err = parseFromSnapshot("db@daily", "prod")
+	require.NoError(t, err)
+	assert.Equal(t, "prod/svc/db", p.String(), "source path relative to the namespace")
+
+	for _, s := range []string{"prod/svc/db", "prod/svc/db@", "", "test/svc/db@daily", "db@../x", "db@../../db2"} {
+		_, _, err := parseFromSnapshot(s, "prod")
+		assert.Error(t, err, s)
+	}
+}
+
+func TestPivotSetCloneFrom(t *testing.T) {
+	section := *orderedmap.New()
+	section.Set("size", "1g")
+	c := rawconfig.T{Data: orderedmap.New()}
+	c.Data.Set("volume#1", section)
+	c.Data.Set("app#1", *orderedmap.New())
+	pivot := Pivot{"test/svc/db": c}
+	vp, _ := path.Parse("prod/vol/db-vol-1")
+	vols := map[string]path.T{
+		"volume#1": vp,
+		"volume#2": vp,
+	}
+	require.NoError(t, pivotSetCloneFrom(pivot, vols, "daily"))
+
+	v, _ := pivot["test/svc/db"].Data.Get("volume#1")
+	s := v.(orderedmap.OrderedMap)
+	cloneFrom, _ := s.Get("clone_from")
+	assert.Equal(t, "prod/vol/db-vol-1@daily", cloneFrom)
+	assert.Equal(t, []string{"size", "clone_from"}, s.Keys())
+	_, ok := pivot["test/svc/db"].Data.Get("volume#2")
+	assert.False(t, ok, "no section is added")
+}
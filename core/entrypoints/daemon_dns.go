@@ -0,0 +1,53 @@
+package entrypoints
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/clusterdns"
+	"opensvc.com/opensvc/core/object"
+)
+
+// DaemonDNS serves the cluster dns zone, built from the cluster status
+// fetched from an opensvc agent api, to a PowerDNS remote backend
+// connected to the node dns unix socket.
+type DaemonDNS struct {
+	Server string
+}
+
+// Do serves the dns backend requests until interrupted.
+func (t DaemonDNS) Do() error {
+	c, err := client.New(client.WithURL(t.Server))
+	if err != nil {
+		return err
+	}
+	getter := func() (cluster.Status, error) {
+		var data cluster.Status
+		b, err := c.NewGetDaemonStatus().Do()
+		if err != nil {
+			return data, err
+		}
+		err = json.Unmarshal(b, &data)
+		return data, err
+	}
+	node := object.NewNode()
+	srv := clusterdns.New(
+		clusterdns.WithSocket(node.DNSUDSFile()),
+		clusterdns.WithStatusGetter(getter),
+		clusterdns.WithLogger(node.Log().With().Str("c", "dns").Logger()),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sig
+		cancel()
+	}()
+	return srv.ListenAndServe(ctx)
+}
@@ -1,10 +1,13 @@
 package entrypoints
 
 import (
+	"fmt"
+	"os"
 	"sort"
 
 	"opensvc.com/opensvc/core/object"
 	"opensvc.com/opensvc/core/output"
+	"opensvc.com/opensvc/core/path"
 	"opensvc.com/opensvc/core/rawconfig"
 )
 
@@ -15,6 +18,20 @@ type List struct {
 	Format         string
 	Server         string
 	Local          bool
+
+	// Extends, if set, is the path of a template object. Only the
+	// objects extending this template are listed.
+	Extends string
+}
+
+// objectExtends returns true if the local configuration of the object at
+// path p extends the template object at path extends.
+func objectExtends(p path.T, extends path.T) bool {
+	i, ok := object.NewFromPath(p).(interface{ Extends(path.T) bool })
+	if !ok {
+		return false
+	}
+	return i.Extends(extends)
 }
 
 // Do prints the formatted object selection
@@ -25,8 +42,19 @@ func (t List) Do() {
 		object.SelectionWithServer(t.Server),
 	)
 	data := make([]string, 0)
-	for _, path := range selection.Expand() {
-		data = append(data, path.String())
+	var extends path.T
+	if t.Extends != "" {
+		var err error
+		if extends, err = path.Parse(t.Extends); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	for _, p := range selection.Expand() {
+		if t.Extends != "" && !objectExtends(p, extends) {
+			continue
+		}
+		data = append(data, p.String())
 	}
 	sort.Strings(data)
 	human := func() string {
@@ -0,0 +1,73 @@
+// Package expose parses the ip resources expose keyword elements,
+// formatted as <port>/<protocol>[:<host port>].
+package expose
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	// T describes a socket service exposed by an ip resource.
+	T struct {
+		Port     int    `json:"port"`
+		Protocol string `json:"protocol"`
+		// HostPort is the port mapped to Port on the host, or 0 if no
+		// mapping is requested.
+		HostPort int `json:"host_port,omitempty"`
+	}
+	L []T
+)
+
+// Parse returns the T parsed from a <port>/<protocol>[:<host port>]
+// string. The protocol defaults to tcp.
+func Parse(s string) (T, error) {
+	t := T{Protocol: "tcp"}
+	portProto := s
+	if i := strings.Index(s, ":"); i >= 0 {
+		portProto = s[:i]
+		hostPort, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return t, fmt.Errorf("expose %s: invalid host port: %s", s, err)
+		}
+		t.HostPort = hostPort
+	}
+	parts := strings.SplitN(portProto, "/", 2)
+	port, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return t, fmt.Errorf("expose %s: invalid port: %s", s, err)
+	}
+	t.Port = port
+	if len(parts) == 2 {
+		t.Protocol = strings.ToLower(parts[1])
+	}
+	switch t.Protocol {
+	case "tcp", "udp":
+	default:
+		return t, fmt.Errorf("expose %s: invalid protocol: %s", s, t.Protocol)
+	}
+	return t, nil
+}
+
+// ParseList returns the list of T parsed from the expose keyword
+// elements.
+func ParseList(l []string) (L, error) {
+	r := make(L, 0)
+	for _, s := range l {
+		t, err := Parse(s)
+		if err != nil {
+			return r, err
+		}
+		r = append(r, t)
+	}
+	return r, nil
+}
+
+func (t T) String() string {
+	s := fmt.Sprintf("%d/%s", t.Port, t.Protocol)
+	if t.HostPort > 0 {
+		s += fmt.Sprintf(":%d", t.HostPort)
+	}
+	return s
+}
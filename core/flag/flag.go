@@ -25,10 +25,21 @@ type (
 
 		// Deprecated is the deprecation message. Empty means not deprecated.
 		Deprecated string
+
+		// Required makes cobra refuse the command if the flag is not set.
+		Required bool
 	}
 )
 
+// Install adds to cmd the flags of the data struct fields having a flag
+// tag, nested structs included. A flag already added by a previous field
+// is not redefined, so an option struct can override a flag of an
+// embedded struct by declaring its own field first.
 func Install(cmd *cobra.Command, data interface{}) {
+	install(cmd, data, make(map[string]interface{}))
+}
+
+func install(cmd *cobra.Command, data interface{}, installed map[string]interface{}) {
 	v := reflect.ValueOf(data).Elem()
 	t := v.Type()
 	for i := 0; i < t.NumField(); i++ {
@@ -38,15 +49,15 @@ func Install(cmd *cobra.Command, data interface{}) {
 		case reflect.Struct:
 			switch e := fv.Addr().Interface().(type) {
 			default:
-				Install(cmd, e)
+				install(cmd, e, installed)
 			}
 		default:
-			installFlag(cmd, ft, fv)
+			installFlag(cmd, ft, fv, installed)
 		}
 	}
 }
 
-func installFlag(cmd *cobra.Command, ft reflect.StructField, fv reflect.Value) {
+func installFlag(cmd *cobra.Command, ft reflect.StructField, fv reflect.Value, installed map[string]interface{}) {
 	var (
 		ok   bool
 		flag string
@@ -60,6 +71,10 @@ func installFlag(cmd *cobra.Command, ft reflect.StructField, fv reflect.Value) {
 		//log.Error().Msgf("%s has flag tag %s but no opt", ft.Name, flag)
 		return
 	}
+	if _, ok = installed[opt.Long]; ok {
+		return
+	}
+	installed[opt.Long] = nil
 	//log.Info().Msgf("%s %s has flag tag %s and opt %s", cmd.Use, ft.Name, flag, opt)
 	opt.installFlag(cmd, fv)
 }
@@ -107,4 +122,7 @@ func (t *Opt) installFlag(cmd *cobra.Command, v reflect.Value) {
 			flagSet.MarkDeprecated(t.Long, t.Deprecated)
 		}
 	}
+	if t.Required {
+		_ = cmd.MarkFlagRequired(t.Long)
+	}
 }
@@ -1,6 +1,11 @@
 package flag
 
 var Tags = map[string]Opt{
+	"applyfile": Opt{
+		Long:  "file",
+		Short: "f",
+		Desc:  "the json or yaml configuration document to apply. the value can be - to read the document from stdin",
+	},
 	"color": Opt{
 		Long:    "color",
 		Default: "auto",
@@ -8,7 +13,29 @@ var Tags = map[string]Opt{
 	},
 	"config": Opt{
 		Long: "config",
-		Desc: "the configuration to use as template when creating or installing a service. the value can be `-` or `/dev/stdin` to read the json-formatted configuration from stdin, or a file path, or uri pointing to a ini-formatted configuration, or a service selector expression (ATTENTION with cloning existing live services that include more than containers, volumes and backend ip addresses ... this could cause disruption on the cloned service)",
+		Desc: "the configuration to use as template when creating or installing a service. the value can be `-` or `/dev/stdin` to read the json or yaml formatted configuration from stdin, or a file path, or uri pointing to a ini-formatted configuration, or to a json or yaml formatted configuration if the file extension is .json, .yaml or .yml, or a service selector expression (ATTENTION with cloning existing live services that include more than containers, volumes and backend ip addresses ... this could cause disruption on the cloned service)",
+	},
+	"configrev": Opt{
+		Long: "rev",
+		Desc: "the configuration revision number, as listed by the config history command",
+	},
+	"configrevs": Opt{
+		Long: "rev",
+		Desc: "the configuration revision numbers to compare. set once to compare with the current configuration, twice to compare two revisions",
+	},
+	"datainterval": Opt{
+		Long:    "interval",
+		Default: "10s",
+		Desc:    "the interval between two data refreshes in watch mode",
+	},
+	"datawatch": Opt{
+		Long:  "watch",
+		Short: "w",
+		Desc:  "keep refreshing the data until interrupted",
+	},
+	"diff": Opt{
+		Long: "diff",
+		Desc: "show the changes without applying them",
 	},
 	"disable-rollback": Opt{
 		Long: "disable-rollback",
@@ -27,6 +54,10 @@ var Tags = map[string]Opt{
 		Long: "dry-run",
 		Desc: "show the action execution plan",
 	},
+	"extends": Opt{
+		Long: "extends",
+		Desc: "only list the objects extending the template object at this path, directly or through other templates",
+	},
 	"env": Opt{
 		Long: "env",
 		Desc: "export the uppercased variable in the os environment. with the create action only, set a env section parameter in the service configuration file. multiple `--env <key>=<val>` can be specified",
@@ -38,12 +69,26 @@ var Tags = map[string]Opt{
 	"format": Opt{
 		Long:    "format",
 		Default: "auto",
-		Desc:    "output format json|flat|auto",
+		Desc:    "output format json|flat|yaml|auto",
+	},
+	"exportformat": Opt{
+		Long:    "format",
+		Default: "pem-bundle",
+		Desc:    "the export format pkcs12|pem-bundle|jks-compatible",
 	},
 	"force": Opt{
 		Long: "force",
 		Desc: "allow dangerous operations",
 	},
+	"grace": Opt{
+		Long:    "grace",
+		Default: "24h",
+		Desc:    "the duration the previous cluster secret is still accepted to decrypt data",
+	},
+	"if-expires-within": Opt{
+		Long: "if-expires-within",
+		Desc: "renew the certificate only if it expires within this duration (30d, 12h, ...)",
+	},
 	"impersonate": Opt{
 		Long: "impersonate",
 		Desc: "the name of a peer node to impersonate when evaluating keywords",
@@ -76,6 +121,10 @@ var Tags = map[string]Opt{
 		Long: "local",
 		Desc: "inline action on local instance",
 	},
+	"matrix": Opt{
+		Long: "matrix",
+		Desc: "evaluate the keywords for each node of the object nodes and drpnodes, and highlight the per-node differences",
+	},
 	"createnamespace": Opt{
 		Long: "namespace",
 		Desc: "where to create the new objects",
@@ -85,6 +134,10 @@ var Tags = map[string]Opt{
 		Desc:    "a fnmatch key name filter",
 		Default: "**",
 	},
+	"networkname": Opt{
+		Long: "name",
+		Desc: "filter on a network name",
+	},
 	"node": Opt{
 		Long: "node",
 		Desc: "execute on a list of nodes",
@@ -129,14 +182,41 @@ var Tags = map[string]Opt{
 		Long: "restore",
 		Desc: "keep the same object id as the origin template or config file. the default is to generate a new id",
 	},
+	"password": Opt{
+		Long: "password",
+		Desc: "the password protecting the exported archive",
+	},
 	"rid": Opt{
 		Long: "rid",
 		Desc: "resource selector expression (ip#1,app,disk.type=zvol)",
 	},
+	"schemaformat": Opt{
+		Long:    "format",
+		Default: "json-schema",
+		Desc:    "the schema format json-schema|markdown",
+	},
+	"schemakind": Opt{
+		Long:    "kind",
+		Default: "svc",
+		Desc:    "the configuration kind svc|vol|cfg|sec|usr|node|cluster",
+	},
+	"secret": Opt{
+		Long: "secret",
+		Desc: "the new cluster secret. a random secret is generated if not set",
+	},
+	"size": Opt{
+		Long: "size",
+		Desc: "the new size, or a size delta prefixed by + or - (ex: +10g)",
+	},
 	"server": Opt{
 		Long: "server",
 		Desc: "uri of the opensvc api server. scheme raw|https",
 	},
+	"snapshotname": Opt{
+		Long:     "name",
+		Desc:     "the snapshot name",
+		Required: true,
+	},
 	"time": Opt{
 		Long:    "time",
 		Default: "5m",
@@ -163,10 +243,19 @@ var Tags = map[string]Opt{
 		Desc:       "start the service up to the specified rid or driver group",
 		Deprecated: "use --to",
 	},
+	"fromsnapshot": Opt{
+		Long: "from-snapshot",
+		Desc: "the <svc path>@<snapshot name> reference of the service volumes snapshot to clone the new service volumes data from. the source service configuration is used if --config is not set",
+	},
 	"from": Opt{
 		Long: "from",
 		Desc: "the key value source (uri, file, /dev/stdin)",
 	},
+	"tokenduration": Opt{
+		Long:    "duration",
+		Default: "24h",
+		Desc:    "the token validity duration",
+	},
 	"value": Opt{
 		Long: "value",
 		Desc: "the key value",
@@ -0,0 +1,346 @@
+// Package keystorebackend resolves the keystore values stored as
+// references to an external secret store, like a HashiCorp Vault
+// KV engine.
+//
+// A reference has the <backend>://<mount>/<path>#<field> format, where
+// backend is the name of a keystore#<backend> section of the node or
+// cluster configuration. The type keyword of this section selects the
+// driver, registered by the drivers/keystore* packages.
+//
+// A backend is usable only by the sec objects of the namespaces listed in
+// its allow keyword, and only for the paths under the mount and path
+// prefix allowed for the namespace.
+//
+// The resolved values are cached in memory for the cache_ttl duration
+// of the backend.
+package keystorebackend
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	opath "opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+const (
+	// RefPrefix is the prefix of the keystore values stored as references.
+	RefPrefix = "ref:"
+
+	// DefaultCacheTTL is the resolved values caching duration used when
+	// the backend has no cache_ttl set.
+	DefaultCacheTTL = 5 * time.Minute
+)
+
+type (
+	// T is the base type embedded by backend drivers.
+	T struct {
+		driver  string
+		name    string
+		config  *xconfig.T
+		decoder SecretDecoder
+	}
+
+	// SecretDecoder returns the decoded value of a key of a sec object.
+	// Backends use it to read their credentials.
+	SecretDecoder func(p opath.T, k string) ([]byte, error)
+
+	// Backender is the interface implemented by backend drivers.
+	Backender interface {
+		SetName(string)
+		SetDriver(string)
+		SetConfig(*xconfig.T)
+		SetSecretDecoder(SecretDecoder)
+		Name() string
+		Type() string
+		CacheTTL() time.Duration
+		Get(Ref) ([]byte, error)
+	}
+
+	// Ref is a parsed <backend>://<mount>/<path>#<field> reference.
+	Ref struct {
+		Backend string
+		Mount   string
+		Path    string
+		Field   string
+	}
+
+	cacheEntry struct {
+		b       []byte
+		expires time.Time
+	}
+)
+
+var (
+	drivers = make(map[string]func() Backender)
+
+	cache   = make(map[string]cacheEntry)
+	cacheMu sync.Mutex
+)
+
+// Register makes a backend driver available to the keystore#<name>
+// sections with the type keyword set to t.
+func Register(t string, fn func() Backender) {
+	drivers[t] = fn
+}
+
+func sectionName(name string) string {
+	return "keystore#" + name
+}
+
+// Exists returns true if the config has a keystore#<name> section.
+func Exists(name string, config *xconfig.T) bool {
+	for _, s := range config.SectionStrings() {
+		if s == sectionName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// New allocates and configures the backend driver matching the
+// keystore#<name>.type keyword value.
+func New(name string, config *xconfig.T, decoder SecretDecoder) (Backender, error) {
+	if !Exists(name, config) {
+		return nil, fmt.Errorf("keystore backend %s is not configured: no %s section", name, sectionName(name))
+	}
+	backendType := config.GetString(key.New(sectionName(name), "type"))
+	fn, ok := drivers[backendType]
+	if !ok {
+		return nil, fmt.Errorf("keystore backend %s: unsupported type '%s'", name, backendType)
+	}
+	t := fn()
+	t.SetName(name)
+	t.SetDriver(backendType)
+	t.SetConfig(config)
+	t.SetSecretDecoder(decoder)
+	return t, nil
+}
+
+func (t T) Name() string {
+	return t.name
+}
+
+func (t *T) SetName(name string) {
+	t.name = name
+}
+
+func (t *T) SetDriver(driver string) {
+	t.driver = driver
+}
+
+func (t T) Type() string {
+	return t.driver
+}
+
+func (t *T) Config() *xconfig.T {
+	return t.config
+}
+
+func (t *T) SetConfig(c *xconfig.T) {
+	t.config = c
+}
+
+func (t *T) SetSecretDecoder(fn SecretDecoder) {
+	t.decoder = fn
+}
+
+// DecodeSecret returns the decoded value of the key k of the sec object p.
+func (t T) DecodeSecret(p opath.T, k string) ([]byte, error) {
+	if t.decoder == nil {
+		return nil, fmt.Errorf("keystore backend %s: no secret decoder", t.name)
+	}
+	return t.decoder(p, k)
+}
+
+func (t *T) GetString(s string) string {
+	return t.config.GetString(key.New(sectionName(t.name), s))
+}
+
+// CacheTTL returns the duration the resolved values are cached.
+func (t *T) CacheTTL() time.Duration {
+	d := t.config.GetDuration(key.New(sectionName(t.name), "cache_ttl"))
+	if d == nil {
+		return DefaultCacheTTL
+	}
+	return *d
+}
+
+// IsRef returns true if s is a keystore value stored as a reference.
+func IsRef(s string) bool {
+	return strings.HasPrefix(s, RefPrefix)
+}
+
+// ParseRef parses a <backend>://<mount>/<path>#<field> reference. The
+// RefPrefix is accepted and ignored.
+//
+// The empty, . and .. path elements are refused, in the decoded and raw
+// forms of the path, so a reference can not escape an allowed path
+// prefix. The percent-encoded slashes are refused too.
+func ParseRef(s string) (Ref, error) {
+	s = strings.TrimPrefix(s, RefPrefix)
+	u, err := url.Parse(s)
+	if err != nil {
+		return Ref{}, err
+	}
+	ref := Ref{
+		Backend: u.Scheme,
+		Mount:   u.Host,
+		Path:    strings.TrimPrefix(u.Path, "/"),
+		Field:   u.Fragment,
+	}
+	if ref.Backend == "" || ref.Mount == "" || ref.Path == "" {
+		return Ref{}, fmt.Errorf("invalid keystore reference %s: expected <backend>://<mount>/<path>#<field>", s)
+	}
+	if err := ref.validate(); err != nil {
+		return Ref{}, errors.Wrapf(err, "invalid keystore reference %s", s)
+	}
+	raw := strings.Split(strings.TrimPrefix(u.EscapedPath(), "/"), "/")
+	if len(raw) != len(ref.Segments()) {
+		return Ref{}, fmt.Errorf("invalid keystore reference %s: encoded path separator", s)
+	}
+	for _, e := range raw {
+		if err := validateSegment(e); err != nil {
+			return Ref{}, errors.Wrapf(err, "invalid keystore reference %s", s)
+		}
+	}
+	return ref, nil
+}
+
+// Segments returns the path elements of the reference, the mount
+// excluded.
+func (t Ref) Segments() []string {
+	return strings.Split(t.Path, "/")
+}
+
+// validate returns an error if the mount or a path element of the
+// reference is empty, . or ..
+func (t Ref) validate() error {
+	if err := validateSegment(t.Mount); err != nil {
+		return errors.Wrapf(err, "mount")
+	}
+	for _, e := range t.Segments() {
+		if err := validateSegment(e); err != nil {
+			return errors.Wrapf(err, "path")
+		}
+	}
+	return nil
+}
+
+func validateSegment(s string) error {
+	switch s {
+	case "", ".", "..":
+		return fmt.Errorf("invalid path element '%s'", s)
+	}
+	return nil
+}
+
+func (t Ref) String() string {
+	s := fmt.Sprintf("%s://%s/%s", t.Backend, t.Mount, t.Path)
+	if t.Field != "" {
+		s += "#" + t.Field
+	}
+	return s
+}
+
+// Allowed returns true if the reference is allowed to the namespace by
+// one of the <namespace>:<mount>[/<path prefix>] elements of allow. The
+// path prefix matches whole path elements.
+func (t Ref) Allowed(namespace string, allow []string) bool {
+	if t.validate() != nil {
+		return false
+	}
+	for _, s := range allow {
+		l := strings.SplitN(s, ":", 2)
+		if len(l) != 2 || l[0] != namespace {
+			continue
+		}
+		prefix := strings.Trim(path.Clean("/"+l[1]), "/")
+		if prefix == "" {
+			continue
+		}
+		p := t.Mount + "/" + t.Path
+		if p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize returns an error if the backend referenced by ref does not
+// allow the namespace to read the referenced path.
+func Authorize(ref Ref, namespace string, config *xconfig.T) error {
+	if !Exists(ref.Backend, config) {
+		return fmt.Errorf("keystore backend %s is not configured: no %s section", ref.Backend, sectionName(ref.Backend))
+	}
+	if namespace == "" {
+		namespace = "root"
+	}
+	allow := config.GetSlice(key.New(sectionName(ref.Backend), "allow"))
+	if !ref.Allowed(namespace, allow) {
+		return fmt.Errorf("keystore backend %s does not allow the %s namespace to read %s/%s: see %s.allow", ref.Backend, namespace, ref.Mount, ref.Path, sectionName(ref.Backend))
+	}
+	return nil
+}
+
+// Resolve returns the value referenced by s for a sec object of the
+// namespace, from the cache if not expired, or from the backend. The
+// reference is authorized before the cache lookup.
+func Resolve(s string, namespace string, config *xconfig.T, decoder SecretDecoder) ([]byte, error) {
+	ref, err := ParseRef(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := Authorize(ref, namespace, config); err != nil {
+		return nil, err
+	}
+	if b, ok := cacheGet(ref.String()); ok {
+		return b, nil
+	}
+	backend, err := New(ref.Backend, config, decoder)
+	if err != nil {
+		return nil, err
+	}
+	b, err := backend.Get(ref)
+	if err != nil {
+		return nil, err
+	}
+	cacheSet(ref.String(), b, backend.CacheTTL())
+	return b, nil
+}
+
+func cacheGet(s string) ([]byte, bool) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	e, ok := cache[s]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(cache, s)
+		return nil, false
+	}
+	return e.b, true
+}
+
+func cacheSet(s string, b []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	cache[s] = cacheEntry{b: b, expires: time.Now().Add(ttl)}
+}
+
+// FlushCache drops all the cached resolved values.
+func FlushCache() {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	cache = make(map[string]cacheEntry)
+}
@@ -0,0 +1,79 @@
+package keystorebackend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRef(t *testing.T) {
+	ref, err := ParseRef("ref:vault://secret/app/db#password")
+	require.NoError(t, err)
+	assert.Equal(t, Ref{Backend: "vault", Mount: "secret", Path: "app/db", Field: "password"}, ref)
+	assert.Equal(t, "vault://secret/app/db#password", ref.String())
+
+	for _, s := range []string{
+		"vault://secret",
+		"secret/app#f",
+		"vault:///app",
+		"vault://secret/app1/../app2#pw",
+		"vault://secret/app1/%2e%2e/app2#pw",
+		"vault://secret/app1/%2E%2E/app2#pw",
+		"vault://secret/./app1#pw",
+		"vault://secret/app1//db#pw",
+		"vault://secret/app1/db/#pw",
+		"vault://secret/app1%2F..%2Fapp2#pw",
+		"vault://../app1#pw",
+	} {
+		_, err := ParseRef(s)
+		assert.Errorf(t, err, "%s", s)
+	}
+}
+
+func TestCache(t *testing.T) {
+	defer FlushCache()
+	cacheSet("a", []byte("1"), time.Minute)
+	cacheSet("b", []byte("2"), time.Millisecond)
+	cacheSet("c", []byte("3"), 0)
+	time.Sleep(2 * time.Millisecond)
+	b, ok := cacheGet("a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", string(b))
+	_, ok = cacheGet("b")
+	assert.False(t, ok, "expired")
+	_, ok = cacheGet("c")
+	assert.False(t, ok, "not cached with a zero ttl")
+}
+
+func TestRefAllowed(t *testing.T) {
+	ref := Ref{Backend: "vault", Mount: "secret", Path: "app1/db", Field: "password"}
+	cases := []struct {
+		namespace string
+		allow     []string
+		allowed   bool
+	}{
+		{"ns1", []string{"ns1:secret/app1"}, true},
+		{"ns1", []string{"ns1:secret/app1/db"}, true},
+		{"ns1", []string{"ns1:secret"}, true},
+		{"ns1", []string{"ns1:secret/app"}, false},
+		{"ns1", []string{"ns1:secret/app1/db/x"}, false},
+		{"ns1", []string{"ns1:other/app1"}, false},
+		{"ns2", []string{"ns1:secret/app1"}, false},
+		{"ns1", []string{"ns1:", "ns1:/"}, false},
+		{"ns1", []string{}, false},
+		{"ns1", []string{"ns2:secret", "ns1:secret/app1/"}, true},
+	}
+	for _, c := range cases {
+		assert.Equalf(t, c.allowed, ref.Allowed(c.namespace, c.allow), "%s %v", c.namespace, c.allow)
+	}
+
+	t.Run("path traversal", func(t *testing.T) {
+		allow := []string{"ns1:secret/app1"}
+		for _, p := range []string{"app1/../app2", "app1/./../app2", "app1//../app2", "app1/.."} {
+			ref := Ref{Backend: "vault", Mount: "secret", Path: p, Field: "pw"}
+			assert.False(t, ref.Allowed("ns1", allow), p)
+		}
+	})
+}
@@ -14,6 +14,13 @@ type (
 		Convert(string) (interface{}, error)
 	}
 
+	// ValueUpgrader is implemented by the converters accepting values
+	// written in a legacy format. UpgradeValue returns the value in the
+	// current format.
+	ValueUpgrader interface {
+		UpgradeValue(string) string
+	}
+
 	Keyword struct {
 		Section string
 		Option  string
@@ -57,30 +64,75 @@ type (
 
 		// Types limits the scope of the keyword to sections with matching type value
 		Types []string
+
+		// Aliases is the list of the legacy names of the keyword. A key
+		// set with an alias name is read as the keyword, with a warning.
+		Aliases []string
+
+		// Deprecated is the agent release deprecating the keyword. Empty means not deprecated.
+		Deprecated string
+
+		// ReplacedBy is the keyword to use instead of the deprecated keyword.
+		ReplacedBy string
 	}
 
 	Store []Keyword
 )
 
+// Lookup returns the keyword matching the key, the object kind and the
+// section type. A keyword with no Types is returned for a typed section
+// if no keyword declares this type.
+//
+// The keywords having k option as an alias are returned only if no
+// keyword is named after the k option.
 func (t Store) Lookup(k key.T, kd kind.T, sectionType string) Keyword {
+	if kw := t.lookup(k, kd, sectionType, Keyword.hasOption); !kw.IsZero() {
+		return kw
+	}
+	return t.lookup(k, kd, sectionType, Keyword.HasAlias)
+}
+
+func (t Store) lookup(k key.T, kd kind.T, sectionType string, match func(Keyword, string) bool) Keyword {
+	var untyped Keyword
 	driverGroup := strings.Split(k.Section, "#")[0]
 	for _, kw := range t {
 		if !kw.Kind.Has(kd) {
 			continue
 		}
-		if k.Option != kw.Option {
+		if !match(kw, k.Option) {
 			continue
 		}
-		if sectionType != "" && !stringslice.Has(sectionType, kw.Types) {
+		if kw.Section != "" && k.Section != kw.Section && driverGroup != kw.Section {
 			continue
 		}
-		if kw.Section == "" || k.Section == kw.Section || driverGroup == kw.Section {
+		switch {
+		case sectionType == "" || stringslice.Has(sectionType, kw.Types):
 			return kw
+		case len(kw.Types) == 0 && untyped.IsZero():
+			untyped = kw
 		}
 	}
-	return Keyword{}
+	return untyped
 }
 
 func (t Keyword) IsZero() bool {
 	return t.Option == ""
 }
+
+func (t Keyword) hasOption(s string) bool {
+	return t.Option == s
+}
+
+// HasAlias returns true if s is a legacy name of the keyword.
+func (t Keyword) HasAlias(s string) bool {
+	return stringslice.Has(s, t.Aliases)
+}
+
+// UpgradeValue returns the value converted from a legacy format to the
+// current format, if the keyword converter supports legacy formats.
+func (t Keyword) UpgradeValue(s string) string {
+	if i, ok := t.Converter.(ValueUpgrader); ok {
+		return i.UpgradeValue(s)
+	}
+	return s
+}
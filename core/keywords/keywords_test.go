@@ -0,0 +1,39 @@
+package keywords
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/util/key"
+)
+
+func TestStoreLookup(t *testing.T) {
+	store := Store{
+		{Section: "pool", Option: "quotas", Kind: kind.Or(kind.Vol)},
+		{Section: "pool", Option: "path", Types: []string{"directory"}, Text: "directory", Kind: kind.Or(kind.Vol)},
+		{Section: "pool", Option: "path", Types: []string{"loop"}, Text: "loop", Kind: kind.Or(kind.Vol)},
+	}
+	k := key.New("pool#p1", "quotas")
+	assert.Equal(t, "quotas", store.Lookup(k, kind.Vol, "directory").Option, "untyped keyword in a typed section")
+	assert.Equal(t, "quotas", store.Lookup(k, kind.Vol, "").Option, "untyped keyword in an untyped section")
+	assert.True(t, store.Lookup(k, kind.Svc, "directory").IsZero(), "kind mismatch")
+
+	k = key.New("pool#p1", "path")
+	assert.Equal(t, "loop", store.Lookup(k, kind.Vol, "loop").Text)
+	assert.Equal(t, "directory", store.Lookup(k, kind.Vol, "directory").Text)
+	assert.True(t, store.Lookup(k, kind.Vol, "vg").IsZero(), "type mismatch")
+	assert.True(t, store.Lookup(key.New("fs#1", "path"), kind.Vol, "loop").IsZero(), "section mismatch")
+}
+
+func TestStoreLookupAlias(t *testing.T) {
+	store := Store{
+		{Section: "DEFAULT", Option: "topology", Aliases: []string{"cluster_type"}, Kind: kind.Or(kind.Svc)},
+		{Section: "DEFAULT", Option: "flex_min", Aliases: []string{"flex_min_nodes"}, Kind: kind.Or(kind.Svc)},
+		{Section: "DEFAULT", Option: "flex_min_nodes", Text: "current", Kind: kind.Or(kind.Vol)},
+	}
+	assert.Equal(t, "topology", store.Lookup(key.New("DEFAULT", "cluster_type"), kind.Svc, "").Option)
+	assert.True(t, store.Lookup(key.New("DEFAULT", "cluster_type"), kind.Vol, "").IsZero(), "kind mismatch")
+	assert.Equal(t, "flex_min", store.Lookup(key.New("DEFAULT", "flex_min_nodes"), kind.Svc, "").Option)
+	assert.Equal(t, "current", store.Lookup(key.New("DEFAULT", "flex_min_nodes"), kind.Vol, "").Text, "keyword name has precedence over aliases")
+}
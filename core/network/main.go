@@ -0,0 +1,274 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/render/tree"
+)
+
+type (
+	T struct {
+		driver string
+		name   string
+		config *xconfig.T
+	}
+
+	Status struct {
+		Type    string   `json:"type"`
+		Name    string   `json:"name"`
+		Network string   `json:"network"`
+		Config  string   `json:"config"`
+		Errors  []string `json:"errors"`
+	}
+	StatusList []Status
+
+	Networker interface {
+		SetName(string)
+		SetDriver(string)
+		Name() string
+		Type() string
+		Network() string
+		SetConfig(*xconfig.T)
+		Config() *xconfig.T
+	}
+
+	// CNIer is implemented by networks that can be used through the
+	// ip.cni resource driver.
+	CNIer interface {
+		CNIConfigData() (interface{}, error)
+	}
+
+	// Setuper is implemented by networks needing node configurations
+	// in addition to the CNI configuration file, like routes.
+	Setuper interface {
+		Setup() error
+	}
+)
+
+const (
+	// CNIVersion is the version of the CNI spec used in the generated
+	// configuration files. 0.4.0 is the first version supporting CHECK.
+	CNIVersion = "0.4.0"
+)
+
+var (
+	drivers = make(map[string]func() Networker)
+)
+
+func NewStatus() Status {
+	t := Status{}
+	t.Errors = make([]string, 0)
+	return t
+}
+
+func sectionName(networkName string) string {
+	return "network#" + networkName
+}
+
+func cKey(networkName string, option string) key.T {
+	section := sectionName(networkName)
+	return key.New(section, option)
+}
+
+func cString(config *xconfig.T, networkName string, option string) string {
+	key := cKey(networkName, option)
+	return config.GetString(key)
+}
+
+// New allocates and configures the network driver matching the
+// network#<name>.type keyword value.
+func New(name string, config *xconfig.T) Networker {
+	networkType := cString(config, name, "type")
+	fn, ok := drivers[networkType]
+	if !ok {
+		return nil
+	}
+	t := fn()
+	t.SetName(name)
+	t.SetDriver(networkType)
+	t.SetConfig(config)
+	return t
+}
+
+func Register(t string, fn func() Networker) {
+	drivers[t] = fn
+}
+
+func (t T) Name() string {
+	return t.name
+}
+
+func (t *T) SetName(name string) {
+	t.name = name
+}
+
+func (t *T) SetDriver(driver string) {
+	t.driver = driver
+}
+
+func (t T) Type() string {
+	return t.driver
+}
+
+func (t *T) Config() *xconfig.T {
+	return t.config
+}
+
+func (t *T) SetConfig(c *xconfig.T) {
+	t.config = c
+}
+
+func (t *T) Network() string {
+	return t.GetString("network")
+}
+
+func (t *T) GetString(s string) string {
+	k := key.New(sectionName(t.name), s)
+	return t.Config().GetString(k)
+}
+
+func (t *T) GetInt(s string) int {
+	k := key.New(sectionName(t.name), s)
+	return t.Config().GetInt(k)
+}
+
+func (t *T) GetSlice(s string) []string {
+	k := key.New(sectionName(t.name), s)
+	return t.Config().GetSlice(k)
+}
+
+// CNIConfigDir returns the directory hosting the CNI network configuration files.
+func CNIConfigDir(config *xconfig.T) string {
+	return config.GetString(key.Parse("cni.config"))
+}
+
+// CNIPluginsDir returns the directory hosting the CNI plugins.
+func CNIPluginsDir(config *xconfig.T) string {
+	return config.GetString(key.Parse("cni.plugins"))
+}
+
+// CNIConfigFile returns the path of the CNI network configuration list
+// file of the network.
+func CNIConfigFile(t Networker) string {
+	return filepath.Join(CNIConfigDir(t.Config()), t.Name()+".conflist")
+}
+
+// Setup installs the CNI configuration file of the network, and the
+// driver-specific node configurations.
+func Setup(t Networker) error {
+	if err := setupCNI(t); err != nil {
+		return err
+	}
+	if o, ok := t.(Setuper); ok {
+		if err := o.Setup(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setupCNI(t Networker) error {
+	o, ok := t.(CNIer)
+	if !ok {
+		return nil
+	}
+	data, err := o.CNIConfigData()
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(data, "", "    ")
+	if err != nil {
+		return err
+	}
+	p := CNIConfigFile(t)
+	if current, err := ioutil.ReadFile(p); err == nil && string(current) == string(b) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return err
+	}
+	tmp := p + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+func GetStatus(t Networker) Status {
+	data := NewStatus()
+	data.Type = t.Type()
+	data.Name = t.Name()
+	data.Network = t.Network()
+	if _, ok := t.(CNIer); ok {
+		data.Config = CNIConfigFile(t)
+	}
+	return data
+}
+
+func NewStatusList() StatusList {
+	l := make([]Status, 0)
+	return StatusList(l)
+}
+
+func (t StatusList) Len() int {
+	return len(t)
+}
+
+func (t StatusList) Less(i, j int) bool {
+	return t[i].Name < t[j].Name
+}
+
+func (t StatusList) Swap(i, j int) {
+	t[i], t[j] = t[j], t[i]
+}
+
+func (t StatusList) Add(n Networker) StatusList {
+	s := GetStatus(n)
+	l := []Status(t)
+	l = append(l, s)
+	return StatusList(l)
+}
+
+func (t StatusList) Render() string {
+	return t.Tree().Render()
+}
+
+// Tree returns a tree loaded with the type instance.
+func (t StatusList) Tree() *tree.Tree {
+	tree := tree.New()
+	t.LoadTreeNode(tree.Head())
+	return tree
+}
+
+// LoadTreeNode add the tree nodes representing the type instance into another.
+func (t StatusList) LoadTreeNode(head *tree.Node) {
+	head.AddColumn().AddText("name").SetColor(rawconfig.Node.Color.Bold)
+	head.AddColumn().AddText("type").SetColor(rawconfig.Node.Color.Bold)
+	head.AddColumn().AddText("network").SetColor(rawconfig.Node.Color.Bold)
+	head.AddColumn().AddText("config").SetColor(rawconfig.Node.Color.Bold)
+	sort.Sort(t)
+	for _, data := range t {
+		n := head.AddNode()
+		data.LoadTreeNode(n)
+	}
+}
+
+// LoadTreeNode add the tree nodes representing the type instance into another.
+func (t Status) LoadTreeNode(head *tree.Node) {
+	head.AddColumn().AddText(t.Name).SetColor(rawconfig.Node.Color.Primary)
+	head.AddColumn().AddText(t.Type)
+	head.AddColumn().AddText(t.Network)
+	head.AddColumn().AddText(t.Config)
+	for _, e := range t.Errors {
+		n := head.AddNode()
+		n.AddColumn().AddText(fmt.Sprintf("error: %s", e)).SetColor(rawconfig.Node.Color.Error)
+	}
+}
@@ -45,6 +45,7 @@ func (t *Base) lockedStart(ctx context.Context) error {
 	if err := t.slaveStart(ctx); err != nil {
 		return err
 	}
+	t.clearKeystoreChanged()
 	return nil
 }
 
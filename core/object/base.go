@@ -11,6 +11,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/ssrathi/go-attr"
 	"opensvc.com/opensvc/core/drivergroup"
+	"opensvc.com/opensvc/core/instance"
 	"opensvc.com/opensvc/core/kind"
 	"opensvc.com/opensvc/core/path"
 	"opensvc.com/opensvc/core/rawconfig"
@@ -52,6 +53,10 @@ type (
 		paths      BasePaths
 		resources  resource.Drivers
 		_resources resource.Drivers
+
+		// customStatusEval, if set by the kind allocator, amends the
+		// instance status data evaluated from the resources.
+		customStatusEval func(*instance.Status)
 	}
 )
 
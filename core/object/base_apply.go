@@ -0,0 +1,88 @@
+package object
+
+import (
+	"io/ioutil"
+	"os"
+
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+// OptsApply is the options of the Apply object method.
+type OptsApply struct {
+	Global OptsGlobal
+	Lock   OptsLocking
+	File   string `flag:"applyfile"`
+	Diff   bool   `flag:"diff"`
+}
+
+// Apply converges the object configuration to the json or yaml document
+// read from the File option path, or from stdin if the path is "-". The
+// keys differing from the document are set, the keys and sections not in
+// the document are unset, except the object id. The configuration is
+// committed only if changed. With the Diff option, the changes are
+// returned but not applied.
+func (t *Base) Apply(options OptsApply) (rawconfig.Changes, error) {
+	var (
+		b   []byte
+		err error
+	)
+	if options.File == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(options.File)
+	}
+	if err != nil {
+		return nil, err
+	}
+	desired, err := rawconfig.ParseStructured(b)
+	if err != nil {
+		return nil, err
+	}
+	changes := t.applyChanges(desired)
+	if options.Diff || len(changes) == 0 {
+		return changes, nil
+	}
+	err = t.lockedAction("", options.Lock, "apply", func() error {
+		if err := t.config.ApplyChanges(changes); err != nil {
+			return err
+		}
+		t.config.UnsetSections(t.applyDeletedSections(desired)...)
+		if err := t.loadConfigParents(t.config); err != nil {
+			return err
+		}
+		if err := t.config.Commit(); err != nil {
+			return err
+		}
+		t.log.Info().Int("changes", len(changes)).Msg("configuration applied")
+		return nil
+	})
+	return changes, err
+}
+
+// applyChanges returns the changes to apply to the configuration file to
+// converge to the desired configuration. The object id is preserved.
+func (t *Base) applyChanges(desired rawconfig.T) rawconfig.Changes {
+	l := make(rawconfig.Changes, 0)
+	for _, c := range rawconfig.Diff(t.config.Raw(), desired) {
+		if c.Section == "DEFAULT" && c.Option == "id" && c.Op == rawconfig.ChangeDelete {
+			continue
+		}
+		l = append(l, c)
+	}
+	return l
+}
+
+// applyDeletedSections returns the sections of the configuration file
+// not in the desired configuration.
+func (t *Base) applyDeletedSections(desired rawconfig.T) []string {
+	l := make([]string, 0)
+	for _, section := range t.config.Raw().Data.Keys() {
+		if section == "DEFAULT" {
+			continue
+		}
+		if _, ok := desired.Data.Get(section); !ok {
+			l = append(l, section)
+		}
+	}
+	return l
+}
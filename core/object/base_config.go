@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"opensvc.com/opensvc/core/fqdn"
 	"opensvc.com/opensvc/core/keyop"
 	"opensvc.com/opensvc/core/path"
@@ -34,7 +35,13 @@ func (t *Base) loadConfig() error {
 	t.config.Path = t.Path
 	t.config.Referrer = t
 	t.config.NodeReferrer = t.Node()
-	return err
+	if err := t.loadConfigParents(t.config); err != nil {
+		// don't let the object run with the template keys missing
+		t.log.Error().Err(err).Msg("load extended configuration")
+		t.config = nil
+		return errors.Wrap(err, "load extended configuration")
+	}
+	return nil
 }
 
 func (t Base) Config() *xconfig.T {
@@ -60,7 +67,7 @@ func (t *Base) ID() uuid.UUID {
 		Value: t.id.String(),
 	}
 	_ = t.config.Set(op)
-	if err := t.config.Commit(); err != nil {
+	if err := t.config.CommitInvalid(); err != nil {
 		t.log.Error().Err(err).Msg("")
 	}
 	return t.id
@@ -309,6 +316,8 @@ func (t Base) Dereference(ref string) (string, error) {
 		return ref, fmt.Errorf("TODO")
 	case strings.Contains(ref, ".exposed_devs"):
 		return t.dereferenceExposedDevices(ref)
+	case strings.HasPrefix(ref, "cfg:"), strings.HasPrefix(ref, "sec:"):
+		return t.dereferenceKeystore(ref)
 	}
 	return ref, fmt.Errorf("unknown reference: %s", ref)
 }
@@ -332,5 +341,8 @@ func (t Base) EncapNodes() []string {
 }
 
 func (t Base) PostCommit() error {
+	if err := t.saveConfigRevision(); err != nil {
+		t.log.Warn().Err(err).Msg("save configuration revision")
+	}
 	return nil
 }
@@ -0,0 +1,75 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/file"
+	"opensvc.com/opensvc/util/key"
+)
+
+// loadConfigParents sets the Parent of cfg to the configuration of the
+// template object referenced by its DEFAULT.extends keyword, and so on
+// for the template configurations.
+//
+// The templates must be in the object namespace or in the namespace set
+// by node.template_namespace. The references in the inherited keys are
+// evaluated by the object, so a {sec:...} reference of a shared template
+// designates a keystore of the object namespace.
+func (t Base) loadConfigParents(cfg *xconfig.T) error {
+	cfg.Parent = nil
+	chain := []string{t.Path.String()}
+	seen := map[path.T]interface{}{t.Path: nil}
+	k := key.New("DEFAULT", "extends")
+	ns := t.Path.Namespace
+	for c := cfg; ; {
+		s := c.Get(k)
+		if s == "" {
+			return nil
+		}
+		p, err := path.ParseIn(s, ns)
+		if err != nil {
+			return errors.Wrapf(err, "%s extends %s", c.Path, s)
+		}
+		if p.Namespace != t.Path.Namespace && p.Namespace != t.templateNamespace() {
+			return fmt.Errorf("%s extends %s: template object is not in the %s namespace nor in the template namespace", c.Path, p, t.Path.Namespace)
+		}
+		chain = append(chain, p.String())
+		if _, ok := seen[p]; ok {
+			return fmt.Errorf("extends cycle: %s", strings.Join(chain, " -> "))
+		}
+		seen[p] = nil
+		f := Base{Path: p}.standardConfigFile()
+		if !file.Exists(f) {
+			return fmt.Errorf("%s extends %s: template object does not exist", c.Path, p)
+		}
+		parent, err := xconfig.NewObject(f)
+		if err != nil {
+			return errors.Wrapf(err, "%s extends %s", c.Path, p)
+		}
+		parent.Path = p
+		c.Parent = parent
+		c = parent
+		ns = p.Namespace
+	}
+}
+
+// templateNamespace returns the namespace of the templates any object can
+// extend, or an empty string if node.template_namespace is not set.
+func (t Base) templateNamespace() string {
+	return t.Node().MergedConfig().GetString(key.Parse("node.template_namespace"))
+}
+
+// Extends returns true if the object configuration extends the template
+// object at path p, directly or through other templates.
+func (t Base) Extends(p path.T) bool {
+	for _, e := range t.config.Extends() {
+		if e == p {
+			return true
+		}
+	}
+	return false
+}
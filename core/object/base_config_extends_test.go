@@ -0,0 +1,79 @@
+package object
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+func TestLoadConfigParents(t *testing.T) {
+	etcNs := t.TempDir()
+	saved := rawconfig.Node.Paths.EtcNs
+	rawconfig.Node.Paths.EtcNs = etcNs
+	defer func() { rawconfig.Node.Paths.EtcNs = saved }()
+
+	install := func(p, s string) {
+		f := filepath.Join(etcNs, p+".conf")
+		require.NoError(t, os.MkdirAll(filepath.Dir(f), 0700))
+		require.NoError(t, ioutil.WriteFile(f, []byte(s), 0600))
+	}
+	install("ns1/svc/tpl", "[DEFAULT]\nnodes = n1 n2\n")
+	install("ns1/svc/bad", "[DEFAULT\n")
+	install("ns2/svc/tpl", "[DEFAULT]\nnodes = n3\n")
+
+	load := func(extends string) error {
+		p, err := path.Parse("ns1/svc/s1")
+		require.NoError(t, err)
+		install("ns1/svc/s1", "[DEFAULT]\nextends = "+extends+"\n")
+		o := Base{Path: p}
+		cfg, err := xconfig.NewObject(o.standardConfigFile())
+		require.NoError(t, err)
+		cfg.Path = p
+		return o.loadConfigParents(cfg)
+	}
+	assert.NoError(t, load("tpl"), "template in the object namespace")
+	assert.NoError(t, load("ns1/svc/tpl"))
+	assert.Error(t, load("ns2/svc/tpl"), "template in another namespace")
+	assert.Error(t, load("nope"), "missing template")
+	assert.Error(t, load("bad"), "invalid template")
+	assert.Error(t, load("s1"), "extends cycle")
+}
+
+func TestLoadConfigParentsTemplateNamespace(t *testing.T) {
+	setTestRoot(t, "10")
+	f := filepath.Join(rawconfig.Node.Paths.Etc, "node.conf")
+	require.NoError(t, ioutil.WriteFile(f, []byte("[node]\ntemplate_namespace = templates\n"), 0600))
+
+	install := func(s, conf string) path.T {
+		p, err := path.Parse(s)
+		require.NoError(t, err)
+		installTestConfig(t, p, conf)
+		return p
+	}
+	install("templates/svc/base", "[DEFAULT]\nnodes = n1 n2\n")
+	install("templates/svc/web", "[DEFAULT]\nextends = base\napp = {cfg:c1/k1}\n")
+	install("ns2/svc/tpl", "[DEFAULT]\nnodes = n3\n")
+	c1 := install("ns1/cfg/c1", "[DEFAULT]\n")
+	require.NoError(t, NewCfg(c1).Add(OptsAdd{Key: "k1", Value: "ns1 value"}))
+	c1 = install("templates/cfg/c1", "[DEFAULT]\n")
+	require.NoError(t, NewCfg(c1).Add(OptsAdd{Key: "k1", Value: "templates value"}))
+
+	p := install("ns1/svc/s1", "[DEFAULT]\nextends = ns2/svc/tpl\n")
+	assert.Nil(t, NewSvc(p).Config(), "template in another namespace")
+
+	install("ns1/svc/s1", "[DEFAULT]\nextends = templates/svc/web\n")
+	o := NewSvc(p)
+	require.NotNil(t, o.Config())
+	assert.Equal(t, "n1 n2", o.Config().Get(key.Parse("nodes")), "relative extends resolved in the template namespace")
+	v, err := o.Config().Eval(key.Parse("app"))
+	require.NoError(t, err)
+	assert.Equal(t, "ns1 value", v, "reference resolved in the object namespace")
+}
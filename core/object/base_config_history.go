@@ -0,0 +1,311 @@
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/file"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/render/tree"
+)
+
+type (
+	// ConfigRevision describes a saved revision of the object
+	// configuration file.
+	ConfigRevision struct {
+		Rev     int       `json:"rev"`
+		Time    time.Time `json:"time"`
+		User    string    `json:"user"`
+		Command string    `json:"command"`
+	}
+
+	// ConfigRevisions is the list of saved revisions, ordered by
+	// increasing revision number.
+	ConfigRevisions []ConfigRevision
+
+	// OptsConfigHistory is the options of the ConfigHistory object method.
+	OptsConfigHistory struct {
+		Global OptsGlobal
+	}
+
+	// OptsConfigDiff is the options of the ConfigDiff object method.
+	OptsConfigDiff struct {
+		Global OptsGlobal
+		Revs   []string `flag:"configrevs"`
+	}
+
+	// OptsConfigRestore is the options of the ConfigRestore object method.
+	OptsConfigRestore struct {
+		Global OptsGlobal
+		Lock   OptsLocking
+		Rev    int `flag:"configrev"`
+	}
+)
+
+var (
+	// ErrConfigRevision is returned when a configuration revision is
+	// not found in the history.
+	ErrConfigRevision = errors.New("configuration revision not found")
+)
+
+func (t ConfigRevisions) Render() string {
+	return t.Tree().Render()
+}
+
+// Tree returns a tree loaded with the type instance.
+func (t ConfigRevisions) Tree() *tree.Tree {
+	tree := tree.New()
+	t.LoadTreeNode(tree.Head())
+	return tree
+}
+
+// LoadTreeNode add the tree nodes representing the type instance into another.
+func (t ConfigRevisions) LoadTreeNode(head *tree.Node) {
+	head.AddColumn().AddText("rev").SetColor(rawconfig.Node.Color.Bold)
+	head.AddColumn().AddText("time").SetColor(rawconfig.Node.Color.Bold)
+	head.AddColumn().AddText("user").SetColor(rawconfig.Node.Color.Bold)
+	head.AddColumn().AddText("command").SetColor(rawconfig.Node.Color.Bold)
+	for _, e := range t {
+		n := head.AddNode()
+		e.LoadTreeNode(n)
+	}
+}
+
+// LoadTreeNode add the tree nodes representing the type instance into another.
+func (t ConfigRevision) LoadTreeNode(head *tree.Node) {
+	head.AddColumn().AddText(strconv.Itoa(t.Rev)).SetColor(rawconfig.Node.Color.Primary)
+	head.AddColumn().AddText(t.Time.Format(time.RFC3339))
+	head.AddColumn().AddText(t.User)
+	head.AddColumn().AddText(t.Command)
+}
+
+// configHistoryDir returns the directory hosting the configuration
+// revisions of the object.
+func (t Base) configHistoryDir() string {
+	return filepath.Join(t.VarDir(), "config_history")
+}
+
+func (t Base) configRevisionFile(rev int) string {
+	return filepath.Join(t.configHistoryDir(), fmt.Sprintf("%d.conf", rev))
+}
+
+func (t Base) configRevisionMetaFile(rev int) string {
+	return filepath.Join(t.configHistoryDir(), fmt.Sprintf("%d.json", rev))
+}
+
+// configHistoryMax returns the number of revisions to keep, from the
+// node.config_history_max keyword.
+func (t Base) configHistoryMax() int {
+	return t.Node().MergedConfig().GetInt(key.Parse("node.config_history_max"))
+}
+
+// ConfigHistory returns the saved revisions of the object configuration.
+func (t Base) ConfigHistory(options OptsConfigHistory) (ConfigRevisions, error) {
+	return t.configRevisions()
+}
+
+func (t Base) configRevisions() (ConfigRevisions, error) {
+	l := make(ConfigRevisions, 0)
+	matches, err := filepath.Glob(filepath.Join(t.configHistoryDir(), "*.json"))
+	if err != nil {
+		return l, err
+	}
+	for _, p := range matches {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return l, err
+		}
+		var rev ConfigRevision
+		if err := json.Unmarshal(b, &rev); err != nil {
+			return l, errors.Wrapf(err, "%s", p)
+		}
+		l = append(l, rev)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].Rev < l[j].Rev })
+	return l, nil
+}
+
+// saveConfigRevision saves a copy of the configuration file as a new
+// revision, unless it is identical to the last revision, then prunes
+// the revisions exceeding node.config_history_max.
+func (t Base) saveConfigRevision() error {
+	if t.IsVolatile() || !file.Exists(t.ConfigFile()) {
+		return nil
+	}
+	max := t.configHistoryMax()
+	if max <= 0 {
+		return nil
+	}
+	l, err := t.configRevisions()
+	if err != nil {
+		return err
+	}
+	rev := 1
+	if len(l) > 0 {
+		last := l[len(l)-1].Rev
+		if same, err := sameFileContent(t.ConfigFile(), t.configRevisionFile(last)); err == nil && same {
+			return nil
+		}
+		rev = last + 1
+	}
+	if err := os.MkdirAll(t.configHistoryDir(), 0700); err != nil {
+		return err
+	}
+	if err := file.Copy(t.ConfigFile(), t.configRevisionFile(rev)); err != nil {
+		return err
+	}
+	meta := ConfigRevision{
+		Rev:     rev,
+		Time:    time.Now(),
+		User:    currentUsername(),
+		Command: redactedCommand(os.Args),
+	}
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(t.configRevisionMetaFile(rev), b, 0600); err != nil {
+		return err
+	}
+	l = append(l, meta)
+	for i := 0; i < len(l)-max; i++ {
+		_ = os.Remove(t.configRevisionFile(l[i].Rev))
+		_ = os.Remove(t.configRevisionMetaFile(l[i].Rev))
+	}
+	t.log.Debug().Int("rev", rev).Msg("configuration revision saved")
+	return nil
+}
+
+func sameFileContent(a, b string) (bool, error) {
+	ab, err := ioutil.ReadFile(a)
+	if err != nil {
+		return false, err
+	}
+	bb, err := ioutil.ReadFile(b)
+	if err != nil {
+		return false, err
+	}
+	return string(ab) == string(bb), nil
+}
+
+func currentUsername() string {
+	if s := os.Getenv("SUDO_USER"); s != "" {
+		return s
+	}
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
+
+// redactedCommand returns the command line, with the --value flag
+// values redacted, so the keystore values are not saved in the history.
+func redactedCommand(args []string) string {
+	l := make([]string, len(args))
+	redactNext := false
+	for i, s := range args {
+		switch {
+		case redactNext:
+			s = xconfig.RedactedValue
+			redactNext = false
+		case s == "--value":
+			redactNext = true
+		case strings.HasPrefix(s, "--value="):
+			s = "--value=" + xconfig.RedactedValue
+		}
+		l[i] = s
+	}
+	return strings.Join(l, " ")
+}
+
+// configRevisionRaw returns the configuration saved as revision rev.
+func (t Base) configRevisionRaw(rev int) (rawconfig.T, error) {
+	p := t.configRevisionFile(rev)
+	if !file.Exists(p) {
+		return rawconfig.T{}, errors.Wrapf(ErrConfigRevision, "rev %d", rev)
+	}
+	cfg, err := xconfig.NewObject(p)
+	if err != nil {
+		return rawconfig.T{}, err
+	}
+	return cfg.Raw(), nil
+}
+
+// ConfigDiff returns the key differences between two configuration
+// revisions. With no revision, the last saved revision differing from the
+// current configuration is compared to the current configuration. With a
+// single revision, this revision is compared to the current configuration.
+func (t Base) ConfigDiff(options OptsConfigDiff) (rawconfig.Changes, error) {
+	revs := make([]int, len(options.Revs))
+	for i, s := range options.Revs {
+		rev, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, errors.Wrapf(ErrConfigRevision, "invalid revision %s", s)
+		}
+		revs[i] = rev
+	}
+	current := t.config.Raw()
+	switch len(revs) {
+	case 0:
+		l, err := t.configRevisions()
+		if err != nil {
+			return nil, err
+		}
+		for i := len(l) - 1; i >= 0; i-- {
+			a, err := t.configRevisionRaw(l[i].Rev)
+			if err != nil {
+				return nil, err
+			}
+			if changes := rawconfig.Diff(a, current); len(changes) > 0 {
+				return changes, nil
+			}
+		}
+		return rawconfig.Changes{}, nil
+	case 1:
+		a, err := t.configRevisionRaw(revs[0])
+		if err != nil {
+			return nil, err
+		}
+		return rawconfig.Diff(a, current), nil
+	case 2:
+		a, err := t.configRevisionRaw(revs[0])
+		if err != nil {
+			return nil, err
+		}
+		b, err := t.configRevisionRaw(revs[1])
+		if err != nil {
+			return nil, err
+		}
+		return rawconfig.Diff(a, b), nil
+	default:
+		return nil, fmt.Errorf("at most two revisions can be compared")
+	}
+}
+
+// ConfigRestore commits the configuration saved as revision rev. The
+// restored configuration is validated, and saved as a new revision.
+func (t *Base) ConfigRestore(options OptsConfigRestore) error {
+	return t.lockedAction("", options.Lock, "config restore", func() error {
+		data, err := t.configRevisionRaw(options.Rev)
+		if err != nil {
+			return err
+		}
+		if err := t.config.CommitData(data); err != nil {
+			return err
+		}
+		t.log.Info().Int("rev", options.Rev).Msg("configuration revision restored")
+		return nil
+	})
+}
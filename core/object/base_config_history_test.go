@@ -0,0 +1,144 @@
+package object
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+// setTestRoot installs the agent paths in a temporary root, with
+// node.config_history_max set to historyMax.
+func setTestRoot(t *testing.T, historyMax string) {
+	root := t.TempDir()
+	saved := rawconfig.Node.Paths
+	rawconfig.Node.Paths.Etc = filepath.Join(root, "etc")
+	rawconfig.Node.Paths.EtcNs = filepath.Join(root, "etc", "namespaces")
+	rawconfig.Node.Paths.Var = filepath.Join(root, "var")
+	rawconfig.Node.Paths.Lock = filepath.Join(root, "var", "lock")
+	rawconfig.Node.Paths.Tmp = filepath.Join(root, "tmp")
+	rawconfig.Node.Paths.Log = filepath.Join(root, "log")
+	t.Cleanup(func() { rawconfig.Node.Paths = saved })
+
+	require.NoError(t, os.MkdirAll(rawconfig.Node.Paths.EtcNs, 0700))
+	nodeConf := "[node]\nconfig_history_max = " + historyMax + "\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(rawconfig.Node.Paths.Etc, "node.conf"), []byte(nodeConf), 0600))
+}
+
+// installTestConfig writes the configuration file of the object p.
+func installTestConfig(t *testing.T, p path.T, conf string) {
+	f := Base{Path: p}.standardConfigFile()
+	require.NoError(t, os.MkdirAll(filepath.Dir(f), 0700))
+	require.NoError(t, ioutil.WriteFile(f, []byte(conf), 0600))
+}
+
+// newTestSvc returns the ns1/svc/s1 object installed in a temporary root,
+// with node.config_history_max set to historyMax.
+func newTestSvc(t *testing.T, historyMax string) *Svc {
+	setTestRoot(t, historyMax)
+	p, err := path.Parse("ns1/svc/s1")
+	require.NoError(t, err)
+	installTestConfig(t, p, "[DEFAULT]\nenv = DEV\n")
+	o := NewSvc(p)
+	require.NotNil(t, o.config)
+	return o
+}
+
+func historyRevs(t *testing.T, o *Svc) []int {
+	l, err := o.ConfigHistory(OptsConfigHistory{})
+	require.NoError(t, err)
+	revs := make([]int, len(l))
+	for i, e := range l {
+		revs[i] = e.Rev
+	}
+	return revs
+}
+
+func TestSaveConfigRevision(t *testing.T) {
+	t.Run("identical configurations are saved once", func(t *testing.T) {
+		o := newTestSvc(t, "10")
+		require.NoError(t, o.saveConfigRevision())
+		require.NoError(t, o.saveConfigRevision())
+		assert.Equal(t, []int{1}, historyRevs(t, o))
+		require.NoError(t, o.SetKeywords([]string{"env=TST"}))
+		require.NoError(t, o.saveConfigRevision())
+		assert.Equal(t, []int{1, 2}, historyRevs(t, o))
+	})
+	t.Run("revisions exceeding the max are pruned", func(t *testing.T) {
+		o := newTestSvc(t, "2")
+		for _, env := range []string{"TST", "PRD", "UAT"} {
+			require.NoError(t, o.SetKeywords([]string{"env=" + env}))
+		}
+		assert.Equal(t, []int{2, 3}, historyRevs(t, o))
+		assert.NoFileExists(t, o.configRevisionFile(1))
+		assert.NoFileExists(t, o.configRevisionMetaFile(1))
+	})
+	t.Run("no history with a zero max", func(t *testing.T) {
+		o := newTestSvc(t, "0")
+		require.NoError(t, o.SetKeywords([]string{"env=TST"}))
+		assert.Empty(t, historyRevs(t, o))
+	})
+}
+
+func TestConfigDiff(t *testing.T) {
+	o := newTestSvc(t, "10")
+	require.NoError(t, o.SetKeywords([]string{"env=TST"}))
+	require.NoError(t, o.SetKeywords([]string{"env=PRD"}))
+	require.Equal(t, []int{1, 2}, historyRevs(t, o))
+
+	changes, err := o.ConfigDiff(OptsConfigDiff{Revs: []string{"1", "2"}})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "env", changes[0].Option)
+	assert.Equal(t, "TST", changes[0].From)
+	assert.Equal(t, "PRD", changes[0].To)
+
+	changes, err = o.ConfigDiff(OptsConfigDiff{Revs: []string{"2"}})
+	require.NoError(t, err)
+	assert.Empty(t, changes, "rev 2 is the current configuration")
+
+	changes, err = o.ConfigDiff(OptsConfigDiff{})
+	require.NoError(t, err)
+	require.Len(t, changes, 1, "the last differing revision is compared")
+	assert.Equal(t, "TST", changes[0].From)
+
+	_, err = o.ConfigDiff(OptsConfigDiff{Revs: []string{"foo"}})
+	assert.Equal(t, ErrConfigRevision, errors.Cause(err))
+	_, err = o.ConfigDiff(OptsConfigDiff{Revs: []string{"9"}})
+	assert.Equal(t, ErrConfigRevision, errors.Cause(err))
+	_, err = o.ConfigDiff(OptsConfigDiff{Revs: []string{"1", "2", "1"}})
+	assert.Error(t, err)
+}
+
+func TestConfigRestore(t *testing.T) {
+	o := newTestSvc(t, "10")
+	require.NoError(t, o.SetKeywords([]string{"env=TST"}))
+	require.NoError(t, o.SetKeywords([]string{"env=PRD"}))
+
+	require.NoError(t, o.ConfigRestore(OptsConfigRestore{Rev: 1}))
+	assert.Equal(t, "TST", o.config.GetString(key.Parse("env")))
+	assert.Equal(t, []int{1, 2, 3}, historyRevs(t, o), "the restored configuration is saved as a new revision")
+
+	err := o.ConfigRestore(OptsConfigRestore{Rev: 9})
+	assert.Equal(t, ErrConfigRevision, errors.Cause(err))
+	assert.Equal(t, "TST", o.config.GetString(key.Parse("env")))
+}
+
+func TestRedactedCommand(t *testing.T) {
+	assert.Equal(t,
+		"om sec/s1 add --key k --value xxxx",
+		redactedCommand([]string{"om", "sec/s1", "add", "--key", "k", "--value", "s3cr3t"}))
+	assert.Equal(t,
+		"om sec/s1 change --value=xxxx --key k",
+		redactedCommand([]string{"om", "sec/s1", "change", "--value=s3cr3t", "--key", "k"}))
+	assert.Equal(t,
+		"om t1 set --kw app#1.start=/bin/true",
+		redactedCommand([]string{"om", "t1", "set", "--kw", "app#1.start=/bin/true"}))
+}
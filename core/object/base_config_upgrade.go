@@ -0,0 +1,36 @@
+package object
+
+import (
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+// OptsConfigUpgrade is the options of the ConfigUpgrade object method.
+type OptsConfigUpgrade struct {
+	Global OptsGlobal
+	Lock   OptsLocking
+}
+
+// ConfigUpgrade rewrites the configuration file to the current keyword
+// names and value formats, so it no longer depends on the keyword
+// aliases and legacy value formats accepted for compatibility with
+// older agents. With the dry-run option, the changes are returned but
+// not applied.
+func (t *Base) ConfigUpgrade(options OptsConfigUpgrade) (rawconfig.Changes, error) {
+	changes := t.config.UpgradeChanges()
+	if options.Global.DryRun || len(changes) == 0 {
+		return changes, nil
+	}
+	err := t.lockedAction("", options.Lock, "config upgrade", func() error {
+		if err := t.config.ApplyChanges(changes); err != nil {
+			return err
+		}
+		// don't refuse to upgrade a configuration already invalid
+		// for other reasons.
+		if err := t.config.CommitInvalid(); err != nil {
+			return err
+		}
+		t.log.Info().Int("changes", len(changes)).Msg("configuration upgraded")
+		return nil
+	})
+	return changes, err
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/hexops/gotextdiff/span"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+	"opensvc.com/opensvc/core/xconfig"
 	"opensvc.com/opensvc/util/editor"
 	"opensvc.com/opensvc/util/file"
 )
@@ -38,6 +39,29 @@ func Diff(a, b string) (string, error) {
 	return fmt.Sprint(gotextdiff.ToUnified(a, b, string(ab), edits)), nil
 }
 
+// validateEditedConfig returns an error if the configuration file at path
+// has error alerts.
+func (t Base) validateEditedConfig(p string) error {
+	cfg, err := xconfig.NewObject(p)
+	if err != nil {
+		return err
+	}
+	cfg.Path = t.Path
+	cfg.Referrer = &t
+	cfg.NodeReferrer = t.Node()
+	if err := t.loadConfigParents(cfg); err != nil {
+		return err
+	}
+	alerts, err := cfg.Validate()
+	if err != nil {
+		return err
+	}
+	if alerts.HasError() {
+		return errors.Wrapf(xconfig.ErrInvalid, "%s", alerts.Errors())
+	}
+	return nil
+}
+
 func (t Base) EditConfig(opts OptsEditConfig) (err error) {
 	var (
 		refSum []byte
@@ -74,10 +98,15 @@ func (t Base) EditConfig(opts OptsEditConfig) (err error) {
 	if file.HaveSameMD5(refSum, dst) {
 		fmt.Println("unchanged")
 	} else {
-		// TODO: Validate dst
+		if err = t.validateEditedConfig(dst); err != nil {
+			return errors.Wrapf(err, "%s kept for --recover or --discard", dst)
+		}
 		if err = file.Copy(dst, src); err != nil {
 			return err
 		}
+		if err = t.saveConfigRevision(); err != nil {
+			t.log.Warn().Err(err).Msg("save configuration revision")
+		}
 	}
 	if err = os.Remove(dst); err != nil {
 		return err
@@ -0,0 +1,226 @@
+package object
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/stringslice"
+)
+
+type (
+	// KeystoreRef is a reference to a cfg or sec key, used in a keyword
+	// value as {cfg:<name>/<key>} or {sec:<name>/<key>}. The referenced
+	// keystore is in the namespace of the referencing object.
+	KeystoreRef struct {
+		Path path.T
+		Key  string
+	}
+
+	// KeystoreReferrer is implemented by objects whose configuration can
+	// reference cfg and sec keys.
+	KeystoreReferrer interface {
+		KeystoreRefs() []KeystoreRef
+	}
+)
+
+// parseKeystoreRef parses a <kind>:<name>/<key> reference, the braces
+// already stripped.
+func parseKeystoreRef(ref string, namespace string) (KeystoreRef, error) {
+	l := strings.SplitN(ref, ":", 2)
+	if len(l) != 2 {
+		return KeystoreRef{}, fmt.Errorf("invalid keystore reference: %s", ref)
+	}
+	switch kind.New(l[0]) {
+	case kind.Cfg, kind.Sec:
+	default:
+		return KeystoreRef{}, fmt.Errorf("invalid keystore reference kind: %s", ref)
+	}
+	nameKey := strings.SplitN(l[1], "/", 2)
+	if len(nameKey) != 2 || nameKey[1] == "" {
+		return KeystoreRef{}, fmt.Errorf("invalid keystore reference, expected %s:<name>/<key>: %s", l[0], ref)
+	}
+	p, err := path.New(nameKey[0], namespace, l[0])
+	if err != nil {
+		return KeystoreRef{}, errors.Wrapf(err, "keystore reference %s", ref)
+	}
+	return KeystoreRef{Path: p, Key: nameKey[1]}, nil
+}
+
+func (t KeystoreRef) String() string {
+	return fmt.Sprintf("%s:%s/%s", t.Path.Kind, t.Path.Name, t.Key)
+}
+
+// dereferenceKeystore returns the decoded value of a cfg or sec key
+// referenced as <kind>:<name>/<key>. Only the keystores of the object
+// namespace can be referenced.
+func (t Base) dereferenceKeystore(ref string) (string, error) {
+	r, err := parseKeystoreRef(ref, t.Path.Namespace)
+	if err != nil {
+		return ref, err
+	}
+	o, ok := NewFromPath(r.Path).(Keystorer)
+	if !ok {
+		return ref, fmt.Errorf("%s is not a keystore", r.Path)
+	}
+	if !o.(Baser).Exists() {
+		return ref, fmt.Errorf("%s does not exist", r.Path)
+	}
+	b, err := o.Decode(OptsDecode{Key: r.Key})
+	if err != nil {
+		return ref, errors.Wrapf(err, "%s", r.Path)
+	}
+	return string(b), nil
+}
+
+// KeystoreRefs returns the cfg and sec keys referenced by the object
+// configuration, sorted and deduplicated.
+func (t Base) KeystoreRefs() []KeystoreRef {
+	m := make(map[string]KeystoreRef)
+	data := t.config.Raw().Data
+	for _, section := range data.Keys() {
+		i, _ := data.Get(section)
+		sectionMap := i.(orderedmap.OrderedMap)
+		for _, option := range sectionMap.Keys() {
+			i, _ := sectionMap.Get(option)
+			value, _ := i.(string)
+			for _, ref := range rawconfig.RegexpReference.FindAllString(value, -1) {
+				ref = ref[1 : len(ref)-1]
+				if !strings.HasPrefix(ref, "cfg:") && !strings.HasPrefix(ref, "sec:") {
+					continue
+				}
+				r, err := parseKeystoreRef(ref, t.Path.Namespace)
+				if err != nil {
+					t.log.Debug().Err(err).Stringer("key", key.New(section, option)).Msg("keystore references")
+					continue
+				}
+				m[r.String()] = r
+			}
+		}
+	}
+	l := make([]KeystoreRef, 0, len(m))
+	for _, r := range m {
+		l = append(l, r)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].String() < l[j].String() })
+	return l
+}
+
+// Dependents returns the paths of the installed objects whose
+// configuration references one of the keys of the keystore.
+func (t Keystore) Dependents(keys ...string) ([]path.T, error) {
+	m, err := t.dependentRefs(keys...)
+	if err != nil {
+		return []path.T{}, err
+	}
+	l := make([]path.T, 0, len(m))
+	for p := range m {
+		l = append(l, p)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].String() < l[j].String() })
+	return l, nil
+}
+
+// dependentRefs returns the references to the keys of the keystore,
+// indexed by the path of the installed objects making them.
+func (t Keystore) dependentRefs(keys ...string) (map[path.T][]KeystoreRef, error) {
+	m := make(map[path.T][]KeystoreRef)
+	paths, err := Installed()
+	if err != nil {
+		return m, err
+	}
+	for _, p := range paths {
+		if p.Namespace != t.Path.Namespace || p == t.Path {
+			continue
+		}
+		o, ok := NewFromPath(p).(KeystoreReferrer)
+		if !ok {
+			continue
+		}
+		for _, r := range o.KeystoreRefs() {
+			if r.Path == t.Path && (len(keys) == 0 || stringslice.Has(r.Key, keys)) {
+				m[p] = append(m[p], r)
+			}
+		}
+	}
+	return m, nil
+}
+
+// flagDependents records the changed key references in the objects
+// referencing them, so their status reports a restart is needed to
+// apply the new values.
+func (t Keystore) flagDependents(keys ...string) {
+	m, err := t.dependentRefs(keys...)
+	if err != nil {
+		t.log.Warn().Err(err).Msg("search keystore dependents")
+		return
+	}
+	for p, refs := range m {
+		l := make([]string, len(refs))
+		for i, r := range refs {
+			l[i] = r.String()
+		}
+		if err := addKeystoreChanged(Base{Path: p}.VarDir(), l...); err != nil {
+			t.log.Warn().Err(err).Stringer("dependent", p).Msg("flag keystore dependent")
+			continue
+		}
+		t.log.Info().Strs("refs", l).Stringer("dependent", p).Msg("dependent object references changed keys, restart it to apply")
+	}
+}
+
+// keystoreChangedFile is the path of the file listing the changed
+// keystore references not yet applied by a start of the object.
+func keystoreChangedFile(varDir string) string {
+	return filepath.Join(varDir, "keystore_changed")
+}
+
+// addKeystoreChanged merges refs into the changed keystore references
+// file of the object hosted in varDir.
+func addKeystoreChanged(varDir string, refs ...string) error {
+	p := keystoreChangedFile(varDir)
+	m := make(map[string]interface{})
+	for _, ref := range append(readKeystoreChanged(p), refs...) {
+		m[ref] = nil
+	}
+	l := make([]string, 0, len(m))
+	for ref := range m {
+		l = append(l, ref)
+	}
+	sort.Strings(l)
+	if err := os.MkdirAll(varDir, os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p, []byte(strings.Join(l, "\n")+"\n"), 0644)
+}
+
+func readKeystoreChanged(p string) []string {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return []string{}
+	}
+	return strings.Fields(string(b))
+}
+
+// KeystoreChanged returns the keystore references changed since the
+// last start of the object.
+func (t *Base) KeystoreChanged() []string {
+	return readKeystoreChanged(keystoreChangedFile(t.varDir()))
+}
+
+// clearKeystoreChanged removes the changed keystore references flag,
+// the new values being applied by a start.
+func (t *Base) clearKeystoreChanged() {
+	p := keystoreChangedFile(t.varDir())
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		t.log.Warn().Err(err).Msg("clear keystore changed flag")
+	}
+}
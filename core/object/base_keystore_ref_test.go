@@ -0,0 +1,64 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+)
+
+func TestParseKeystoreRef(t *testing.T) {
+	cases := map[string]struct {
+		ref  string
+		path string
+		key  string
+		err  bool
+	}{
+		"sec":         {ref: "sec:db-creds/password", path: "ns1/sec/db-creds", key: "password"},
+		"cfg":         {ref: "cfg:app/port", path: "ns1/cfg/app", key: "port"},
+		"key path":    {ref: "cfg:app/conf/nginx.conf", path: "ns1/cfg/app", key: "conf/nginx.conf"},
+		"no key":      {ref: "sec:db-creds", err: true},
+		"empty key":   {ref: "sec:db-creds/", err: true},
+		"bad kind":    {ref: "svc:db/password", err: true},
+		"bad name":    {ref: "sec:db_creds/password", err: true},
+		"no kind sep": {ref: "db-creds/password", err: true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := parseKeystoreRef(c.ref, "ns1")
+			if c.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.path, r.Path.String())
+			assert.Equal(t, c.key, r.Key)
+			assert.Equal(t, c.ref, r.String())
+		})
+	}
+}
+
+func TestKeystoreFlagDependents(t *testing.T) {
+	o := newTestSvc(t, "10")
+	installTestConfig(t, o.Path, "[DEFAULT]\nenv = DEV\n\n[app#1]\ntype = forking\nstart = /bin/echo {cfg:c1/k1}\n")
+	p, err := path.Parse("ns1/cfg/c1")
+	require.NoError(t, err)
+	installTestConfig(t, p, "[DEFAULT]\n")
+	c := NewCfg(p)
+
+	require.NoError(t, c.Add(OptsAdd{Key: "k2", Value: "v"}))
+	assert.Empty(t, o.KeystoreChanged(), "unreferenced key")
+
+	require.NoError(t, c.Add(OptsAdd{Key: "k1", Value: "v"}))
+	assert.Equal(t, []string{"cfg:c1/k1"}, o.KeystoreChanged())
+	l, err := c.Dependents("k1")
+	require.NoError(t, err)
+	assert.Equal(t, []path.T{o.Path}, l)
+
+	require.NoError(t, c.Change(OptsAdd{Key: "k1", Value: "v2"}))
+	assert.Equal(t, []string{"cfg:c1/k1"}, o.KeystoreChanged(), "references are recorded once")
+
+	o.clearKeystoreChanged()
+	assert.Empty(t, o.KeystoreChanged())
+}
@@ -1,15 +1,20 @@
 package object
 
 import (
+	"sort"
+
+	"opensvc.com/opensvc/core/drivergroup"
 	"opensvc.com/opensvc/core/envs"
 	"opensvc.com/opensvc/core/keywords"
 	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/manifest"
 	"opensvc.com/opensvc/core/placement"
 	"opensvc.com/opensvc/core/resource"
 	"opensvc.com/opensvc/core/resourceid"
 	"opensvc.com/opensvc/core/xconfig"
 	"opensvc.com/opensvc/util/converters"
 	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/stringslice"
 )
 
 var keywordStore = keywords.Store{
@@ -44,6 +49,12 @@ var keywordStore = keywords.Store{
 		Text:    "Allow service process to bind only the specified cpus. Cpus are specified as list or range : 0,1,2 or 0-2",
 		Example: "0-2",
 	},
+	{
+		Section: "DEFAULT",
+		Option:  "extends",
+		Text:    "The path of a template object whose configuration is extended. The keywords and sections not set in this configuration are inherited from the template, which can itself extend another template. The template must be in the object namespace, which is the default namespace of the path, or in the namespace set by the node.template_namespace keyword. The sec and cfg references in the inherited keys are resolved in the object namespace. The configuration can not be loaded if the template does not exist or is invalid.",
+		Example: "svc/web-tpl",
+	},
 	{
 		Section:     "DEFAULT",
 		Option:      "nodes",
@@ -75,6 +86,7 @@ var keywordStore = keywords.Store{
 	{
 		Section:     "DEFAULT",
 		Option:      "env",
+		Aliases:     []string{"service_type"},
 		DefaultText: "Same as the node env",
 		Candidates:  envs.List,
 		Text:        "A non-PRD service can not be brought up on a PRD node, but a PRD service can be startup on a non-PRD node (in a DRP situation). The default value is the node :kw:`env`.",
@@ -97,6 +109,7 @@ var keywordStore = keywords.Store{
 	{
 		Section:    "DEFAULT",
 		Option:     "topology",
+		Aliases:    []string{"cluster_type"},
 		Default:    "failover",
 		Candidates: []string{"failover", "flex"},
 		Text:       "``failover`` the service is allowed to be up on one node at a time. ``flex`` the service can be up on :kw:`flex_target` nodes, where :kw:`flex_target` must be in the [flex_min, flex_max] range.",
@@ -104,6 +117,7 @@ var keywordStore = keywords.Store{
 	{
 		Section:   "DEFAULT",
 		Option:    "flex_min",
+		Aliases:   []string{"flex_min_nodes"},
 		Default:   "1",
 		Converter: converters.Int,
 		//Depends: []keyval.T{
@@ -114,6 +128,7 @@ var keywordStore = keywords.Store{
 	{
 		Section:     "DEFAULT",
 		Option:      "flex_max",
+		Aliases:     []string{"flex_max_nodes"},
 		DefaultText: "Number of svc nodes",
 		Converter:   converters.Int,
 		//Depends: []keyval.T{
@@ -166,7 +181,39 @@ var keywordStore = keywords.Store{
 		Text:      "If set to ``true``, actions are executed in parallel amongst the subset member resources.",
 	},
 
+	// Volumes
+	{
+		Section: "DEFAULT",
+		Option:  "pool",
+		Text:    "The name of the pool this volume was allocated from.",
+		Kind:    kind.Or(kind.Vol),
+	},
+	{
+		Section:   "DEFAULT",
+		Option:    "size",
+		Converter: converters.Size,
+		Text:      "The size of the volume, as requested from its pool. Updated by the :cmd:`om vol resize` action.",
+		Example:   "10g",
+		Kind:      kind.Or(kind.Vol),
+	},
+	{
+		Section:    "DEFAULT",
+		Option:     "access",
+		Default:    "rwo",
+		Candidates: []string{"rwo", "roo", "rwx", "rox"},
+		Text:       "The access mode of the volume. ``rwo`` is Read Write Once, ``roo`` is Read Only Once, ``rwx`` is Read Write Many, ``rox`` is Read Only Many. ``rox`` and ``rwx`` modes are served by flex volume services.",
+		Kind:       kind.Or(kind.Vol),
+	},
+
 	// Secrets
+	{
+		Section:   "DEFAULT",
+		Option:    "grant",
+		Converter: converters.List,
+		Text:      "The whitespace separated list of ``<role>[:<namespace>]`` grants of the user. The namespaced roles are ``admin``, ``operator`` and ``guest``. The cluster-wide roles are ``root``, ``squatter``, ``prioritizer`` and ``blacklistadmin``.",
+		Example:   "admin:test1 guest:test2 prioritizer",
+		Kind:      kind.Or(kind.Usr),
+	},
 	{
 		Section:  "DEFAULT",
 		Option:   "cn",
@@ -237,11 +284,21 @@ var keywordStore = keywords.Store{
 		Option:    "bits",
 		Converter: converters.Size,
 		Scopable:  true,
-		Text:      "Certificate Private Key Length.",
+		Text:      "Certificate Private Key Length. Only used with the ``rsa`` :kw:`key_type`.",
 		Default:   "4kib",
 		Example:   "8192",
 		Kind:      kind.Or(kind.Sec),
 	},
+	{
+		Section:    "DEFAULT",
+		Option:     "key_type",
+		Scopable:   true,
+		Candidates: []string{"rsa", "ecdsa-p256", "ecdsa-p384", "ed25519"},
+		Text:       "Certificate Private Key Type. The private key is stored in the PKCS#8 format. Changing the key type causes a new private key generation on the next certificate generation. A certificate can be signed by a :kw:`ca` using a different key type.",
+		Default:    "rsa",
+		Example:    "ecdsa-p256",
+		Kind:       kind.Or(kind.Sec),
+	},
 	{
 		Section:   "DEFAULT",
 		Option:    "rollback",
@@ -268,6 +325,32 @@ var keywordStore = keywords.Store{
 		Example:  "ca",
 		Kind:     kind.Or(kind.Sec),
 	},
+	{
+		Section:  "DEFAULT",
+		Option:   "cert_renew_schedule",
+		Scopable: true,
+		Text:     "The schedule of the certificate renewal, for secrets with a :kw:`ca`. The certificate is renewed only if it expires within :kw:`cert_renew_within`. See ``usr/share/doc/schedule`` for the schedule syntax.",
+		Default:  "02:00-04:00",
+		Kind:     kind.Or(kind.Sec),
+	},
+	{
+		Section:   "DEFAULT",
+		Option:    "cert_renew_within",
+		Converter: converters.Duration,
+		Scopable:  true,
+		Text:      "The scheduled certificate renewal happens when the certificate expires within this duration. The object status is ``warn`` when the certificate expires within this duration.",
+		Default:   "30d",
+		Example:   "7d",
+		Kind:      kind.Or(kind.Sec),
+	},
+	{
+		Section:  "DEFAULT",
+		Option:   "post_cert_renew",
+		Scopable: true,
+		Text:     "A command to execute after a certificate renewal, for example to signal the applications using the certificate to reload it. The ``OPENSVC_SEC_PATH`` environment variable is set to the secret path.",
+		Example:  "/usr/bin/systemctl reload nginx",
+		Kind:     kind.Or(kind.Sec),
+	},
 	{
 		Section:  "DEFAULT",
 		Option:   "monitor_schedule",
@@ -369,6 +452,9 @@ var keywordStore = keywords.Store{
 }
 
 func (t Base) KeywordLookup(k key.T, sectionType string) keywords.Keyword {
+	if t.Path.Kind == kind.Ccfg {
+		return ccfgKeywordLookup(k, sectionType)
+	}
 	switch k.Section {
 	case "data", "env":
 		return keywords.Keyword{
@@ -377,23 +463,98 @@ func (t Base) KeywordLookup(k key.T, sectionType string) keywords.Keyword {
 			Required: false,
 		}
 	}
-	rid := resourceid.Parse(k.Section)
-	driverGroupName := rid.DriverGroup().String()
-
 	if kw := keywordStore.Lookup(k, t.Path.Kind, sectionType); !kw.IsZero() {
 		// base keyword
 		return kw
 	}
+	if k.Option == "type" {
+		return sectionTypeKeyword(k.Section)
+	}
+	if m := sectionManifest(k.Section, sectionType); m != nil {
+		store := keywords.Store(m.Keywords)
+		return store.Lookup(k, t.Path.Kind, sectionType)
+	}
+	return keywords.Keyword{}
+}
 
-	for _, newDRV := range resource.RegisteredGroupDrivers(driverGroupName) {
-		kws := newDRV().Manifest().Keywords
-		if kws == nil {
+// StrictKeywords returns true if the unknown keywords make the object
+// configuration invalid. This is the case of the cluster configuration,
+// where a mistyped keyword would silently be ignored by all nodes.
+func (t Base) StrictKeywords() bool {
+	return t.Path.Kind == kind.Ccfg
+}
+
+// SectionKeywords returns the keywords applicable to the section, for
+// the section type. Generic keywords, valid in all sections, are not
+// included.
+func (t Base) SectionKeywords(section string, sectionType string) []keywords.Keyword {
+	if t.Path.Kind == kind.Ccfg {
+		return ccfgSectionKeywords(section, sectionType)
+	}
+	l := make([]keywords.Keyword, 0)
+	driverGroupName := resourceid.Parse(section).DriverGroup().String()
+	add := func(kw keywords.Keyword) {
+		if !kw.Kind.Has(t.Path.Kind) {
+			return
+		}
+		if sectionType != "" && len(kw.Types) > 0 && !stringslice.Has(sectionType, kw.Types) {
+			return
+		}
+		l = append(l, kw)
+	}
+	for _, kw := range keywordStore {
+		if kw.Section != section && kw.Section != driverGroupName {
 			continue
 		}
-		store := keywords.Store(kws)
-		if kw := store.Lookup(k, t.Path.Kind, sectionType); !kw.IsZero() {
-			return kw
+		add(kw)
+	}
+	if m := sectionManifest(section, sectionType); m != nil {
+		for _, kw := range m.Keywords {
+			add(kw)
 		}
 	}
-	return keywords.Keyword{}
+	return l
+}
+
+// sectionTypeKeyword returns the type keyword of a resource section, whose
+// candidates are the names of the drivers registered in the section driver
+// group. The candidates are not set if the driver group has a generic driver,
+// like the volume driver group whose type points a pool driver.
+func sectionTypeKeyword(section string) keywords.Keyword {
+	driverGroup := resourceid.Parse(section).DriverGroup()
+	if driverGroup == drivergroup.Unknown {
+		return keywords.Keyword{}
+	}
+	kw := keywords.Keyword{
+		Section: driverGroup.String(),
+		Option:  "type",
+		Text:    "The resource driver name.",
+	}
+	if resource.NewDriverID(driverGroup, "").NewResourceFunc() != nil {
+		return kw
+	}
+	for drvID := range resource.RegisteredGroupDrivers(driverGroup.String()) {
+		kw.Candidates = append(kw.Candidates, drvID.Name)
+	}
+	sort.Strings(kw.Candidates)
+	return kw
+}
+
+// sectionManifest returns the manifest of the resource driver selected
+// by the section name and type, or nil if the section is not a resource
+// section or the driver is not found.
+func sectionManifest(section string, sectionType string) *manifest.T {
+	driverGroup := resourceid.Parse(section).DriverGroup()
+	if driverGroup == drivergroup.Unknown {
+		return nil
+	}
+	driverName := sectionType
+	if driverName == "" {
+		driverName = DefaultDriver[driverGroup.String()]
+	}
+	factory := resource.NewDriverID(driverGroup, driverName).NewResourceFunc()
+	if factory == nil {
+		return nil
+	}
+	return factory().Manifest()
 }
@@ -2,6 +2,7 @@ package object
 
 import (
 	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/xconfig"
 )
 
 // OptsPrintConfig is the options of the PrintConfig object method.
@@ -10,13 +11,21 @@ type OptsPrintConfig struct {
 	Lock        OptsLocking
 	Eval        bool   `flag:"eval"`
 	Impersonate string `flag:"impersonate"`
+	Matrix      bool   `flag:"matrix"`
 }
 
-// PrintConfig gets a keyword value
+// PrintConfig returns the object configuration. With the Eval option, the
+// values are dereferenced and descoped for the Impersonate node, and the
+// values of the referenced sec keys are redacted.
 func (t *Base) PrintConfig(options OptsPrintConfig) (rawconfig.T, error) {
 	if options.Eval {
-		// TODO
-		return rawconfig.T{}, nil
+		return t.config.RawEvaluated(options.Impersonate)
 	}
 	return t.config.Raw(), nil
 }
+
+// PrintConfigMatrix returns the object configuration values evaluated for
+// each of the object nodes and drpnodes.
+func (t *Base) PrintConfigMatrix(options OptsPrintConfig) (xconfig.Matrix, error) {
+	return t.config.EvalMatrix(), nil
+}
@@ -0,0 +1,82 @@
+package object
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/resourceselector"
+)
+
+// OptsRefreshData is the options of the RefreshData object method.
+type OptsRefreshData struct {
+	Global OptsGlobal
+	Lock   OptsLocking
+	resourceselector.Options
+	Watch    bool          `flag:"datawatch"`
+	Interval time.Duration `flag:"datainterval"`
+}
+
+// RefreshData re-installs the cfg and sec keys projected in the volumes
+// by the selected resources, if the source keystores changed, and sends
+// the configured signals to the processes using the changed files.
+//
+// With the Watch option, the refresh is repeated every Interval until
+// the process is interrupted.
+func (t *Base) RefreshData(options OptsRefreshData) error {
+	if !options.Watch {
+		return t.refreshDataOnce(options)
+	}
+	interval := options.Interval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sig)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := t.refreshDataOnce(options); err != nil {
+			t.log.Error().Err(err).Msg("refresh data")
+		}
+		select {
+		case <-sig:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Base) refreshDataOnce(options OptsRefreshData) error {
+	return t.lockedAction("", options.Lock, "refresh-data", func() error {
+		return t.lockedRefreshData(options)
+	})
+}
+
+func (t *Base) lockedRefreshData(options OptsRefreshData) error {
+	var errs error
+	l := resourceselector.New(t, resourceselector.WithOptions(options.Options))
+	for _, r := range l.Resources() {
+		if r.IsDisabled() {
+			continue
+		}
+		i, ok := r.(resource.DataRefresher)
+		if !ok {
+			continue
+		}
+		changed, err := i.RefreshData()
+		if err != nil {
+			t.log.Error().Err(err).Str("rid", r.RID()).Msg("refresh data")
+			errs = errors.Errorf("%s: %s", r.RID(), err)
+			continue
+		}
+		if changed {
+			t.log.Info().Str("rid", r.RID()).Msg("data refreshed")
+		}
+	}
+	return errs
+}
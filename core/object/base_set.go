@@ -36,6 +36,9 @@ func (t *Base) SetKeywords(kws []string) error {
 		changes++
 	}
 	if changes > 0 {
+		if err := t.loadConfigParents(t.config); err != nil {
+			return err
+		}
 		return t.config.Commit()
 	}
 	return nil
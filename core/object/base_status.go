@@ -94,6 +94,12 @@ func (t *Base) lockedStatusEval(ctx context.Context) (data instance.Status, err
 		data.Overall = status.NotApplicable
 		data.Optional = status.NotApplicable
 	}
+	if t.customStatusEval != nil {
+		t.customStatusEval(&data)
+	}
+	if len(t.KeystoreChanged()) > 0 {
+		data.Overall = status.Warn
+	}
 	if data.Topology == topology.Flex {
 		data.FlexTarget = t.FlexTarget()
 		data.FlexMin = t.FlexMin()
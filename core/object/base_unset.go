@@ -26,6 +26,9 @@ func (t *Base) unset(kws ...key.T) error {
 		changes += t.config.Unset(k)
 	}
 	if changes > 0 {
+		if err := t.loadConfigParents(t.config); err != nil {
+			return err
+		}
 		return t.config.Commit()
 	}
 	return nil
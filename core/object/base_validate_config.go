@@ -0,0 +1,15 @@
+package object
+
+import (
+	"opensvc.com/opensvc/core/xconfig"
+)
+
+// OptsValidateConfig is the options of the ValidateConfig object method.
+type OptsValidateConfig struct {
+	Global OptsGlobal
+}
+
+// ValidateConfig returns the list of problems found in the object configuration.
+func (t *Base) ValidateConfig(options OptsValidateConfig) (xconfig.Alerts, error) {
+	return t.config.Validate()
+}
@@ -1,8 +1,14 @@
 package object
 
 import (
+	"strings"
+
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/core/kind"
 	"opensvc.com/opensvc/core/path"
 	"opensvc.com/opensvc/util/funcopt"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/stringslice"
 )
 
 type (
@@ -17,9 +23,48 @@ type (
 	}
 )
 
+// ccfgKeywordStore is the store of the keywords accepted in the cluster
+// configuration, like cluster.name, hb#, arbitrator#, stonith# and pool#.
+// The node private keywords are only accepted in node.conf.
+var ccfgKeywordStore = keywords.Store(commonKeywords)
+
 // NewCcfg allocates a ccfg kind object.
 func NewCcfg(p path.T, opts ...funcopt.O) *Ccfg {
 	s := &Ccfg{}
 	s.Base.init(p, opts...)
 	return s
 }
+
+func ccfgKeywordLookup(k key.T, sectionType string) keywords.Keyword {
+	switch k.Section {
+	case "data", "env", "labels":
+		return keywords.Keyword{
+			Option:   "*", // trick IsZero()
+			Scopable: true,
+			Required: false,
+		}
+	case "DEFAULT":
+		// the object id generated on create
+		if k.Option == "id" {
+			return keywordStore.Lookup(k, kind.Ccfg, sectionType)
+		}
+	}
+	return ccfgKeywordStore.Lookup(k, kind.Invalid, sectionType)
+}
+
+// ccfgSectionKeywords returns the cluster keywords applicable to the
+// section, for the section type.
+func ccfgSectionKeywords(section string, sectionType string) []keywords.Keyword {
+	l := make([]keywords.Keyword, 0)
+	driverGroup := strings.Split(section, "#")[0]
+	for _, kw := range ccfgKeywordStore {
+		if kw.Section != section && kw.Section != driverGroup {
+			continue
+		}
+		if sectionType != "" && len(kw.Types) > 0 && !stringslice.Has(sectionType, kw.Types) {
+			continue
+		}
+		l = append(l, kw)
+	}
+	return l
+}
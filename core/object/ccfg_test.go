@@ -0,0 +1,54 @@
+package object
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/key"
+)
+
+func TestCcfgKeywordLookup(t *testing.T) {
+	for _, s := range []string{"cluster.name", "cluster.secret", "cluster.nodes", "hb#1.type", "arbitrator#1.name", "stonith#n1.cmd", "pool#p1.type", "DEFAULT.id", "env.foo"} {
+		t.Run(s, func(t *testing.T) {
+			assert.False(t, ccfgKeywordLookup(key.Parse(s), "").IsZero())
+		})
+	}
+	t.Run("node private keywords are not cluster keywords", func(t *testing.T) {
+		assert.False(t, nodeKeywordStore.Lookup(key.Parse("node.uuid"), 0, "").IsZero())
+		assert.True(t, ccfgKeywordLookup(key.Parse("node.uuid"), "").IsZero())
+	})
+	t.Run("unknown keywords", func(t *testing.T) {
+		assert.True(t, ccfgKeywordLookup(key.Parse("foo.bar"), "").IsZero())
+		assert.True(t, ccfgKeywordLookup(key.Parse("DEFAULT.nodes"), "").IsZero())
+	})
+}
+
+func TestCcfgSectionKeywords(t *testing.T) {
+	l := ccfgSectionKeywords("arbitrator#1", "")
+	required := make([]string, 0)
+	for _, kw := range l {
+		assert.Equal(t, "arbitrator", kw.Section)
+		if kw.Required {
+			required = append(required, kw.Option)
+		}
+	}
+	assert.ElementsMatch(t, []string{"name", "secret"}, required)
+}
+
+func TestCcfgSetUnknownKeyword(t *testing.T) {
+	setTestRoot(t, "0")
+	p, err := path.Parse("cluster")
+	require.NoError(t, err)
+	conf := "[cluster]\nname = c1\nsecret = 0a2b\n"
+	installTestConfig(t, p, conf)
+	o := NewCcfg(p)
+	assert.True(t, o.StrictKeywords())
+	assert.Error(t, o.SetKeywords([]string{"cluster.bogus=1"}), "unknown keywords are errors in the cluster configuration")
+	b, err := ioutil.ReadFile(o.ConfigFile())
+	require.NoError(t, err)
+	assert.Equal(t, conf, string(b), "the configuration is not changed")
+	assert.NoError(t, o.SetKeywords([]string{"cluster.name=c2"}))
+}
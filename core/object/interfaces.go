@@ -2,6 +2,9 @@ package object
 
 import (
 	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/pool"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/rbac"
 	"opensvc.com/opensvc/core/resource"
 	"opensvc.com/opensvc/core/resourceset"
 	"opensvc.com/opensvc/core/schedule"
@@ -18,6 +21,18 @@ type (
 	// SecureKeystorer is implemented by encrypting Keystore object kinds (usr, sec).
 	SecureKeystorer interface {
 		GenCert(OptsGenCert) error
+		CertRenew(OptsCertRenew) error
+		CertInfoData(OptsCertInfo) (CertInfoData, error)
+		GenCSR(OptsGenCSR) ([]byte, error)
+		ImportCert(OptsImportCert) error
+		Export(OptsExport) ([]byte, error)
+		Rekey(OptsRekey) error
+	}
+
+	// Usrer is implemented by the usr object kind.
+	Usrer interface {
+		Grants() (rbac.Grants, error)
+		TokenCreate(OptsTokenCreate) (TokenData, error)
 	}
 
 	// Keystorer is implemented by Keystore object kinds (usr, sec, cfg).
@@ -47,6 +62,25 @@ type (
 		Unprovision(OptsUnprovision) error
 	}
 
+	// DataRefresher is implemented by object kinds whose resources can
+	// install data from cfg and sec objects.
+	DataRefresher interface {
+		RefreshData(OptsRefreshData) error
+	}
+
+	// Resizer is implemented by object kinds supporting resize.
+	Resizer interface {
+		Resize(OptsResize) error
+	}
+
+	// VolSnapshotter is implemented by the vol object kind.
+	VolSnapshotter interface {
+		SnapshotCreate(OptsSnapshotCreate) error
+		Snapshots(OptsSnapshotLs) (pool.SnapshotList, error)
+		SnapshotDelete(OptsSnapshotDelete) error
+		SnapshotRollback(OptsSnapshotRollback) error
+	}
+
 	// Freezer is implemented by object kinds supporting freeze and thaw.
 	Freezer interface {
 		Freeze() error
@@ -63,12 +97,39 @@ type (
 		EditConfig(OptsEditConfig) error
 		Eval(OptsEval) (interface{}, error)
 		Get(OptsGet) (interface{}, error)
+		PrintConfig(OptsPrintConfig) (rawconfig.T, error)
+		PrintConfigMatrix(OptsPrintConfig) (xconfig.Matrix, error)
 		Set(OptsSet) error
 		Unset(OptsUnset) error
 		Delete(OptsDelete) error
 		SetStandardConfigFile()
 	}
 
+	// ConfigValidater is implemented by object kinds supporting validate config.
+	ConfigValidater interface {
+		ValidateConfig(OptsValidateConfig) (xconfig.Alerts, error)
+	}
+
+	// ConfigRevisioner is implemented by object kinds keeping a history
+	// of their configuration revisions.
+	ConfigRevisioner interface {
+		ConfigHistory(OptsConfigHistory) (ConfigRevisions, error)
+		ConfigDiff(OptsConfigDiff) (rawconfig.Changes, error)
+		ConfigRestore(OptsConfigRestore) error
+	}
+
+	// ConfigUpgrader is implemented by object kinds whose configuration
+	// can be rewritten to the current keyword names and value formats.
+	ConfigUpgrader interface {
+		ConfigUpgrade(OptsConfigUpgrade) (rawconfig.Changes, error)
+	}
+
+	// Applier is implemented by object kinds whose configuration can be
+	// converged to a structured document.
+	Applier interface {
+		Apply(OptsApply) (rawconfig.Changes, error)
+	}
+
 	// ResourceLister provides a method to list and filter resources
 	ResourceLister interface {
 		Resources() resource.Drivers
@@ -42,7 +42,10 @@ func (t *Keystore) alter(name string, from string, value string) error {
 	switch {
 	case from != "":
 		u := uri.New(from)
+		config := NewNode().MergedConfig()
 		switch {
+		case isBackendRef(from, config):
+			err = t.fromRef(name, from, config)
 		case u.IsValid():
 			err = t.fromURI(name, u)
 		case file.ExistsAndRegular(from):
@@ -58,7 +61,11 @@ func (t *Keystore) alter(name string, from string, value string) error {
 	if err != nil {
 		return err
 	}
-	return t.config.Commit()
+	if err := t.config.Commit(); err != nil {
+		return err
+	}
+	t.flagDependents(name)
+	return nil
 }
 
 func (t *Keystore) fromValue(name string, value string) error {
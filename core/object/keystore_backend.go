@@ -0,0 +1,72 @@
+package object
+
+import (
+	"fmt"
+
+	"opensvc.com/opensvc/core/keyop"
+	"opensvc.com/opensvc/core/keystorebackend"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/xconfig"
+
+	_ "opensvc.com/opensvc/drivers/keystorevault"
+)
+
+// isBackendRef returns true if s is a reference to a value hosted by a
+// keystore backend configured in the node or cluster configuration.
+func isBackendRef(s string, config *xconfig.T) bool {
+	ref, err := keystorebackend.ParseRef(s)
+	if err != nil {
+		return false
+	}
+	return keystorebackend.Exists(ref.Backend, config)
+}
+
+// fromRef stores the reference to a keystore backend value, unencoded.
+// The reference is resolved at decode time. Only the sec objects can
+// store references, to the paths allowed to their namespace.
+//
+// Note: fromRef does not commit, like addKey.
+func (t *Keystore) fromRef(name string, s string, config *xconfig.T) error {
+	if t.Path.Kind != kind.Sec {
+		return fmt.Errorf("keystore backend references are only allowed in sec objects")
+	}
+	ref, err := keystorebackend.ParseRef(s)
+	if err != nil {
+		return err
+	}
+	if err := keystorebackend.Authorize(ref, t.Path.Namespace, config); err != nil {
+		return err
+	}
+	op := keyop.T{
+		Key:   keyFromName(name),
+		Op:    keyop.Set,
+		Value: keystorebackend.RefPrefix + ref.String(),
+	}
+	if err := t.config.Set(op); err != nil {
+		return err
+	}
+	t.log.Info().Str("key", name).Str("ref", ref.String()).Msg("key set")
+	return nil
+}
+
+// resolveRef returns the value hosted by the keystore backend referenced
+// by s. The reference is authorized again, as the configuration may have
+// been edited since the key was added.
+func (t *Keystore) resolveRef(s string) ([]byte, error) {
+	if t.Path.Kind != kind.Sec {
+		return nil, fmt.Errorf("keystore backend references are only allowed in sec objects")
+	}
+	config := NewNode().MergedConfig()
+	return keystorebackend.Resolve(s, t.Path.Namespace, config, decodeSecretKey)
+}
+
+// decodeSecretKey returns the decoded value of the key k of the sec object
+// p. It is used by the keystore backends to read their credentials.
+func decodeSecretKey(p path.T, k string) ([]byte, error) {
+	o := NewSec(p, WithVolatile(true))
+	if !o.Exists() {
+		return nil, fmt.Errorf("%s does not exist", p)
+	}
+	return o.decode(k)
+}
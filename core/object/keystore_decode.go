@@ -3,6 +3,8 @@ package object
 import (
 	"fmt"
 	"os"
+
+	"opensvc.com/opensvc/core/keystorebackend"
 )
 
 const (
@@ -32,5 +34,8 @@ func (t *Keystore) decode(keyname string) ([]byte, error) {
 	if s, err = t.config.GetStringStrict(k); err != nil {
 		return []byte{}, err
 	}
+	if keystorebackend.IsRef(s) {
+		return t.resolveRef(s)
+	}
 	return t.CustomDecode(s)
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"opensvc.com/opensvc/core/keystorebackend"
 	"opensvc.com/opensvc/util/editor"
 	"opensvc.com/opensvc/util/file"
 )
@@ -17,6 +18,9 @@ func (t Keystore) EditKey(opts OptsEditKey) (err error) {
 		refSum []byte
 		f      *os.File
 	)
+	if s := t.config.Get(keyFromName(opts.Key)); keystorebackend.IsRef(s) {
+		return fmt.Errorf("key %s is a reference to %s: use the change action", opts.Key, s[len(keystorebackend.RefPrefix):])
+	}
 	if f, err = t.temporaryKeyFile(opts.Key); err != nil {
 		return
 	}
@@ -38,7 +42,10 @@ func (t Keystore) EditKey(opts OptsEditKey) (err error) {
 		if err = t.addKey(opts.Key, b); err != nil {
 			return
 		}
-		return t.Config().Commit()
+		if err = t.Config().Commit(); err != nil {
+			return
+		}
+		t.flagDependents(opts.Key)
 	}
 	return nil
 }
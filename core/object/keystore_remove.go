@@ -14,5 +14,9 @@ type OptsRemove struct {
 // Remove gets a keyword value
 func (t *Keystore) Remove(options OptsRemove) error {
 	k := key.New(DataSectionName, options.Key)
-	return t.unset(k)
+	if err := t.unset(k); err != nil {
+		return err
+	}
+	t.flagDependents(options.Key)
+	return nil
 }
@@ -65,7 +65,7 @@ func (t Node) ID() uuid.UUID {
 		Value: t.id.String(),
 	}
 	_ = t.config.Set(op)
-	if err := t.config.Commit(); err != nil {
+	if err := t.config.CommitInvalid(); err != nil {
 		t.log.Error().Err(err).Msg("")
 	}
 	return t.id
@@ -0,0 +1,88 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+type (
+	// OptsNodeConfigUpgrade is the options of the ConfigUpgrade node method.
+	OptsNodeConfigUpgrade struct {
+		Global OptsGlobal
+		Lock   OptsLocking
+	}
+
+	// ConfigUpgradeResult is the changes made to a configuration file by
+	// a node config upgrade. Path is "node" for the node configuration.
+	ConfigUpgradeResult struct {
+		Path    string            `json:"path"`
+		Changes rawconfig.Changes `json:"changes"`
+		Error   string            `json:"error,omitempty"`
+	}
+
+	// ConfigUpgradeResults is the list of results of a node config upgrade.
+	ConfigUpgradeResults []ConfigUpgradeResult
+)
+
+// ConfigUpgrade rewrites the node configuration file and the configuration
+// files of all the objects installed on the node to the current keyword
+// names and value formats. The upgrade of an object failing does not stop
+// the upgrade of the others. With the dry-run option, the changes are
+// returned but not applied.
+func (t *Node) ConfigUpgrade(options OptsNodeConfigUpgrade) (ConfigUpgradeResults, error) {
+	l := make(ConfigUpgradeResults, 0)
+	changes := t.config.UpgradeChanges()
+	if !options.Global.DryRun && len(changes) > 0 {
+		if err := t.config.ApplyChanges(changes); err != nil {
+			return l, err
+		}
+		if err := t.config.CommitInvalid(); err != nil {
+			return l, err
+		}
+		t.log.Info().Int("changes", len(changes)).Msg("configuration upgraded")
+	}
+	l = append(l, ConfigUpgradeResult{Path: "node", Changes: changes})
+	paths, err := Installed()
+	if err != nil {
+		return l, err
+	}
+	failed := 0
+	for _, p := range paths {
+		result := ConfigUpgradeResult{Path: p.String()}
+		o, ok := NewFromPath(p).(ConfigUpgrader)
+		if !ok {
+			continue
+		}
+		result.Changes, err = o.ConfigUpgrade(OptsConfigUpgrade{Global: options.Global, Lock: options.Lock})
+		if err != nil {
+			t.log.Error().Err(err).Stringer("path", p).Msg("config upgrade")
+			result.Error = err.Error()
+			failed++
+		}
+		l = append(l, result)
+	}
+	if failed > 0 {
+		return l, fmt.Errorf("%d object configurations failed to upgrade", failed)
+	}
+	return l, nil
+}
+
+// Render returns the changes grouped by configuration file. The files
+// not changed are omitted.
+func (t ConfigUpgradeResults) Render() string {
+	l := make([]string, 0)
+	for _, r := range t {
+		if len(r.Changes) == 0 && r.Error == "" {
+			continue
+		}
+		s := rawconfig.Node.Colorize.Bold(r.Path) + "\n"
+		if r.Error != "" {
+			s += rawconfig.Node.Colorize.Error(r.Error) + "\n"
+		}
+		s += r.Changes.Render()
+		l = append(l, s)
+	}
+	return strings.Join(l, "\n")
+}
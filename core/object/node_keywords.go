@@ -336,10 +336,24 @@ var commonKeywords = []keywords.Keyword{
 	{
 		Section:    "node",
 		Option:     "env",
+		Aliases:    []string{"host_mode"},
 		Default:    "TST",
 		Candidates: envs.List,
 		Text:       "A non-PRD service can not be brought up on a PRD node, but a PRD service can be startup on a non-PRD node (in a DRP situation).",
 	},
+	{
+		Section:   "node",
+		Option:    "config_history_max",
+		Default:   "10",
+		Converter: converters.Int,
+		Text:      "The maximum number of object configuration revisions kept by the node. Each configuration commit saves a revision, the oldest revisions are pruned. Set to ``0`` to disable the configuration history.",
+	},
+	{
+		Section: "node",
+		Option:  "template_namespace",
+		Text:    "The namespace of the template objects shared by all namespaces. An object can extend a template of its own namespace or of this namespace. The sec and cfg references in the inherited keys are resolved in the namespace of the extending object.",
+		Example: "templates",
+	},
 	{
 		Section:   "node",
 		Option:    "max_parallel",
@@ -734,6 +748,17 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		DefaultText: "<random autogenerated on first use>",
 		Text:        "The cluster shared secret. Used to encrypt/decrypt data with AES256. This secret is either autogenerated or fetched from a join command.",
 	},
+	{
+		Section: "cluster",
+		Option:  "previous_secret",
+		Text:    "The cluster shared secret replaced by the last ``node rotate-secret``. Data encrypted with this secret is still accepted until :kw:`previous_secret_until`, so the nodes and the sec objects can be migrated to the new secret.",
+	},
+	{
+		Section: "cluster",
+		Option:  "previous_secret_until",
+		Example: "2021-06-01T00:00:00Z",
+		Text:    "The end of the :kw:`previous_secret` grace period, in RFC3339 format. Set by ``node rotate-secret``.",
+	},
 	{
 		Section:   "cluster",
 		Option:    "nodes",
@@ -911,6 +936,84 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		Text:    "The directory hosting the CNI network configuration files.",
 		Example: "/var/lib/opensvc/cni/net.d",
 	},
+	{
+		Section:    "keystore",
+		Option:     "type",
+		Default:    "vault",
+		Candidates: []string{"vault"},
+		Text:       "The keystore backend type. A sec key added with ``--from <name>://<mount>/<path>#<field>`` stores a reference resolved at decode time by the :c-keystore:`keystore#<name>` backend, if allowed by :kw:`allow`.",
+	},
+	{
+		Section:  "keystore",
+		Types:    []string{"vault"},
+		Option:   "url",
+		Required: true,
+		Example:  "https://vault.example.com:8200",
+		Text:     "The url of the HashiCorp Vault compatible api.",
+	},
+	{
+		Section: "keystore",
+		Types:   []string{"vault"},
+		Option:  "namespace",
+		Text:    "The Vault namespace, sent in the X-Vault-Namespace request header.",
+	},
+	{
+		Section:    "keystore",
+		Types:      []string{"vault"},
+		Option:     "auth",
+		Default:    "token",
+		Candidates: []string{"token", "approle"},
+		Text:       "The authentication method. ``token`` uses the token stored in the :kw:`token_key` key of the :kw:`sec` object. ``approle`` logs in with the role id and secret id stored in the :kw:`role_id_key` and :kw:`secret_id_key` keys of the :kw:`sec` object.",
+	},
+	{
+		Section: "keystore",
+		Types:   []string{"vault"},
+		Option:  "sec",
+		Example: "system/sec/vault",
+		Text:    "The path of the sec object hosting the Vault credentials.",
+	},
+	{
+		Section: "keystore",
+		Types:   []string{"vault"},
+		Option:  "token_key",
+		Default: "token",
+		Text:    "The :kw:`sec` object key hosting the Vault token.",
+	},
+	{
+		Section: "keystore",
+		Types:   []string{"vault"},
+		Option:  "role_id_key",
+		Default: "role_id",
+		Text:    "The :kw:`sec` object key hosting the AppRole role id.",
+	},
+	{
+		Section: "keystore",
+		Types:   []string{"vault"},
+		Option:  "secret_id_key",
+		Default: "secret_id",
+		Text:    "The :kw:`sec` object key hosting the AppRole secret id.",
+	},
+	{
+		Section: "keystore",
+		Types:   []string{"vault"},
+		Option:  "approle_mount",
+		Default: "approle",
+		Text:    "The mount path of the AppRole auth method.",
+	},
+	{
+		Section:   "keystore",
+		Option:    "allow",
+		Converter: converters.List,
+		Example:   "ns1:secret/app1 ns2:secret/app2/db",
+		Text:      "The whitespace separated list of ``<namespace>:<mount>[/<path prefix>]`` the sec objects of the namespace are allowed to reference. A backend with no allow entry for a namespace can not be used by its sec objects. The cfg objects can not reference a backend.",
+	},
+	{
+		Section:   "keystore",
+		Option:    "cache_ttl",
+		Default:   "5m",
+		Converter: converters.Duration,
+		Text:      "The duration the resolved values are cached in memory.",
+	},
 	{
 		Section:    "pool",
 		Option:     "type",
@@ -923,6 +1026,14 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		Option:  "status_schedule",
 		Text:    "The value to set to the status_schedule keyword of the volume objects allocated from the pool. See usr/share/doc/schedule for the schedule syntax.",
 	},
+	{
+		Section:   "pool",
+		Option:    "quotas",
+		Types:     []string{"directory", "loop", "vg", "zpool", "freenas", "share", "shm", "symmetrix", "virtual", "dorado", "hcs", "drbd"},
+		Converter: converters.List,
+		Text:      "The whitespace separated list of ``<namespace>=<size>`` maximum total size of the volumes the pool can allocate for the namespace. The ``*`` namespace matches the namespaces not explicitly listed. The quotas are enforced on volume creation.",
+		Example:   "test=100g *=1t",
+	},
 	{
 		Section:  "pool",
 		Option:   "mnt_opt",
@@ -1035,6 +1146,29 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		Required: true,
 		Text:     "The name of the volume group to allocate the pool volumes logical volumes into.",
 	},
+	{
+		Section: "pool",
+		Types:   []string{"vg"},
+		Option:  "thin_pool",
+		Text:    "The name of the thin pool logical volume of the volume group to allocate the pool volumes thin logical volumes into. If not set, the logical volumes are fully allocated in the volume group.",
+		Example: "tp1",
+	},
+	{
+		Section:   "pool",
+		Types:     []string{"vg"},
+		Option:    "create_options",
+		Converter: converters.Shlex,
+		Example:   "--contiguous y",
+		Text:      "Additional options to pass to the logical volume create command.",
+	},
+	{
+		Section: "pool",
+		Types:   []string{"vg"},
+		Option:  "snap_size",
+		Default: "10%ORIGIN",
+		Example: "1g",
+		Text:    "The size of the snapshot logical volumes of the thick volumes allocated from this pool. Either a size, or a percentage of the origin logical volume size suffixed by ``%ORIGIN``. Not used by thin volumes snapshots.",
+	},
 	{
 		Section: "pool",
 		Types:   []string{"drbd"},
@@ -1113,6 +1247,14 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		Default: "{var}/pool/loop",
 		Text:    "The path to create the pool loop files in.",
 	},
+	{
+		Section:   "pool",
+		Option:    "sparse",
+		Types:     []string{"loop"},
+		Converter: converters.Bool,
+		Default:   "true",
+		Text:      "Create the pool loop files as sparse files. The space not yet allocated by sparse loop files is accounted as used in the pool usage. If ``false``, the loop files blocks are allocated on provision.",
+	},
 	{
 		Section: "pool",
 		Option:  "fs_type",
@@ -0,0 +1,88 @@
+package object
+
+import (
+	"strings"
+
+	"opensvc.com/opensvc/core/network"
+	"opensvc.com/opensvc/drivers/netbridge"
+	_ "opensvc.com/opensvc/drivers/netroutedbridge"
+)
+
+func (t *Node) ShowNetworksByName(name string) network.StatusList {
+	l := network.NewStatusList()
+	for _, n := range t.Networks() {
+		if name != "" && name != n.Name() {
+			continue
+		}
+		l = l.Add(n)
+	}
+	return l
+}
+
+func (t *Node) ShowNetworks() network.StatusList {
+	return t.ShowNetworksByName("")
+}
+
+// Networks returns the list of configured network drivers, plus the
+// implicit "default" bridge network if not explicitly configured.
+func (t *Node) Networks() []network.Networker {
+	l := make([]network.Networker, 0)
+	config := t.MergedConfig()
+	hasDefault := false
+
+	for _, name := range t.ListNetworks() {
+		n := network.New(name, config)
+		if n == nil {
+			t.log.Debug().Msgf("network %s: unsupported type", name)
+			continue
+		}
+		if n.Name() == "default" {
+			hasDefault = true
+		}
+		l = append(l, n)
+	}
+	if !hasDefault {
+		n := netbridge.NewNetworker()
+		n.SetName("default")
+		n.SetDriver("bridge")
+		n.SetConfig(config)
+		l = append(l, n)
+	}
+	return l
+}
+
+func (t *Node) ListNetworks() []string {
+	l := make([]string, 0)
+	for _, s := range t.MergedConfig().SectionStrings() {
+		if !strings.HasPrefix(s, "network#") {
+			continue
+		}
+		l = append(l, s[8:])
+	}
+	return l
+}
+
+// Network returns the network driver named <name>, or nil if not found.
+func (t *Node) Network(name string) network.Networker {
+	for _, n := range t.Networks() {
+		if n.Name() == name {
+			return n
+		}
+	}
+	return nil
+}
+
+// SetupNetworks installs the CNI configuration files and node settings
+// of the networks matching <name>, or all networks if <name> is empty.
+func (t *Node) SetupNetworks(name string) error {
+	for _, n := range t.Networks() {
+		if name != "" && name != n.Name() {
+			continue
+		}
+		t.log.Info().Msgf("setup network %s", n.Name())
+		if err := network.Setup(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -3,9 +3,17 @@ package object
 import (
 	"strings"
 
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/drivergroup"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/path"
 	"opensvc.com/opensvc/core/pool"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/resourceid"
+	"opensvc.com/opensvc/core/xconfig"
 	"opensvc.com/opensvc/drivers/pooldirectory"
 	"opensvc.com/opensvc/drivers/poolshm"
+	"opensvc.com/opensvc/util/key"
 )
 
 func (t *Node) ShowPoolsByName(name string) pool.StatusList {
@@ -73,3 +81,111 @@ func (t *Node) ListPools() []string {
 	}
 	return l
 }
+
+// Pool returns the node pool named <name>, or nil if not found.
+func (t *Node) Pool(name string) pool.Pooler {
+	for _, p := range t.Pools() {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// PoolVolumes returns the status of the installed volumes. The children
+// of a volume are the installed objects having a volume resource
+// referencing it. A volume without children is an orphan.
+//
+// An error is returned if an installed object can not be fully
+// inspected, as its volume references would be missed and the
+// volumes it uses reported as orphans.
+func (t *Node) PoolVolumes() (pool.VolumeStatusList, error) {
+	l := make(pool.VolumeStatusList, 0)
+	paths, err := Installed()
+	if err != nil {
+		return l, err
+	}
+	refs := make(map[path.T][]path.T)
+	vols := make([]*Vol, 0)
+	for _, p := range paths {
+		if p.Kind == kind.Vol {
+			v := NewVol(p, WithVolatile(true))
+			if v.config == nil {
+				return l, errors.Errorf("%s: configuration can not be loaded", p)
+			}
+			vols = append(vols, v)
+			continue
+		}
+		vps, err := volumeRefs(p)
+		if err != nil {
+			return l, errors.Wrapf(err, "%s", p)
+		}
+		for _, vp := range vps {
+			refs[vp] = append(refs[vp], p)
+		}
+	}
+	for _, v := range vols {
+		data := pool.VolumeStatus{
+			Path:     v.Path,
+			Pool:     v.config.GetString(key.Parse("pool")),
+			Children: refs[v.Path],
+		}
+		if data.Children == nil {
+			data.Children = make([]path.T, 0)
+		}
+		if size, err := v.config.GetSizeStrict(key.Parse("size")); err == nil && size != nil {
+			data.Size = float64(*size)
+		}
+		data.Orphan = len(data.Children) == 0
+		l = append(l, data)
+	}
+	return l, nil
+}
+
+// volumeRefs returns the paths of the volumes referenced by the volume
+// resources of the object, or an error if a volume section of the
+// object configuration can not be resolved to a volume path.
+func volumeRefs(p path.T) ([]path.T, error) {
+	type volNamer interface {
+		VolName() string
+	}
+	type configResourceLister interface {
+		Config() *xconfig.T
+		Resources() resource.Drivers
+	}
+	l := make([]path.T, 0)
+	o, ok := NewFromPath(p, WithVolatile(true)).(configResourceLister)
+	switch {
+	case !ok:
+		return l, errors.Errorf("unsupported kind")
+	case o.Config() == nil:
+		return l, errors.Errorf("configuration can not be loaded")
+	}
+	sections := 0
+	for _, s := range o.Config().SectionStrings() {
+		if resourceid.Parse(s).DriverGroup() == drivergroup.Volume {
+			sections++
+		}
+	}
+	if sections == 0 {
+		return l, nil
+	}
+	for _, r := range o.Resources() {
+		if r.ID().DriverGroup() != drivergroup.Volume {
+			continue
+		}
+		i, ok := r.(volNamer)
+		if !ok {
+			continue
+		}
+		vp, err := path.New(i.VolName(), p.Namespace, kind.Vol.String())
+		if err != nil {
+			return l, errors.Wrapf(err, "%s", r.RID())
+		}
+		l = append(l, vp)
+	}
+	if len(l) != sections {
+		return l, errors.Errorf("%d of %d volume resources can be configured", len(l), sections)
+	}
+	return l, nil
+}
@@ -0,0 +1,172 @@
+package object
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/drivergroup"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/pool"
+	"opensvc.com/opensvc/core/resourceid"
+	"opensvc.com/opensvc/core/status"
+)
+
+// OptsNodePoolGC is the options of the PoolGC function.
+type OptsNodePoolGC struct {
+	Global OptsGlobal
+}
+
+// PoolGC unprovisions and deletes the orphan volumes, ie the volumes
+// installed on this node and not referenced by the volume resource of
+// any object instance of the cluster. The references are read from the
+// cluster status served by the daemon, and nothing is deleted if the
+// cluster status can not be fetched, lacks the dataset of a cluster
+// node, or has a volume resource without volume name.
+//
+// Nothing is deleted either if an installed object can not be fully
+// inspected. A volume is kept if its local instance is not down or if
+// it has local holders. With the DryRun global option set, the volumes
+// to delete are only reported.
+//
+// The returned list contains the deleted volumes.
+func (t *Node) PoolGC(options OptsNodePoolGC) (pool.VolumeStatusList, error) {
+	l := make(pool.VolumeStatusList, 0)
+	refs, err := clusterVolumeRefs()
+	if err != nil {
+		return l, errors.Wrapf(err, "refuse to delete volumes without the cluster view")
+	}
+	volumes, err := t.PoolVolumes()
+	if err != nil {
+		return l, err
+	}
+	for _, data := range volumes.Orphans() {
+		if children := refs[data.Path]; len(children) > 0 {
+			t.log.Info().Msgf("skip orphan volume %s: referenced by %s", data.Path, children)
+			continue
+		}
+		v := NewVol(data.Path)
+		if err := poolGCSafe(v); err != nil {
+			t.log.Info().Msgf("skip orphan volume %s: %s", v.Path, err)
+			continue
+		}
+		if options.Global.DryRun {
+			t.log.Info().Msgf("orphan volume %s would be deleted", v.Path)
+			l = append(l, data)
+			continue
+		}
+		t.log.Info().Msgf("delete orphan volume %s", v.Path)
+		unprovisionOptions := OptsUnprovision{}
+		unprovisionOptions.Leader = true
+		if err := v.Unprovision(unprovisionOptions); err != nil {
+			return l, errors.Wrapf(err, "unprovision orphan volume %s", v.Path)
+		}
+		if err := v.Delete(OptsDelete{}); err != nil {
+			return l, errors.Wrapf(err, "delete orphan volume %s", v.Path)
+		}
+		l = append(l, data)
+	}
+	return l, nil
+}
+
+// poolGCSafe returns an error if the volume local instance is not
+// stopped or has local holders.
+func poolGCSafe(v *Vol) error {
+	data, err := v.Status(OptsStatus{})
+	if err != nil {
+		return err
+	}
+	switch data.Avail {
+	case status.Down, status.NotApplicable:
+	default:
+		return fmt.Errorf("avail status is %s", data.Avail)
+	}
+	if holders := v.HoldersExcept(context.Background(), path.T{}); holders.Len() > 0 {
+		return fmt.Errorf("active holders: %s", holders)
+	}
+	return nil
+}
+
+// clusterVolumeRefs returns the paths of the objects having a volume
+// resource referencing a volume, indexed by volume path, read from the
+// cluster status served by the daemon.
+func clusterVolumeRefs() (map[path.T][]path.T, error) {
+	c, err := client.New()
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.NewGetDaemonStatus().Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "get cluster status")
+	}
+	return volumeRefsFromClusterStatus(b)
+}
+
+// volumeRefsFromClusterStatus returns the volume references found in
+// the instance status of the cluster status b, or an error if a cluster
+// node dataset is missing or a volume resource does not expose its
+// volume name.
+//
+// The cluster package imports this package, so the status is decoded
+// into a local subset of its cluster.Status type.
+func volumeRefsFromClusterStatus(b []byte) (map[path.T][]path.T, error) {
+	var data struct {
+		Cluster struct {
+			Nodes []string `json:"nodes"`
+		} `json:"cluster"`
+		Monitor struct {
+			Nodes map[string]struct {
+				Services struct {
+					Status map[string]instance.Status `json:"status"`
+				} `json:"services"`
+			} `json:"nodes"`
+		} `json:"monitor"`
+	}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, errors.Wrapf(err, "decode cluster status")
+	}
+	if len(data.Cluster.Nodes) == 0 {
+		return nil, errors.Errorf("the cluster status has no nodes")
+	}
+	refs := make(map[path.T][]path.T)
+	for _, nodename := range data.Cluster.Nodes {
+		nodeData, ok := data.Monitor.Nodes[nodename]
+		if !ok {
+			return nil, errors.Errorf("the cluster status has no dataset for node %s", nodename)
+		}
+		for ps, instanceStatus := range nodeData.Services.Status {
+			p, err := path.Parse(ps)
+			if err != nil {
+				return nil, errors.Wrapf(err, "node %s", nodename)
+			}
+			for rid, resourceStatus := range instanceStatus.Resources {
+				if resourceid.Parse(rid).DriverGroup() != drivergroup.Volume {
+					continue
+				}
+				name, ok := resourceStatus.Info["name"].(string)
+				if !ok || name == "" {
+					return nil, errors.Errorf("%s@%s %s: volume name not exposed", p, nodename, rid)
+				}
+				vp, err := path.New(name, p.Namespace, kind.Vol.String())
+				if err != nil {
+					return nil, errors.Wrapf(err, "%s@%s %s", p, nodename, rid)
+				}
+				refs[vp] = appendPathOnce(refs[vp], p)
+			}
+		}
+	}
+	return refs, nil
+}
+
+func appendPathOnce(l []path.T, p path.T) []path.T {
+	for _, e := range l {
+		if e == p {
+			return l
+		}
+	}
+	return append(l, p)
+}
@@ -0,0 +1,63 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+)
+
+func TestVolumeRefsFromClusterStatus(t *testing.T) {
+	mustParse := func(s string) path.T {
+		p, err := path.Parse(s)
+		require.NoError(t, err)
+		return p
+	}
+	t.Run("references of all nodes", func(t *testing.T) {
+		b := []byte(`{
+			"cluster": {"nodes": ["n1", "n2"]},
+			"monitor": {"nodes": {
+				"n1": {"services": {"status": {
+					"ns1/svc/s1": {"resources": {
+						"volume#1": {"type": "volume", "info": {"name": "v1"}},
+						"fs#1": {"type": "fs.flag"}
+					}}
+				}}},
+				"n2": {"services": {"status": {
+					"ns1/svc/s1": {"resources": {"volume#1": {"type": "volume", "info": {"name": "v1"}}}},
+					"ns1/svc/s2": {"resources": {"volume#data": {"type": "volume", "info": {"name": "v2"}}}},
+					"s3": {"resources": {"volume#1": {"type": "volume", "info": {"name": "v1"}}}}
+				}}}
+			}}
+		}`)
+		refs, err := volumeRefsFromClusterStatus(b)
+		require.NoError(t, err)
+		assert.Equal(t, map[path.T][]path.T{
+			mustParse("ns1/vol/v1"): {mustParse("ns1/svc/s1")},
+			mustParse("ns1/vol/v2"): {mustParse("ns1/svc/s2")},
+			mustParse("vol/v1"):     {mustParse("s3")},
+		}, refs)
+	})
+	t.Run("missing node dataset", func(t *testing.T) {
+		b := []byte(`{"cluster": {"nodes": ["n1", "n2"]}, "monitor": {"nodes": {"n1": {}}}}`)
+		_, err := volumeRefsFromClusterStatus(b)
+		assert.Error(t, err)
+	})
+	t.Run("volume name not exposed", func(t *testing.T) {
+		b := []byte(`{
+			"cluster": {"nodes": ["n1"]},
+			"monitor": {"nodes": {"n1": {"services": {"status": {
+				"ns1/svc/s1": {"resources": {"volume#1": {"type": "volume", "label": "volume v1"}}}
+			}}}}}
+		}`)
+		_, err := volumeRefsFromClusterStatus(b)
+		assert.Error(t, err)
+	})
+	t.Run("no cluster view", func(t *testing.T) {
+		for _, s := range []string{"", "{}", `{"cluster": {"nodes": []}}`} {
+			_, err := volumeRefsFromClusterStatus([]byte(s))
+			assert.Error(t, err, s)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package object
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"opensvc.com/opensvc/core/keyop"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+// OptsNodeRotateSecret is the options of the RotateSecret function.
+type OptsNodeRotateSecret struct {
+	Global OptsGlobal
+	Secret string        `flag:"secret"`
+	Grace  time.Duration `flag:"grace"`
+}
+
+var (
+	keyClusterSecret              = key.New("cluster", "secret")
+	keyClusterPreviousSecret      = key.New("cluster", "previous_secret")
+	keyClusterPreviousSecretUntil = key.New("cluster", "previous_secret_until")
+)
+
+// RotateSecret replaces the cluster secret, and keeps accepting the
+// replaced secret to decrypt data during the grace period, so the other
+// cluster nodes and the sec objects can be migrated.
+//
+// The new secret is generated if not specified in the options.
+func (t *Node) RotateSecret(options OptsNodeRotateSecret) error {
+	current := rawconfig.Node.Cluster.Secret
+	if current == "" {
+		return fmt.Errorf("no cluster secret to rotate")
+	}
+	secret := options.Secret
+	if secret == "" {
+		var err error
+		if secret, err = NewClusterSecret(); err != nil {
+			return err
+		}
+	}
+	if secret == current {
+		return fmt.Errorf("the new cluster secret is the current cluster secret")
+	}
+	until := time.Now().Add(options.Grace).UTC().Format(time.RFC3339)
+
+	// node.conf values have precedence over cluster.conf values, so drop
+	// the cluster secrets possibly stored there.
+	if t.config.Unset(keyClusterSecret, keyClusterPreviousSecret, keyClusterPreviousSecretUntil) > 0 {
+		if err := t.config.Commit(); err != nil {
+			return err
+		}
+	}
+	cfg, err := xconfig.NewObject(t.ClusterConfigFile())
+	if err != nil {
+		return err
+	}
+	cfg.Referrer = t
+	for _, op := range []keyop.T{
+		{Key: keyClusterPreviousSecret, Op: keyop.Set, Value: current},
+		{Key: keyClusterPreviousSecretUntil, Op: keyop.Set, Value: until},
+		{Key: keyClusterSecret, Op: keyop.Set, Value: secret},
+	} {
+		if err := cfg.Set(op); err != nil {
+			return err
+		}
+	}
+	if err := cfg.Commit(); err != nil {
+		return err
+	}
+	rawconfig.Node.Cluster.PreviousSecret = current
+	rawconfig.Node.Cluster.PreviousSecretUntil = until
+	rawconfig.Node.Cluster.Secret = secret
+	t.log.Info().Str("previous_secret_until", until).Msg("cluster secret rotated")
+	return nil
+}
+
+// NewClusterSecret returns a random cluster secret.
+func NewClusterSecret() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
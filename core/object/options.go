@@ -71,14 +71,15 @@ type (
 		resourceselector.Options
 		OptTo
 		OptForce
-		Template    string   `flag:"template"`
-		Config      string   `flag:"config"`
-		Keywords    []string `flag:"kwops"`
-		Env         string   `flag:"env"`
-		Interactive bool     `flag:"interactive"`
-		Provision   bool     `flag:"provision"`
-		Restore     bool     `flag:"restore"`
-		Namespace   string   `flag:"createnamespace"`
+		Template     string   `flag:"template"`
+		Config       string   `flag:"config"`
+		Keywords     []string `flag:"kwops"`
+		Env          string   `flag:"env"`
+		Interactive  bool     `flag:"interactive"`
+		Provision    bool     `flag:"provision"`
+		Restore      bool     `flag:"restore"`
+		Namespace    string   `flag:"createnamespace"`
+		FromSnapshot string   `flag:"fromsnapshot"`
 	}
 )
 
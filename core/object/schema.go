@@ -0,0 +1,314 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/drivergroup"
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/stringslice"
+)
+
+type (
+	// Schema describes the keywords accepted in the sections of the
+	// configuration file of a kind of object, of the node or of the
+	// cluster.
+	Schema struct {
+		Kind     string          `json:"kind"`
+		Sections []SchemaSection `json:"sections"`
+	}
+
+	// SchemaSection describes the keywords accepted in a section.
+	//
+	// Pattern is the regular expression the section names must match,
+	// or empty if the section name is Name. Free sections accept any
+	// option, like the env and data sections.
+	//
+	// The sections of typed driver groups have one Types entry per
+	// driver, with the keywords accepted by this driver. DefaultType is
+	// the driver used when the section type keyword is not set.
+	SchemaSection struct {
+		Name        string          `json:"name"`
+		Pattern     string          `json:"pattern,omitempty"`
+		Free        bool            `json:"free,omitempty"`
+		DefaultType string          `json:"default_type,omitempty"`
+		Keywords    []SchemaKeyword `json:"keywords,omitempty"`
+		Types       []SchemaType    `json:"types,omitempty"`
+	}
+
+	// SchemaType describes the keywords accepted by a section type.
+	SchemaType struct {
+		Name     string          `json:"name"`
+		Keywords []SchemaKeyword `json:"keywords"`
+	}
+
+	// SchemaKeyword is the documentation of a keyword.
+	SchemaKeyword struct {
+		Option       string   `json:"option"`
+		Scopable     bool     `json:"scopable"`
+		Required     bool     `json:"required"`
+		Provisioning bool     `json:"provisioning"`
+		Converter    string   `json:"converter,omitempty"`
+		Default      string   `json:"default,omitempty"`
+		DefaultText  string   `json:"default_text,omitempty"`
+		Candidates   []string `json:"candidates,omitempty"`
+		Example      string   `json:"example,omitempty"`
+		Aliases      []string `json:"aliases,omitempty"`
+		Deprecated   string   `json:"deprecated,omitempty"`
+		ReplacedBy   string   `json:"replaced_by,omitempty"`
+		Text         string   `json:"text"`
+	}
+
+	// SchemaKeywordDoc is a keyword found by Schema.Lookup, with the
+	// section and the section types sharing this keyword definition.
+	SchemaKeywordDoc struct {
+		Section string   `json:"section"`
+		Types   []string `json:"types,omitempty"`
+		SchemaKeyword
+	}
+
+	// SchemaKeywordDocs is the list of keywords found by Schema.Lookup.
+	SchemaKeywordDocs []SchemaKeywordDoc
+)
+
+var (
+	// SchemaKinds is the list of configuration kinds a schema can be
+	// generated for.
+	SchemaKinds = []string{"svc", "vol", "cfg", "sec", "usr", "node", "cluster"}
+
+	// ErrSchemaKind is returned when a schema is asked for an unsupported
+	// configuration kind.
+	ErrSchemaKind = errors.New("unsupported schema kind")
+)
+
+// NewSchema returns the schema of the configuration files of the kind,
+// one of SchemaKinds.
+func NewSchema(s string) (Schema, error) {
+	switch s {
+	case "node":
+		return newKeywordsSchema(s, nodeKeywordStore), nil
+	case "cluster":
+		return newKeywordsSchema(s, ccfgKeywordStore), nil
+	}
+	if !stringslice.Has(s, SchemaKinds) {
+		return Schema{}, errors.Wrapf(ErrSchemaKind, "%s", s)
+	}
+	return newObjectSchema(kind.New(s)), nil
+}
+
+func newSchemaKeyword(kw keywords.Keyword) SchemaKeyword {
+	t := SchemaKeyword{
+		Option:       kw.Option,
+		Scopable:     kw.Scopable,
+		Required:     kw.Required,
+		Provisioning: kw.Provisioning,
+		Default:      kw.Default,
+		DefaultText:  kw.DefaultText,
+		Candidates:   kw.Candidates,
+		Example:      kw.Example,
+		Aliases:      kw.Aliases,
+		Deprecated:   kw.Deprecated,
+		ReplacedBy:   kw.ReplacedBy,
+		Text:         kw.Text,
+	}
+	if i, ok := kw.Converter.(fmt.Stringer); ok {
+		t.Converter = i.String()
+	}
+	return t
+}
+
+// schemaKeywords returns the documentation of the keywords applicable to
+// the object kind, the first declaration of an option winning.
+func schemaKeywords(kd kind.T, kws ...keywords.Keyword) []SchemaKeyword {
+	l := make([]SchemaKeyword, 0)
+	seen := make(map[string]interface{})
+	for _, kw := range kws {
+		if !kw.Kind.Has(kd) {
+			continue
+		}
+		if _, ok := seen[kw.Option]; ok {
+			continue
+		}
+		seen[kw.Option] = nil
+		l = append(l, newSchemaKeyword(kw))
+	}
+	return l
+}
+
+// newObjectSchema returns the schema of the kind of object. Only the svc
+// and vol objects have resource sections.
+func newObjectSchema(kd kind.T) Schema {
+	var (
+		defaults []keywords.Keyword
+		subsets  []keywords.Keyword
+		generics []keywords.Keyword
+	)
+	for _, kw := range keywordStore {
+		switch kw.Section {
+		case "DEFAULT":
+			defaults = append(defaults, kw)
+		case "subset":
+			subsets = append(subsets, kw)
+		case "":
+			generics = append(generics, kw)
+		}
+	}
+	t := Schema{Kind: kd.String()}
+	t.Sections = append(t.Sections,
+		SchemaSection{Name: "DEFAULT", Keywords: schemaKeywords(kd, append(defaults, generics...)...)},
+		SchemaSection{Name: "env", Free: true},
+		SchemaSection{Name: "data", Free: true},
+		SchemaSection{Name: "metadata", Free: true},
+	)
+	switch kd {
+	case kind.Svc, kind.Vol:
+	default:
+		return t
+	}
+	t.Sections = append(t.Sections, SchemaSection{
+		Name:     "subset",
+		Pattern:  "^subset#.+$",
+		Keywords: schemaKeywords(kd, subsets...),
+	})
+	groups := drivergroup.Names()
+	sort.Strings(groups)
+	for _, group := range groups {
+		if section, ok := newResourceSchemaSection(kd, group, generics); ok {
+			t.Sections = append(t.Sections, section)
+		}
+	}
+	return t
+}
+
+// newResourceSchemaSection returns the schema of the sections of the
+// driver group, and false if no driver is registered in the group.
+func newResourceSchemaSection(kd kind.T, group string, generics []keywords.Keyword) (SchemaSection, bool) {
+	t := SchemaSection{
+		Name:    group,
+		Pattern: "^" + group + "#.+$",
+	}
+	factories := make(map[string]func() resource.Driver)
+	names := make([]string, 0)
+	for drvID, factory := range resource.RegisteredGroupDrivers(group) {
+		factories[drvID.Name] = factory
+		names = append(names, drvID.Name)
+	}
+	if len(names) == 0 {
+		return t, false
+	}
+	sort.Strings(names)
+	typeKeyword := sectionTypeKeyword(group)
+	for _, name := range names {
+		m := factories[name]().Manifest()
+		kws := append(append([]keywords.Keyword{typeKeyword}, generics...), m.Keywords...)
+		if name == "" {
+			// generic driver, the type keyword does not select
+			// the resource driver.
+			t.Keywords = schemaKeywords(kd, kws...)
+			return t, true
+		}
+		t.Types = append(t.Types, SchemaType{
+			Name:     name,
+			Keywords: schemaKeywords(kd, kws...),
+		})
+	}
+	if name, ok := DefaultDriver[group]; ok && stringslice.Has(name, names) {
+		t.DefaultType = name
+	}
+	return t, true
+}
+
+// newKeywordsSchema returns the schema of a node or cluster configuration,
+// whose sections are named <section> or <section>#<name>. A section is
+// typed if some of its keywords apply to specific types.
+func newKeywordsSchema(s string, store []keywords.Keyword) Schema {
+	t := Schema{Kind: s}
+	names := make([]string, 0)
+	bySection := make(map[string][]keywords.Keyword)
+	for _, kw := range store {
+		if _, ok := bySection[kw.Section]; !ok {
+			names = append(names, kw.Section)
+		}
+		bySection[kw.Section] = append(bySection[kw.Section], kw)
+	}
+	for _, name := range []string{"env", "data", "labels"} {
+		t.Sections = append(t.Sections, SchemaSection{Name: name, Free: true})
+	}
+	for _, name := range names {
+		kws := bySection[name]
+		section := SchemaSection{
+			Name:    name,
+			Pattern: "^" + name + "(#.+)?$",
+		}
+		types := make([]string, 0)
+		for _, kw := range kws {
+			for _, typ := range kw.Types {
+				if !stringslice.Has(typ, types) {
+					types = append(types, typ)
+				}
+			}
+		}
+		sort.Strings(types)
+		if len(types) == 0 {
+			section.Keywords = schemaKeywords(kind.Invalid, kws...)
+		}
+		for _, typ := range types {
+			l := make([]keywords.Keyword, 0)
+			for _, kw := range kws {
+				if len(kw.Types) == 0 || stringslice.Has(typ, kw.Types) {
+					l = append(l, kw)
+				}
+			}
+			section.Types = append(section.Types, SchemaType{
+				Name:     typ,
+				Keywords: schemaKeywords(kind.Invalid, l...),
+			})
+		}
+		t.Sections = append(t.Sections, section)
+	}
+	return t
+}
+
+// Lookup returns the documentation of the keywords matching k, by name or
+// alias, in all the section types. The types sharing the same keyword definition are
+// documented together. The k section can be a section name, like fs#1, or
+// a driver group name, like fs.
+func (t Schema) Lookup(k key.T) SchemaKeywordDocs {
+	l := make(SchemaKeywordDocs, 0)
+	section := strings.SplitN(k.Section, "#", 2)[0]
+	option := strings.SplitN(k.Option, "@", 2)[0]
+	add := func(s SchemaSection, typ string, kws []SchemaKeyword) {
+		for _, kw := range kws {
+			if kw.Option != option && !stringslice.Has(option, kw.Aliases) {
+				continue
+			}
+			for i, e := range l {
+				if e.Section == s.Name && reflect.DeepEqual(e.SchemaKeyword, kw) {
+					l[i].Types = append(l[i].Types, typ)
+					return
+				}
+			}
+			doc := SchemaKeywordDoc{Section: s.Name, SchemaKeyword: kw}
+			if typ != "" {
+				doc.Types = []string{typ}
+			}
+			l = append(l, doc)
+		}
+	}
+	for _, s := range t.Sections {
+		if s.Name != section {
+			continue
+		}
+		add(s, "", s.Keywords)
+		for _, typ := range s.Types {
+			add(s, typ.Name, typ.Keywords)
+		}
+	}
+	return l
+}
@@ -0,0 +1,303 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+// JSONSchema returns the JSON Schema, draft 07, of the json representation
+// of the configuration, where each section is an object with string values.
+// The sections of typed driver groups are validated against the schema of
+// the driver selected by their type keyword.
+func (t Schema) JSONSchema() map[string]interface{} {
+	properties := make(map[string]interface{})
+	patternProperties := make(map[string]interface{})
+	definitions := make(map[string]interface{})
+	for _, s := range t.Sections {
+		definitions[s.Name] = s.jsonSchema(definitions)
+		ref := map[string]interface{}{"$ref": "#/definitions/" + s.Name}
+		if s.Pattern == "" {
+			properties[s.Name] = ref
+		} else {
+			patternProperties[s.Pattern] = ref
+		}
+	}
+	return map[string]interface{}{
+		"$schema":              "http://json-schema.org/draft-07/schema#",
+		"title":                fmt.Sprintf("opensvc %s configuration", t.Kind),
+		"type":                 "object",
+		"properties":           properties,
+		"patternProperties":    patternProperties,
+		"additionalProperties": false,
+		"definitions":          definitions,
+	}
+}
+
+func (t SchemaSection) jsonSchema(definitions map[string]interface{}) map[string]interface{} {
+	if t.Free {
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": map[string]interface{}{"type": "string"},
+		}
+	}
+	if len(t.Types) == 0 {
+		return keywordsJSONSchema(t.Keywords, SchemaKeyword.jsonSchema)
+	}
+	// The keyword schemas are defined once per distinct definition, and
+	// referenced by the types, to keep the schema of driver groups with
+	// many types small.
+	variants := make(map[string][]SchemaKeyword)
+	keywordSchema := func(kw SchemaKeyword) interface{} {
+		i := 0
+		for ; i < len(variants[kw.Option]); i++ {
+			if reflect.DeepEqual(variants[kw.Option][i], kw) {
+				break
+			}
+		}
+		name := t.Name + ":" + kw.Option
+		if i > 0 {
+			name += fmt.Sprintf(":%d", i)
+		}
+		if i == len(variants[kw.Option]) {
+			variants[kw.Option] = append(variants[kw.Option], kw)
+			definitions[name] = kw.jsonSchema()
+		}
+		return map[string]interface{}{"$ref": "#/definitions/" + name}
+	}
+	names := make([]string, len(t.Types))
+	conditions := make([]interface{}, 0)
+	for i, typ := range t.Types {
+		names[i] = typ.Name
+		name := t.Name + "." + typ.Name
+		definitions[name] = keywordsJSONSchema(typ.Keywords, keywordSchema, typ.Name)
+		ref := map[string]interface{}{"$ref": "#/definitions/" + name}
+		conditions = append(conditions, map[string]interface{}{
+			"if": map[string]interface{}{
+				"properties": map[string]interface{}{"type": map[string]interface{}{"const": typ.Name}},
+				"required":   []string{"type"},
+			},
+			"then": ref,
+		})
+		if typ.Name == t.DefaultType {
+			conditions = append(conditions, map[string]interface{}{
+				"if":   map[string]interface{}{"not": map[string]interface{}{"required": []string{"type"}}},
+				"then": ref,
+			})
+		}
+	}
+	m := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"type": map[string]interface{}{"enum": names},
+		},
+		"allOf": conditions,
+	}
+	if t.DefaultType == "" {
+		m["required"] = []string{"type"}
+	}
+	return m
+}
+
+// keywordsJSONSchema returns the schema of a section accepting the
+// keywords, whose schemas are returned by the keywordSchema function.
+// If typ is set, the type keyword value must be typ.
+func keywordsJSONSchema(kws []SchemaKeyword, keywordSchema func(SchemaKeyword) interface{}, typ ...string) map[string]interface{} {
+	properties := make(map[string]interface{})
+	patternProperties := make(map[string]interface{})
+	required := make([]string, 0)
+	for _, kw := range kws {
+		m := keywordSchema(kw)
+		// the aliases are accepted for compatibility with the
+		// configurations written by older agents.
+		for _, option := range append([]string{kw.Option}, kw.Aliases...) {
+			properties[option] = m
+			if kw.Scopable {
+				patternProperties["^"+option+"@.+$"] = m
+			}
+		}
+		if !kw.Scopable && kw.Required && len(kw.Aliases) == 0 {
+			// a scopable required keyword can be set with only
+			// scoped values, and an aliased keyword with one of
+			// its names, which a json schema can't express.
+			required = append(required, kw.Option)
+		}
+	}
+	if len(typ) > 0 {
+		properties["type"] = map[string]interface{}{"const": typ[0]}
+	}
+	m := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(patternProperties) > 0 {
+		m["patternProperties"] = patternProperties
+	}
+	if len(required) > 0 {
+		m["required"] = required
+	}
+	return m
+}
+
+func (t SchemaKeyword) jsonSchema() interface{} {
+	m := map[string]interface{}{
+		"type":        "string",
+		"description": t.Text,
+	}
+	if t.Default != "" {
+		m["default"] = t.Default
+	}
+	if t.Example != "" {
+		m["examples"] = []string{t.Example}
+	}
+	if len(t.Candidates) > 0 && !t.isList() {
+		m["enum"] = t.Candidates
+	}
+	return m
+}
+
+// isList returns true if the keyword value is a list, whose elements
+// are to be found in the candidates.
+func (t SchemaKeyword) isList() bool {
+	switch t.Converter {
+	case "list", "list-lowercase", "set", "shlex":
+		return true
+	default:
+		return false
+	}
+}
+
+// Markdown returns the documentation of the sections and keywords of the
+// configuration, formatted as markdown.
+func (t Schema) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s configuration\n", t.Kind)
+	for _, s := range t.Sections {
+		switch {
+		case s.Free:
+			fmt.Fprintf(&b, "\n## %s\n\nAny option is accepted in this section.\n", s.Name)
+		case len(s.Types) == 0:
+			fmt.Fprintf(&b, "\n## %s\n", s.Name)
+			s.markdownPattern(&b)
+			for _, kw := range s.Keywords {
+				kw.markdown(&b)
+			}
+		default:
+			for _, typ := range s.Types {
+				fmt.Fprintf(&b, "\n## %s, type=%s\n", s.Name, typ.Name)
+				s.markdownPattern(&b)
+				if typ.Name == s.DefaultType {
+					fmt.Fprintf(&b, "\nThis is the default type.\n")
+				}
+				for _, kw := range typ.Keywords {
+					kw.markdown(&b)
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+func (t SchemaSection) markdownPattern(b *strings.Builder) {
+	if t.Pattern != "" {
+		fmt.Fprintf(b, "\nSection names match `%s`.\n", t.Pattern)
+	}
+}
+
+func (t SchemaKeyword) markdown(b *strings.Builder) {
+	fmt.Fprintf(b, "\n### %s\n\n", t.Option)
+	fmt.Fprintf(b, "| attribute | value |\n|---|---|\n")
+	for _, e := range t.attributes() {
+		fmt.Fprintf(b, "| %s | %s |\n", e[0], strings.ReplaceAll(e[1], "|", "\\|"))
+	}
+	if t.Text != "" {
+		fmt.Fprintf(b, "\n%s\n", t.Text)
+	}
+}
+
+// attributes returns the name and value of the keyword attributes, in
+// display order. The attributes with no value are omitted.
+func (t SchemaKeyword) attributes() [][2]string {
+	l := [][2]string{
+		{"scopable", fmt.Sprint(t.Scopable)},
+		{"required", fmt.Sprint(t.Required)},
+		{"provisioning", fmt.Sprint(t.Provisioning)},
+	}
+	add := func(k, v string) {
+		if v != "" {
+			l = append(l, [2]string{k, v})
+		}
+	}
+	add("converter", t.Converter)
+	if t.DefaultText != "" {
+		add("default", t.DefaultText)
+	} else {
+		add("default", t.Default)
+	}
+	add("candidates", strings.Join(t.Candidates, ", "))
+	add("example", t.Example)
+	add("aliases", strings.Join(t.Aliases, ", "))
+	add("deprecated", t.Deprecated)
+	add("replaced by", t.ReplacedBy)
+	return l
+}
+
+// Render returns the man-page style documentation of the keywords.
+func (t SchemaKeywordDocs) Render() string {
+	l := make([]string, len(t))
+	for i, e := range t {
+		l[i] = e.Render()
+	}
+	return strings.Join(l, "\n")
+}
+
+// Render returns the man-page style documentation of the keyword.
+func (t SchemaKeywordDoc) Render() string {
+	var b strings.Builder
+	b.WriteString(rawconfig.Node.Colorize.Bold("keyword:") + fmt.Sprintf("       %s.%s\n", t.Section, t.Option))
+	if len(t.Types) > 0 {
+		for i, line := range wrapText(strings.Join(t.Types, " "), 64) {
+			if i == 0 {
+				b.WriteString(rawconfig.Node.Colorize.Bold("types:") + "         " + line + "\n")
+			} else {
+				b.WriteString("               " + line + "\n")
+			}
+		}
+	}
+	b.WriteString("  " + strings.Repeat("-", 76) + "\n")
+	for _, e := range t.attributes() {
+		fmt.Fprintf(&b, "  %-14s %s\n", e[0]+":", e[1])
+	}
+	if t.Text != "" {
+		b.WriteString("\n")
+		for _, line := range wrapText(t.Text, 74) {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+	return b.String()
+}
+
+// wrapText splits the text paragraphs in lines of at most width
+// characters, breaking on spaces.
+func wrapText(s string, width int) []string {
+	lines := make([]string, 0)
+	for _, paragraph := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			switch {
+			case line == "":
+				line = word
+			case len(line)+1+len(word) > width:
+				lines = append(lines, line)
+				line = word
+			default:
+				line += " " + word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
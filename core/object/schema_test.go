@@ -0,0 +1,73 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/util/key"
+)
+
+func TestNewSchema(t *testing.T) {
+	t.Run("unsupported kind", func(t *testing.T) {
+		_, err := NewSchema("foo")
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrSchemaKind))
+	})
+	t.Run("object keywords are filtered by kind", func(t *testing.T) {
+		schema, err := NewSchema("vol")
+		require.NoError(t, err)
+		assert.Len(t, schema.Lookup(key.Parse("DEFAULT.pool")), 1)
+		schema, err = NewSchema("svc")
+		require.NoError(t, err)
+		assert.Len(t, schema.Lookup(key.Parse("DEFAULT.pool")), 0)
+	})
+	t.Run("keystores have no resource sections", func(t *testing.T) {
+		schema, err := NewSchema("sec")
+		require.NoError(t, err)
+		for _, s := range schema.Sections {
+			assert.NotEqual(t, "subset", s.Name)
+		}
+	})
+	t.Run("node typed sections", func(t *testing.T) {
+		schema, err := NewSchema("node")
+		require.NoError(t, err)
+		docs := schema.Lookup(key.Parse("pool#p1.type"))
+		require.Len(t, docs, 1)
+		assert.Equal(t, "pool", docs[0].Section)
+		assert.Contains(t, docs[0].Types, "vg")
+		assert.Contains(t, docs[0].Types, "loop")
+	})
+	t.Run("keyword aliases", func(t *testing.T) {
+		schema, err := NewSchema("svc")
+		require.NoError(t, err)
+		docs := schema.Lookup(key.Parse("DEFAULT.cluster_type"))
+		require.Len(t, docs, 1)
+		assert.Equal(t, "topology", docs[0].Option)
+		assert.Contains(t, docs[0].Render(), "cluster_type")
+	})
+}
+
+func TestSchemaJSONSchema(t *testing.T) {
+	schema, err := NewSchema("svc")
+	require.NoError(t, err)
+	m := schema.JSONSchema()
+	assert.Equal(t, false, m["additionalProperties"])
+	definitions := m["definitions"].(map[string]interface{})
+	section := definitions["DEFAULT"].(map[string]interface{})
+	properties := section["properties"].(map[string]interface{})
+	assert.Contains(t, properties, "nodes")
+	assert.Contains(t, properties, "extends")
+	assert.Contains(t, properties, "cluster_type", "keyword aliases are accepted")
+	assert.NotContains(t, section["patternProperties"], "^nodes@.+$", "nodes is not scopable")
+}
+
+func TestSchemaMarkdown(t *testing.T) {
+	schema, err := NewSchema("cluster")
+	require.NoError(t, err)
+	s := schema.Markdown()
+	assert.Contains(t, s, "# cluster configuration\n")
+	assert.Contains(t, s, "\n## hb, type=unicast\n")
+	assert.NotContains(t, s, "\n### oci\n", "node.oci is a node.conf keyword")
+}
@@ -8,6 +8,8 @@ import (
 
 	reqjsonrpc "opensvc.com/opensvc/core/client/requester/jsonrpc"
 	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/seccrypt"
 	"opensvc.com/opensvc/util/funcopt"
 )
 
@@ -35,29 +37,37 @@ func NewSec(p path.T, opts ...funcopt.O) *Sec {
 	s := &Sec{}
 	s.CustomEncode = secEncode
 	s.CustomDecode = secDecode
+	s.customStatusEval = s.certStatusEval
 	s.Base.init(p, opts...)
 	return s
 }
 
 func secEncode(b []byte) (string, error) {
-	m := reqjsonrpc.NewMessage(b)
-	b, err := m.Encrypt()
-	if err != nil {
-		return "", err
-	}
-	return "crypt:" + base64.URLEncoding.Strict().EncodeToString(b), nil
+	return seccrypt.Encrypt(b, rawconfig.Node.Cluster.Secret)
 }
 
 func secDecode(s string) ([]byte, error) {
-	if !strings.HasPrefix(s, "crypt:") {
-		return []byte{}, fmt.Errorf("unsupported value (no crypt prefix)")
+	switch {
+	case seccrypt.IsEncrypted(s):
+		return seccrypt.Decrypt(s, rawconfig.Node.Cluster.Secrets())
+	case strings.HasPrefix(s, "crypt:"):
+		return secDecodeLegacy(s)
+	default:
+		return []byte{}, fmt.Errorf("unsupported value (no %s or crypt: prefix)", seccrypt.Prefix)
 	}
+}
 
+// secDecodeLegacy decodes the values encrypted with the AES-CBC jsonrpc
+// message format used before the gcm1 format.
+func secDecodeLegacy(s string) ([]byte, error) {
 	// decode base64
 	b, err := base64.URLEncoding.DecodeString(s[6:])
 	if err != nil {
 		return []byte{}, err
 	}
+	if len(b) == 0 {
+		return []byte{}, fmt.Errorf("unsupported value (empty)")
+	}
 
 	// remove the trailing \r
 	last := len(b) - 1
@@ -78,3 +88,13 @@ func secDecode(s string) ([]byte, error) {
 	}
 	return []byte(s), nil
 }
+
+// secNeedRekey returns true if the value is not encrypted in the gcm1
+// format with the current cluster secret.
+func secNeedRekey(s string) bool {
+	kid, err := seccrypt.ValueKeyID(s)
+	if err != nil {
+		return true
+	}
+	return kid != seccrypt.KeyID(rawconfig.Node.Cluster.Secret)
+}
@@ -0,0 +1,91 @@
+package object
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/render/tree"
+)
+
+type (
+	// OptsCertInfo is the options of the CertInfoData function of sec objects.
+	OptsCertInfo struct {
+		Global OptsGlobal
+	}
+
+	// CertInfoData describes the certificate stored in a sec object.
+	CertInfoData struct {
+		Subject            string    `json:"subject"`
+		Issuer             string    `json:"issuer"`
+		NotBefore          time.Time `json:"not_before"`
+		NotAfter           time.Time `json:"not_after"`
+		Algorithm          string    `json:"algorithm"`
+		SignatureAlgorithm string    `json:"signature_algorithm"`
+		IsCA               bool      `json:"is_ca"`
+		DNSNames           []string  `json:"dns_names"`
+		IPAddresses        []string  `json:"ip_addresses"`
+	}
+)
+
+// CertInfoData returns the description of the stored certificate.
+func (t *Sec) CertInfoData(options OptsCertInfo) (CertInfoData, error) {
+	data := CertInfoData{}
+	b, err := t.decode("certificate")
+	if err != nil {
+		return data, err
+	}
+	cert, err := certFromPEM(b)
+	if err != nil {
+		return data, err
+	}
+	data.Subject = cert.Subject.String()
+	data.Issuer = cert.Issuer.String()
+	data.NotBefore = cert.NotBefore
+	data.NotAfter = cert.NotAfter
+	data.Algorithm = certAlgorithm(cert)
+	data.SignatureAlgorithm = cert.SignatureAlgorithm.String()
+	data.IsCA = cert.IsCA
+	data.DNSNames = cert.DNSNames
+	data.IPAddresses = make([]string, len(cert.IPAddresses))
+	for i, ip := range cert.IPAddresses {
+		data.IPAddresses[i] = ip.String()
+	}
+	return data, nil
+}
+
+func certAlgorithm(cert *x509.Certificate) string {
+	switch pub := cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("%s-%s", cert.PublicKeyAlgorithm, pub.Curve.Params().Name)
+	case *rsa.PublicKey:
+		return fmt.Sprintf("%s-%d", cert.PublicKeyAlgorithm, pub.Size()*8)
+	default:
+		return cert.PublicKeyAlgorithm.String()
+	}
+}
+
+// Render returns a human friendly string representation of the type instance.
+func (t CertInfoData) Render() string {
+	tr := tree.New()
+	tr.AddColumn().AddText("key").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("value").SetColor(rawconfig.Node.Color.Bold)
+	add := func(k string, v interface{}) {
+		n := tr.AddNode()
+		n.AddColumn().AddText(k).SetColor(rawconfig.Node.Color.Primary)
+		n.AddColumn().AddText(fmt.Sprint(v))
+	}
+	add("subject", t.Subject)
+	add("issuer", t.Issuer)
+	add("not_before", t.NotBefore.Format(time.RFC3339))
+	add("not_after", t.NotAfter.Format(time.RFC3339))
+	add("algorithm", t.Algorithm)
+	add("signature_algorithm", t.SignatureAlgorithm)
+	add("is_ca", t.IsCA)
+	add("dns_names", t.DNSNames)
+	add("ip_addresses", t.IPAddresses)
+	return tr.Render()
+}
@@ -0,0 +1,181 @@
+package object
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/schedule"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/converters"
+	"opensvc.com/opensvc/util/key"
+)
+
+// OptsCertRenew is the options of the CertRenew function of sec objects.
+type OptsCertRenew struct {
+	Global          OptsGlobal
+	Lock            OptsLocking
+	IfExpiresWithin string `flag:"if-expires-within"`
+	Force           bool   `flag:"force"`
+}
+
+// CertRenew regenerates the certificate, reusing the private key.
+//
+// The certificate is renewed only if it expires within
+// options.IfExpiresWithin, or within cert_renew_within if not set, so the
+// scheduled renewal is a no-op for a fresh certificate. options.Force
+// renews the certificate unconditionally. The post_cert_renew command is
+// executed after a renewal.
+func (t *Sec) CertRenew(options OptsCertRenew) error {
+	within := t.CertRenewWithin()
+	if options.IfExpiresWithin != "" {
+		i, err := converters.Duration.Convert(options.IfExpiresWithin)
+		if err != nil {
+			return errors.Wrapf(err, "invalid --if-expires-within value")
+		}
+		within = *i.(*time.Duration)
+	}
+	if notAfter, err := t.CertNotAfter(); err == nil && !options.Force && !certExpiresWithin(notAfter, within) {
+		t.log.Info().Time("not_after", notAfter).Msg("certificate renewal not needed")
+		return nil
+	}
+	if err := t.GenCert(OptsGenCert{Global: options.Global, Lock: options.Lock}); err != nil {
+		return err
+	}
+	notAfter, _ := t.CertNotAfter()
+	t.log.Info().Time("not_after", notAfter).Msg("certificate renewed")
+	return t.postCertRenew()
+}
+
+func (t *Sec) postCertRenew() error {
+	s := t.config.GetString(key.Parse("post_cert_renew"))
+	if s == "" {
+		return nil
+	}
+	cmdArgs, err := command.CmdArgsFromString(s)
+	if err != nil {
+		return err
+	}
+	if len(cmdArgs) == 0 {
+		return nil
+	}
+	t.log.Info().Msgf("post_cert_renew: %s", s)
+	env := append(os.Environ(), "OPENSVC_SEC_PATH="+t.Path.String())
+	cmd := command.New(
+		command.WithName(cmdArgs[0]),
+		command.WithVarArgs(cmdArgs[1:]...),
+		command.WithEnv(env),
+		command.WithLogger(&t.log),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel))
+	return cmd.Run()
+}
+
+// CertNotAfter returns the expiry date of the certificate stored in the
+// certificate key.
+func (t *Sec) CertNotAfter() (time.Time, error) {
+	b, err := t.decode("certificate")
+	if err != nil {
+		return time.Time{}, err
+	}
+	cert, err := certFromPEM(b)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// CertRenewWithin returns the cert_renew_within keyword value.
+func (t *Sec) CertRenewWithin() time.Duration {
+	if d := t.config.GetDuration(key.Parse("cert_renew_within")); d != nil {
+		return *d
+	}
+	return 0
+}
+
+func certExpiresWithin(notAfter time.Time, d time.Duration) bool {
+	return time.Now().Add(d).After(notAfter)
+}
+
+// certStatusEval sets the instance status to warn if the certificate
+// expires within cert_renew_within. The expiry is logged only when it
+// changes from the last status evaluation, as the status is evaluated
+// often.
+func (t *Sec) certStatusEval(data *instance.Status) {
+	notAfter, err := t.CertNotAfter()
+	if err != nil {
+		return
+	}
+	var state, msg string
+	switch {
+	case time.Now().After(notAfter):
+		state = "expired"
+		msg = fmt.Sprintf("certificate expired on %s", notAfter.Format(time.RFC3339))
+	case certExpiresWithin(notAfter, t.CertRenewWithin()):
+		state = "expires"
+		msg = fmt.Sprintf("certificate expires on %s", notAfter.Format(time.RFC3339))
+	}
+	if state != "" {
+		data.Overall = status.Warn
+	}
+	if !t.setCertState(state) {
+		return
+	}
+	if state == "" {
+		t.log.Info().Time("not_after", notAfter).Msg("certificate no longer expires within cert_renew_within")
+		return
+	}
+	t.log.Warn().Time("not_after", notAfter).Msg(msg)
+}
+
+// certStateFile is the path of the file storing the certificate expiry
+// state of the last status evaluation.
+func (t *Sec) certStateFile() string {
+	return filepath.Join(t.varDir(), "cert_state")
+}
+
+// setCertState stores the certificate expiry state, and returns true if
+// it changed. An empty state removes the file.
+func (t *Sec) setCertState(state string) bool {
+	p := t.certStateFile()
+	b, _ := ioutil.ReadFile(p)
+	if string(b) == state {
+		return false
+	}
+	if state == "" {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			t.log.Debug().Err(err).Msg("remove certificate state")
+		}
+		return true
+	}
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		t.log.Debug().Err(err).Msg("store certificate state")
+		return true
+	}
+	if err := ioutil.WriteFile(p, []byte(state), 0644); err != nil {
+		t.log.Debug().Err(err).Msg("store certificate state")
+	}
+	return true
+}
+
+// Schedules returns the object scheduling table, including the
+// certificate renewal entry for secrets with a ca.
+func (t *Sec) Schedules() schedule.Table {
+	table := t.Base.Schedules()
+	if t.CertInfo("ca") == "" {
+		return table
+	}
+	e := t.newScheduleEntry("cert_renew", "cert_renew_schedule", "cert_renew")
+	return table.Add(e)
+}
+
+// PrintSchedule display the object scheduling table
+func (t *Sec) PrintSchedule(options OptsPrintSchedule) schedule.Table {
+	return t.Schedules()
+}
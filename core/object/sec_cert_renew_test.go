@@ -0,0 +1,45 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestCertRenew(t *testing.T) {
+	setTestRoot(t, "0")
+	saved := rawconfig.Node.Cluster.Secret
+	rawconfig.Node.Cluster.Secret = "d2a5e9c2b6d84a29a7e6bb1ec42b3a9f"
+	t.Cleanup(func() { rawconfig.Node.Cluster.Secret = saved })
+	p, err := path.Parse("ns1/sec/s1")
+	require.NoError(t, err)
+	installTestConfig(t, p, "[DEFAULT]\nkey_type = ecdsa-p256\ncert_renew_within = 7d\n")
+	o := NewSec(p)
+	require.NoError(t, o.GenCert(OptsGenCert{}))
+	cert, err := o.decode("certificate")
+	require.NoError(t, err)
+
+	renewed := func() bool {
+		b, err := o.decode("certificate")
+		require.NoError(t, err)
+		defer func() { cert = b }()
+		return string(b) != string(cert)
+	}
+
+	require.NoError(t, o.CertRenew(OptsCertRenew{}))
+	assert.False(t, renewed(), "the scheduled renewal of a fresh certificate is a no-op")
+
+	require.NoError(t, o.CertRenew(OptsCertRenew{IfExpiresWithin: "1d"}))
+	assert.False(t, renewed(), "not expiring within 1d")
+
+	require.NoError(t, o.CertRenew(OptsCertRenew{IfExpiresWithin: "100000d"}))
+	assert.True(t, renewed(), "expiring within 100000d")
+
+	require.NoError(t, o.CertRenew(OptsCertRenew{Force: true}))
+	assert.True(t, renewed(), "forced")
+
+	assert.Error(t, o.CertRenew(OptsCertRenew{IfExpiresWithin: "foo"}))
+}
@@ -0,0 +1,165 @@
+package object
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/util/file"
+)
+
+type (
+	// OptsGenCSR is the options of the GenCSR function of sec objects.
+	OptsGenCSR struct {
+		Global OptsGlobal
+		Lock   OptsLocking
+	}
+
+	// OptsImportCert is the options of the ImportCert function of sec objects.
+	OptsImportCert struct {
+		Global OptsGlobal
+		Lock   OptsLocking
+		From   string `flag:"from"`
+	}
+)
+
+// GenCSR returns a PEM-encoded certificate signing request built from the
+// subject keywords, to submit to an external certificate authority.
+//
+// A private key is generated and stored if none is stored yet, or if the
+// stored key type does not match the key_type keyword.
+//
+// Unlike the self-issued certificates, the request has no implicit
+// 127.0.0.1 ip address: a public authority would reject it.
+func (t *Sec) GenCSR(options OptsGenCSR) ([]byte, error) {
+	priv, err := t.getPriv()
+	if err != nil {
+		return nil, err
+	}
+	tmpl := x509.CertificateRequest{
+		Subject:        t.subject(),
+		DNSNames:       t.DNSNamesFromAltNames(),
+		IPAddresses:    t.altNamesIPAddresses(),
+		EmailAddresses: t.emailAddresses(),
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &tmpl, priv)
+	if err != nil {
+		return nil, errors.Wrap(err, "create certificate signing request")
+	}
+	// commit the private key possibly generated by getPriv
+	if err := t.config.Commit(); err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), nil
+}
+
+// ImportCert stores a certificate signed by an external certificate
+// authority, with its optional intermediate certificates.
+//
+// The PEM-encoded chain must start with the certificate of the stored
+// private key, followed by the certificates of its issuers, each one
+// signing the previous. The certificate_chain key is replaced by the
+// imported chain, even if it holds only the certificate.
+func (t *Sec) ImportCert(options OptsImportCert) error {
+	b, err := readFrom(options.From)
+	if err != nil {
+		return err
+	}
+	certs, err := certsFromPEM(b)
+	if err != nil {
+		return err
+	}
+	if err := t.validateChain(certs); err != nil {
+		return err
+	}
+	leaf := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certs[0].Raw})
+	if err := t.addKey("certificate", leaf); err != nil {
+		return err
+	}
+	// always replace the chain, so the chain of a previous certificate
+	// is not exported with this one
+	chain := bytes.NewBuffer(nil)
+	for _, cert := range certs {
+		chain.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	}
+	if err := t.addKey("certificate_chain", chain.Bytes()); err != nil {
+		return err
+	}
+	return t.config.Commit()
+}
+
+func (t *Sec) validateChain(certs []*x509.Certificate) error {
+	b, err := t.decode("private_key")
+	if err != nil {
+		return errors.Wrap(err, "no private key to match the certificate")
+	}
+	priv, err := privFromPEM(b)
+	if err != nil {
+		return err
+	}
+	leaf := certs[0]
+	certPub, err := x509.MarshalPKIXPublicKey(leaf.PublicKey)
+	if err != nil {
+		return err
+	}
+	privPub, err := x509.MarshalPKIXPublicKey(priv.Public())
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(certPub, privPub) {
+		return fmt.Errorf("the certificate public key does not match the stored private key")
+	}
+	now := time.Now()
+	for i, cert := range certs {
+		if now.After(cert.NotAfter) || now.Before(cert.NotBefore) {
+			return fmt.Errorf("certificate %s is not valid now (%s - %s)", cert.Subject, cert.NotBefore, cert.NotAfter)
+		}
+		if i == 0 {
+			continue
+		}
+		if err := certs[i-1].CheckSignatureFrom(cert); err != nil {
+			return errors.Wrapf(err, "certificate %s is not signed by %s", certs[i-1].Subject, cert.Subject)
+		}
+	}
+	return nil
+}
+
+func certsFromPEM(b []byte) ([]*x509.Certificate, error) {
+	l := make([]*x509.Certificate, 0)
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, cert)
+	}
+	if len(l) == 0 {
+		return nil, fmt.Errorf("no PEM certificate found")
+	}
+	return l, nil
+}
+
+// readFrom returns the content of the file, or of stdin if the file is
+// "-" or empty.
+func readFrom(p string) ([]byte, error) {
+	switch p {
+	case "", "-", "/dev/stdin":
+		return ioutil.ReadAll(os.Stdin)
+	default:
+		return file.ReadAll(p)
+	}
+}
@@ -0,0 +1,86 @@
+package object
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestImportCertReplacesChain(t *testing.T) {
+	setTestRoot(t, "0")
+	saved := rawconfig.Node.Cluster.Secret
+	rawconfig.Node.Cluster.Secret = "d2a5e9c2b6d84a29a7e6bb1ec42b3a9f"
+	t.Cleanup(func() { rawconfig.Node.Cluster.Secret = saved })
+	p, err := path.Parse("ns1/sec/s1")
+	require.NoError(t, err)
+	installTestConfig(t, p, "[DEFAULT]\n")
+	o := NewSec(p)
+
+	caPriv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	require.NoError(t, o.addKey("private_key", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+	require.NoError(t, o.config.Commit())
+
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caCert, caPEM, err := genCert(caTmpl, caTmpl, caPriv.Public(), caPriv)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "s1"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	_, leafPEM, err := genCert(tmpl, caCert, priv.Public(), caPriv)
+	require.NoError(t, err)
+
+	importCert := func(b []byte) {
+		f := filepath.Join(t.TempDir(), "chain.pem")
+		require.NoError(t, ioutil.WriteFile(f, b, 0600))
+		require.NoError(t, o.ImportCert(OptsImportCert{From: f}))
+	}
+
+	importCert(append(append([]byte{}, leafPEM...), caPEM...))
+	b, err := o.decode("certificate_chain")
+	require.NoError(t, err)
+	assert.Equal(t, append(append([]byte{}, leafPEM...), caPEM...), b)
+
+	// re-import a single certificate onto the ca-signed sec
+	tmpl.SerialNumber = big.NewInt(3)
+	_, selfPEM, err := genCert(tmpl, tmpl, priv.Public(), priv)
+	require.NoError(t, err)
+	importCert(selfPEM)
+	b, err = o.decode("certificate_chain")
+	require.NoError(t, err)
+	assert.Equal(t, selfPEM, b, "the previous chain is replaced")
+	b, err = o.Export(OptsExport{})
+	require.NoError(t, err)
+	certs, err := certsFromPEM(b)
+	require.NoError(t, err)
+	require.Len(t, certs, 1, "the ca certificate is not exported")
+	assert.Equal(t, "s1", certs[0].Subject.CommonName)
+}
@@ -0,0 +1,95 @@
+package object
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509"
+	"fmt"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+type (
+	// OptsExport is the options of the Export function of sec objects.
+	//
+	// Format is declared before Global, so the --format flag selects the
+	// export format instead of the output format.
+	OptsExport struct {
+		Format   string `flag:"exportformat"`
+		Global   OptsGlobal
+		Lock     OptsLocking
+		Password string `flag:"password"`
+	}
+)
+
+const (
+	ExportFormatPEMBundle     = "pem-bundle"
+	ExportFormatPKCS12        = "pkcs12"
+	ExportFormatJKSCompatible = "jks-compatible"
+)
+
+// Export returns the private key and certificate chain in the format
+// expected by the consumers:
+//
+// * pem-bundle: the PEM-encoded private key, certificate and issuer
+// certificates.
+//
+// * pkcs12: a PKCS#12 archive protected by the password, which can be
+// empty.
+//
+// * jks-compatible: a PKCS#12 archive loadable by the Java keytool and
+// KeyStore. The private key is encrypted with pbeWithSHAAnd3-KeyTripleDES-CBC,
+// the certificates with pbeWithSHAAnd40BitRC2-CBC, and the password is
+// mandatory, because Java refuses the keystores without a password.
+func (t *Sec) Export(options OptsExport) ([]byte, error) {
+	keyPEM, err := t.decode("private_key")
+	if err != nil {
+		return nil, err
+	}
+	chainPEM, err := t.decode("certificate_chain")
+	if err != nil {
+		if chainPEM, err = t.decode("certificate"); err != nil {
+			return nil, err
+		}
+	}
+	switch options.Format {
+	case "", ExportFormatPEMBundle:
+		return append(append([]byte{}, keyPEM...), chainPEM...), nil
+	case ExportFormatPKCS12:
+		return exportPKCS12(keyPEM, chainPEM, options.Password)
+	case ExportFormatJKSCompatible:
+		return exportJKSCompatible(keyPEM, chainPEM, options.Password)
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", options.Format)
+	}
+}
+
+func exportPKCS12(keyPEM, chainPEM []byte, password string) ([]byte, error) {
+	priv, err := privFromPEM(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	certs, err := certsFromPEM(chainPEM)
+	if err != nil {
+		return nil, err
+	}
+	var caCerts []*x509.Certificate
+	for _, cert := range certs[1:] {
+		// the leaf certificate can be repeated in the chain
+		if bytes.Equal(cert.Raw, certs[0].Raw) {
+			continue
+		}
+		caCerts = append(caCerts, cert)
+	}
+	return pkcs12.Encode(rand.Reader, priv, certs[0], caCerts, password)
+}
+
+// exportJKSCompatible returns a PKCS#12 archive using the Java compatible
+// encryption parameters. The archive produced by exportPKCS12 already uses
+// them, so only the password requirement is added here.
+func exportJKSCompatible(keyPEM, chainPEM []byte, password string) ([]byte, error) {
+	if password == "" {
+		return nil, fmt.Errorf("the %s format requires a password", ExportFormatJKSCompatible)
+	}
+	return exportPKCS12(keyPEM, chainPEM, password)
+}
@@ -0,0 +1,88 @@
+package object
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestExportPKCS12(t *testing.T) {
+	caPriv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caCert, caPEM, err := genCert(caTmpl, caTmpl, caPriv.Public(), caPriv)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "leaf"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	_, leafPEM, err := genCert(tmpl, caCert, priv.Public(), caPriv)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	certs, err := certsFromPEM(append(append([]byte{}, leafPEM...), caPEM...))
+	require.NoError(t, err)
+	require.Len(t, certs, 2)
+	assert.NoError(t, certs[0].CheckSignatureFrom(certs[1]))
+
+	b, err := exportPKCS12(keyPEM, append(append([]byte{}, leafPEM...), caPEM...), "secret")
+	require.NoError(t, err)
+	decodedPriv, decodedCert, decodedCACerts, err := pkcs12.DecodeChain(b, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "leaf", decodedCert.Subject.CommonName)
+	assert.Len(t, decodedCACerts, 1)
+	assert.Equal(t, priv, decodedPriv)
+
+	_, _, _, err = pkcs12.DecodeChain(b, "bad")
+	assert.Error(t, err)
+}
+
+func TestExportJKSCompatible(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "leaf"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	_, leafPEM, err := genCert(tmpl, tmpl, priv.Public(), priv)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	_, err = exportJKSCompatible(keyPEM, leafPEM, "")
+	assert.Error(t, err, "a password is required")
+
+	b, err := exportJKSCompatible(keyPEM, leafPEM, "secret")
+	require.NoError(t, err)
+	decodedPriv, decodedCert, err := pkcs12.Decode(b, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "leaf", decodedCert.Subject.CommonName)
+	assert.Equal(t, priv, decodedPriv)
+}
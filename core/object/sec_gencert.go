@@ -1,6 +1,10 @@
 package object
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -20,6 +24,13 @@ import (
 	"opensvc.com/opensvc/util/key"
 )
 
+const (
+	keyTypeRSA       = "rsa"
+	keyTypeECDSAP256 = "ecdsa-p256"
+	keyTypeECDSAP384 = "ecdsa-p384"
+	keyTypeEd25519   = "ed25519"
+)
+
 // OptsUnset is the options of the Unset object method.
 type OptsGenCert struct {
 	Global OptsGlobal
@@ -59,30 +70,34 @@ func CASecPaths() []path.T {
 	return l
 }
 
-func (t *Sec) genSelfSigned(priv *rsa.PrivateKey) error {
+func (t *Sec) genSelfSigned(priv crypto.Signer) error {
 	t.log.Debug().Msg("generate a self-signed certificate")
 	tmpl, err := t.template(true, priv)
 	if err != nil {
 		return err
 	}
-	_, certBytes, err := genCert(&tmpl, &tmpl, priv)
+	_, certBytes, err := genCert(&tmpl, &tmpl, priv.Public(), priv)
 	if err != nil {
 		return err
 	}
 	return t.addKey("certificate", certBytes)
 }
 
-func (t *Sec) genCASigned(priv *rsa.PrivateKey, ca string) error {
+func (t *Sec) genCASigned(priv crypto.Signer, ca string) error {
 	t.log.Debug().Msgf("generate a certificate signed by the CA in %s", ca)
 	caCert, caCertBytes, err := t.getCACert()
 	if err != nil {
 		return err
 	}
+	caPriv, err := t.getCAPriv()
+	if err != nil {
+		return err
+	}
 	tmpl, err := t.template(false, priv)
 	if err != nil {
 		return err
 	}
-	_, certBytes, err := genCert(&tmpl, caCert, priv)
+	_, certBytes, err := genCert(&tmpl, caCert, priv.Public(), caPriv)
 	if err != nil {
 		return err
 	}
@@ -99,6 +114,11 @@ func (t *Sec) CertInfo(name string) string {
 	return t.config.GetString(key.Parse(name))
 }
 
+// CertInfoKeyType returns the key_type keyword value.
+func (t *Sec) CertInfoKeyType() string {
+	return t.config.GetString(key.Parse("key_type"))
+}
+
 func (t *Sec) CertInfoBits() int {
 	sz := t.config.GetSize(key.Parse("bits"))
 	return int(*sz)
@@ -114,6 +134,12 @@ func (t *Sec) CertInfoNotAfter() (time.Time, error) {
 
 func (t *Sec) IPAddressesFromAltNames() []net.IP {
 	l := []net.IP{net.ParseIP("127.0.0.1")}
+	return append(l, t.altNamesIPAddresses()...)
+}
+
+// altNamesIPAddresses returns the ip addresses set in alt_names.
+func (t *Sec) altNamesIPAddresses() []net.IP {
+	l := []net.IP{}
 	for _, word := range t.config.GetSlice(key.Parse("alt_names")) {
 		ip := net.ParseIP(word)
 		if ip == nil {
@@ -149,14 +175,41 @@ func getBaseKeyUsage(priv interface{}) x509.KeyUsage {
 }
 
 func (t *Sec) subject() pkix.Name {
+	l := func(name string) []string {
+		if s := t.CertInfo(name); s != "" {
+			return []string{s}
+		}
+		return nil
+	}
 	return pkix.Name{
-		Country:            []string{t.CertInfo("c")},
-		Organization:       []string{t.CertInfo("o")},
-		OrganizationalUnit: []string{t.CertInfo("ou")},
+		Country:            l("c"),
+		Province:           l("st"),
+		Locality:           l("l"),
+		Organization:       l("o"),
+		OrganizationalUnit: l("ou"),
 		CommonName:         t.CertInfo("cn"),
 	}
 }
 
+func (t *Sec) emailAddresses() []string {
+	if s := t.CertInfo("email"); s != "" {
+		return []string{s}
+	}
+	return nil
+}
+
+// serialNumber returns a random 128-bit certificate serial number, so
+// the certificates signed by a ca, renewals included, have unique
+// serial numbers.
+func serialNumber() (*big.Int, error) {
+	limit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serial, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate serial number")
+	}
+	return serial, nil
+}
+
 // "cn", "c", "st", "l", "o", "ou", "email", "alt_names", "bits", "validity", "ca"
 func (t *Sec) template(isCA bool, priv interface{}) (x509.Certificate, error) {
 	keyUsage := getBaseKeyUsage(priv)
@@ -164,20 +217,25 @@ func (t *Sec) template(isCA bool, priv interface{}) (x509.Certificate, error) {
 	if err != nil {
 		return x509.Certificate{}, err
 	}
+	serial, err := serialNumber()
+	if err != nil {
+		return x509.Certificate{}, err
+	}
 	template := x509.Certificate{
-		SerialNumber:          big.NewInt(1),
+		SerialNumber:          serial,
 		Subject:               t.subject(),
 		NotBefore:             time.Now().Add(-10 * time.Second),
 		NotAfter:              notAfter,
 		KeyUsage:              keyUsage,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 		BasicConstraintsValid: true,
-		MaxPathLen:            2,
 		IPAddresses:           t.IPAddressesFromAltNames(),
 		DNSNames:              t.DNSNamesFromAltNames(),
+		EmailAddresses:        t.emailAddresses(),
 	}
 	if isCA {
 		template.IsCA = true
+		template.MaxPathLen = 2
 		template.KeyUsage |= x509.KeyUsageCertSign
 		template.KeyUsage |= x509.KeyUsageCRLSign
 	}
@@ -186,7 +244,7 @@ func (t *Sec) template(isCA bool, priv interface{}) (x509.Certificate, error) {
 
 func (t *Sec) getCASec() (*Sec, error) {
 	s := t.CertInfo("ca")
-	p, err := path.Parse(s)
+	p, err := path.New(s, t.Path.Namespace, "sec")
 	if err != nil {
 		return nil, errors.Wrapf(err, "invalid ca secret path: %s", s)
 	}
@@ -197,7 +255,7 @@ func (t *Sec) getCASec() (*Sec, error) {
 	return sec, nil
 }
 
-func (t *Sec) setPriv(priv *rsa.PrivateKey) error {
+func (t *Sec) setPriv(priv crypto.Signer) error {
 	b, err := x509.MarshalPKCS8PrivateKey(priv)
 	if err != nil {
 		return err
@@ -232,6 +290,9 @@ func (t *Sec) getCACert() (*x509.Certificate, []byte, error) {
 
 func certFromPEM(b []byte) (*x509.Certificate, error) {
 	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("certFromPEM: no PEM block found")
+	}
 	if block.Type != "CERTIFICATE" {
 		return nil, fmt.Errorf("certFromPEM: PEM block type is not CERTIFICATE")
 	}
@@ -242,7 +303,7 @@ func certFromPEM(b []byte) (*x509.Certificate, error) {
 	return cert, nil
 }
 
-func (t *Sec) getCAPriv() (*rsa.PrivateKey, error) {
+func (t *Sec) getCAPriv() (crypto.Signer, error) {
 	var (
 		sec *Sec
 		b   []byte
@@ -257,19 +318,40 @@ func (t *Sec) getCAPriv() (*rsa.PrivateKey, error) {
 	return privFromPEM(b)
 }
 
-func privFromPEM(b []byte) (*rsa.PrivateKey, error) {
+// privFromPEM parses a PKCS#8 private key. The PKCS#1 and SEC 1 formats
+// are also accepted for keys imported from other tools.
+func privFromPEM(b []byte) (crypto.Signer, error) {
+	var (
+		priv interface{}
+		err  error
+	)
 	block, _ := pem.Decode(b)
-	if block.Type != "PRIVATE KEY" {
-		return nil, fmt.Errorf("PEM block type is not PRIVATE KEY")
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	switch block.Type {
+	case "PRIVATE KEY":
+		priv, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		priv, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		priv, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %s", block.Type)
 	}
-	priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
 	if err != nil {
 		return nil, err
 	}
-	return priv.(*rsa.PrivateKey), nil
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", priv)
+	}
+	return signer, nil
 }
 
-func (t *Sec) getPriv() (*rsa.PrivateKey, error) {
+// getPriv returns the stored private key, or a new private key if none is
+// stored or if the stored key type does not match the key_type keyword.
+func (t *Sec) getPriv() (crypto.Signer, error) {
 	b, err := t.decode("private_key")
 	if err != nil {
 		return t.genPriv()
@@ -278,13 +360,36 @@ func (t *Sec) getPriv() (*rsa.PrivateKey, error) {
 	if err != nil {
 		return t.genPriv()
 	}
+	if keyType := t.CertInfoKeyType(); privKeyType(priv) != keyType {
+		t.log.Info().Str("key_type", keyType).Str("current_key_type", privKeyType(priv)).Msg("private key type changed")
+		return t.genPriv()
+	}
 	return priv, nil
 }
 
-func (t *Sec) genPriv() (*rsa.PrivateKey, error) {
-	bits := t.CertInfoBits()
-	t.log.Info().Int("bits", bits).Msg("generate new private key")
-	priv, err := rsa.GenerateKey(rand.Reader, bits)
+func (t *Sec) genPriv() (crypto.Signer, error) {
+	var (
+		priv crypto.Signer
+		err  error
+	)
+	keyType := t.CertInfoKeyType()
+	switch keyType {
+	case keyTypeRSA:
+		bits := t.CertInfoBits()
+		t.log.Info().Str("key_type", keyType).Int("bits", bits).Msg("generate new private key")
+		priv, err = rsa.GenerateKey(rand.Reader, bits)
+	case keyTypeECDSAP256:
+		t.log.Info().Str("key_type", keyType).Msg("generate new private key")
+		priv, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case keyTypeECDSAP384:
+		t.log.Info().Str("key_type", keyType).Msg("generate new private key")
+		priv, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case keyTypeEd25519:
+		t.log.Info().Str("key_type", keyType).Msg("generate new private key")
+		_, priv, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported key_type: %s", keyType)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -294,8 +399,26 @@ func (t *Sec) genPriv() (*rsa.PrivateKey, error) {
 	return priv, nil
 }
 
-func genCert(template, parent *x509.Certificate, priv *rsa.PrivateKey) (*x509.Certificate, []byte, error) {
-	certBytes, err := x509.CreateCertificate(rand.Reader, template, parent, &priv.PublicKey, priv)
+// privKeyType returns the key_type keyword value matching the private key.
+func privKeyType(priv crypto.Signer) string {
+	switch k := priv.(type) {
+	case *rsa.PrivateKey:
+		return keyTypeRSA
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return keyTypeECDSAP256
+		case elliptic.P384():
+			return keyTypeECDSAP384
+		}
+	case ed25519.PrivateKey:
+		return keyTypeEd25519
+	}
+	return ""
+}
+
+func genCert(template, parent *x509.Certificate, pub crypto.PublicKey, signer crypto.Signer) (*x509.Certificate, []byte, error) {
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create certificate: " + err.Error())
 	}
@@ -0,0 +1,118 @@
+package object
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivFromPEM(t *testing.T) {
+	genRSA := func() (crypto.Signer, error) { return rsa.GenerateKey(rand.Reader, 1024) }
+	genP256 := func() (crypto.Signer, error) { return ecdsa.GenerateKey(elliptic.P256(), rand.Reader) }
+	genP384 := func() (crypto.Signer, error) { return ecdsa.GenerateKey(elliptic.P384(), rand.Reader) }
+	genEd25519 := func() (crypto.Signer, error) {
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		return priv, err
+	}
+	cases := map[string]func() (crypto.Signer, error){
+		keyTypeRSA:       genRSA,
+		keyTypeECDSAP256: genP256,
+		keyTypeECDSAP384: genP384,
+		keyTypeEd25519:   genEd25519,
+	}
+	for keyType, gen := range cases {
+		t.Run(keyType, func(t *testing.T) {
+			priv, err := gen()
+			require.NoError(t, err)
+			b, err := x509.MarshalPKCS8PrivateKey(priv)
+			require.NoError(t, err)
+			pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: b})
+			parsed, err := privFromPEM(pemBytes)
+			require.NoError(t, err)
+			assert.Equal(t, keyType, privKeyType(parsed))
+		})
+	}
+	t.Run("invalid pem", func(t *testing.T) {
+		_, err := privFromPEM([]byte("foo"))
+		assert.Error(t, err)
+	})
+}
+
+func TestSerialNumber(t *testing.T) {
+	a, err := serialNumber()
+	require.NoError(t, err)
+	b, err := serialNumber()
+	require.NoError(t, err)
+	assert.NotEqual(t, a, b)
+	assert.True(t, a.Sign() >= 0)
+	assert.LessOrEqual(t, a.BitLen(), 128)
+}
+
+func TestGenCertMixedKeyTypes(t *testing.T) {
+	genRSA := func() (crypto.Signer, error) { return rsa.GenerateKey(rand.Reader, 2048) }
+	genP256 := func() (crypto.Signer, error) { return ecdsa.GenerateKey(elliptic.P256(), rand.Reader) }
+	newTemplate := func(isCA bool, priv crypto.Signer) *x509.Certificate {
+		serial, err := serialNumber()
+		require.NoError(t, err)
+		tmpl := &x509.Certificate{
+			SerialNumber:          serial,
+			NotBefore:             time.Now().Add(-10 * time.Second),
+			NotAfter:              time.Now().Add(time.Hour),
+			KeyUsage:              getBaseKeyUsage(priv),
+			ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			BasicConstraintsValid: true,
+			DNSNames:              []string{"svc1.default.svc.cluster1"},
+		}
+		if isCA {
+			tmpl.IsCA = true
+			tmpl.MaxPathLen = 2
+			tmpl.KeyUsage |= x509.KeyUsageCertSign
+			tmpl.KeyUsage |= x509.KeyUsageCRLSign
+		}
+		return tmpl
+	}
+	cases := map[string]struct {
+		ca   func() (crypto.Signer, error)
+		leaf func() (crypto.Signer, error)
+	}{
+		"ecdsa ca signs rsa leaf": {ca: genP256, leaf: genRSA},
+		"rsa ca signs ecdsa leaf": {ca: genRSA, leaf: genP256},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			caPriv, err := tc.ca()
+			require.NoError(t, err)
+			caTmpl := newTemplate(true, caPriv)
+			caCert, _, err := genCert(caTmpl, caTmpl, caPriv.Public(), caPriv)
+			require.NoError(t, err)
+
+			leafPriv, err := tc.leaf()
+			require.NoError(t, err)
+			leafCert, leafPEM, err := genCert(newTemplate(false, leafPriv), caCert, leafPriv.Public(), caPriv)
+			require.NoError(t, err)
+			parsed, err := certFromPEM(leafPEM)
+			require.NoError(t, err)
+			assert.Equal(t, leafPriv.Public(), parsed.PublicKey)
+			assert.NotEqual(t, caCert.PublicKeyAlgorithm, parsed.PublicKeyAlgorithm)
+
+			roots := x509.NewCertPool()
+			roots.AddCert(caCert)
+			_, err = leafCert.Verify(x509.VerifyOptions{
+				Roots:   roots,
+				DNSName: "svc1.default.svc.cluster1",
+			})
+			assert.NoError(t, err)
+			assert.NoError(t, leafCert.CheckSignatureFrom(caCert))
+		})
+	}
+}
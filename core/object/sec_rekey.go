@@ -0,0 +1,43 @@
+package object
+
+import (
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/keystorebackend"
+)
+
+// OptsRekey is the options of the Rekey function of sec objects.
+type OptsRekey struct {
+	Global OptsGlobal
+	Lock   OptsLocking
+}
+
+// Rekey re-encrypts with the current cluster secret the keys not yet
+// encrypted in the current format with this secret.
+//
+// Keys encrypted with the previous cluster secret can be re-encrypted
+// only during the secret rotation grace period.
+func (t *Sec) Rekey(options OptsRekey) error {
+	n := 0
+	for _, name := range t.config.Keys(DataSectionName) {
+		s, err := t.config.GetStringStrict(keyFromName(name))
+		if err != nil {
+			return err
+		}
+		if keystorebackend.IsRef(s) || !secNeedRekey(s) {
+			continue
+		}
+		b, err := t.CustomDecode(s)
+		if err != nil {
+			return errors.Wrapf(err, "decode key %s", name)
+		}
+		if err := t.addKey(name, b); err != nil {
+			return errors.Wrapf(err, "encode key %s", name)
+		}
+		t.Log().Info().Str("key", name).Msg("rekeyed")
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	return t.config.Commit()
+}
@@ -335,7 +335,9 @@ func (t *Selection) localExactExpand(s string) (*set.Set, error) {
 		return matching, err
 	}
 	o := NewBaserFromPath(p)
-	if !o.Exists() {
+	if !o.Exists() && p.Kind != kind.Ccfg {
+		// the cluster configuration always exists, with its
+		// keywords defaults, before the first set creates it.
 		return matching, nil
 	}
 	matching.Insert(p.String())
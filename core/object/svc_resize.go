@@ -0,0 +1,67 @@
+package object
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/drivergroup"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/resourceselector"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/sizeconv"
+)
+
+// Resize resizes the volumes referenced by the selected volume
+// resources, and updates their size keyword.
+//
+// A shrink is refused unless the Force option is set.
+func (t *Svc) Resize(options OptsResize) error {
+	return t.lockedAction("", options.Lock, "resize", func() error {
+		return t.lockedResize(options)
+	})
+}
+
+func (t *Svc) lockedResize(options OptsResize) error {
+	ctx := context.Background()
+	kws := make([]string, 0)
+	l := resourceselector.New(t, resourceselector.WithOptions(options.Options))
+	for _, r := range l.Resources() {
+		if r.IsDisabled() {
+			continue
+		}
+		if r.ID().DriverGroup() != drivergroup.Volume {
+			continue
+		}
+		i, ok := r.(resource.Resizer)
+		if !ok {
+			continue
+		}
+		k := key.New(r.RID(), "size")
+		size, err := t.config.GetSizeStrict(k)
+		if err != nil {
+			return errors.Wrapf(err, "%s current size", r.RID())
+		}
+		if size == nil {
+			return fmt.Errorf("%s: size keyword is not set", r.RID())
+		}
+		current := *size
+		target, err := resizeTarget(current, options.Size, options.Force)
+		if err != nil {
+			return errors.Wrapf(err, "%s", r.RID())
+		}
+		if target == current {
+			t.log.Info().Str("rid", r.RID()).Msgf("already %s", sizeconv.BSizeCompact(float64(current)))
+			continue
+		}
+		t.log.Info().Str("rid", r.RID()).Msgf("resize to %s", sizeconv.BSizeCompact(float64(target)))
+		if err := i.Resize(ctx, target); err != nil {
+			return errors.Wrapf(err, "%s", r.RID())
+		}
+		kws = append(kws, k.String()+"="+sizeconv.ExactBSizeCompact(float64(target)))
+	}
+	if len(kws) == 0 {
+		return nil
+	}
+	return t.SetKeywords(kws)
+}
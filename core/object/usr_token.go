@@ -0,0 +1,45 @@
+package object
+
+import (
+	"time"
+
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/rbac"
+	"opensvc.com/opensvc/util/key"
+)
+
+type (
+	// OptsTokenCreate is the options of the TokenCreate function of usr objects.
+	OptsTokenCreate struct {
+		Global   OptsGlobal
+		Duration time.Duration `flag:"tokenduration"`
+	}
+
+	// TokenData is the result of the TokenCreate function.
+	TokenData struct {
+		Token  string    `json:"token"`
+		Expire time.Time `json:"expire"`
+	}
+)
+
+// Grants returns the parsed grant keyword value.
+func (t *Usr) Grants() (rbac.Grants, error) {
+	return rbac.ParseGrants(t.config.GetSlice(key.Parse("grant")))
+}
+
+// TokenCreate returns a bearer token embedding the user grants, signed
+// with the cluster secret and valid for the requested duration.
+func (t *Usr) TokenCreate(options OptsTokenCreate) (TokenData, error) {
+	data := TokenData{}
+	grants, err := t.Grants()
+	if err != nil {
+		return data, err
+	}
+	data.Token, data.Expire, err = rbac.NewToken(t.Path.String(), rawconfig.Node.Cluster.Name, grants, options.Duration, rawconfig.Node.Cluster.Secret)
+	return data, err
+}
+
+// Render returns the token, for the human readable output format.
+func (t TokenData) Render() string {
+	return t.Token + "\n"
+}
@@ -32,7 +32,24 @@ func NewVol(p path.T, opts ...funcopt.O) *Vol {
 	return s
 }
 
+// MountPoint returns the path where the volume data is exposed, which is
+// the head of the first fs resource having one.
 func (t *Vol) MountPoint() string {
+	type header interface {
+		Head() string
+	}
+	l := t.Resources()
+	l.Sort()
+	for _, r := range l {
+		if r.ID().DriverGroup() != drivergroup.FS {
+			continue
+		}
+		if i, ok := r.(header); ok {
+			if s := i.Head(); s != "" {
+				return s
+			}
+		}
+	}
 	return ""
 }
 
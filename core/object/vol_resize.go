@@ -0,0 +1,128 @@
+package object
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/drivergroup"
+	"opensvc.com/opensvc/core/ordering"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/resourceselector"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/sizeconv"
+)
+
+// OptsResize is the options of the Resize object method.
+type OptsResize struct {
+	Global OptsGlobal
+	Lock   OptsLocking
+	resourceselector.Options
+	Size  string `flag:"size"`
+	Force bool   `flag:"force"`
+}
+
+// Resize grows the disk resources of the volume, then the fs resources
+// hosted on them, and updates the size keywords.
+//
+// A shrink is refused unless the Force option is set. In this case, the
+// fs resources are shrunk before the disk resources.
+func (t *Vol) Resize(options OptsResize) error {
+	return t.lockedAction("", options.Lock, "resize", func() error {
+		return t.lockedResize(options)
+	})
+}
+
+func (t *Vol) lockedResize(options OptsResize) error {
+	size, err := t.config.GetSizeStrict(key.Parse("size"))
+	if err != nil {
+		return errors.Wrapf(err, "%s current size", t.Path)
+	}
+	if size == nil {
+		return fmt.Errorf("%s: size keyword is not set", t.Path)
+	}
+	current := *size
+	target, err := resizeTarget(current, options.Size, options.Force)
+	if err != nil {
+		return errors.Wrapf(err, "%s", t.Path)
+	}
+	if target == current {
+		t.log.Info().Msgf("%s is already %s", t.Path, sizeconv.BSizeCompact(float64(current)))
+		return nil
+	}
+	order := ordering.Asc
+	if target < current {
+		order = ordering.Desc
+	}
+	ctx := context.Background()
+	l := resourceselector.New(t, resourceselector.WithOptions(options.Options), resourceselector.WithOrder(order))
+	kws, err := t.resizeResources(ctx, l.Resources(), target)
+	if err != nil {
+		return err
+	}
+	kws = append([]string{"size=" + sizeconv.ExactBSizeCompact(float64(target))}, kws...)
+	return t.SetKeywords(kws)
+}
+
+// resizeResources resizes the enabled resources implementing the
+// resource.Resizer interface, in the resources order, and returns the
+// keyword operations updating the size of the disk resources having a
+// size keyword.
+func (t *Vol) resizeResources(ctx context.Context, l []resource.Driver, target int64) ([]string, error) {
+	kws := make([]string, 0)
+	for _, r := range l {
+		if r.IsDisabled() {
+			continue
+		}
+		i, ok := r.(resource.Resizer)
+		if !ok {
+			continue
+		}
+		t.log.Info().Str("rid", r.RID()).Msgf("resize to %s", sizeconv.BSizeCompact(float64(target)))
+		if err := i.Resize(ctx, target); err != nil {
+			return kws, errors.Wrapf(err, "%s", r.RID())
+		}
+		if r.ID().DriverGroup() == drivergroup.Disk && t.config.HasKey(key.New(r.RID(), "size")) {
+			kws = append(kws, r.RID()+".size="+sizeconv.ExactBSizeCompact(float64(target)))
+		}
+	}
+	return kws, nil
+}
+
+// resizeTarget returns the size in bytes resulting from the application
+// of the s resize expression to the current size. The expression is
+// either an absolute size, or a size delta prefixed by + or -.
+func resizeTarget(current int64, s string, force bool) (int64, error) {
+	var (
+		target int64
+		sign   int64
+	)
+	switch {
+	case s == "":
+		return 0, fmt.Errorf("the new size is not set")
+	case strings.HasPrefix(s, "+"):
+		sign = 1
+	case strings.HasPrefix(s, "-"):
+		sign = -1
+	}
+	if sign != 0 {
+		s = s[1:]
+	}
+	i, err := sizeconv.FromSize(s)
+	if err != nil {
+		return 0, err
+	}
+	if sign == 0 {
+		target = i
+	} else {
+		target = current + sign*i
+	}
+	switch {
+	case target <= 0:
+		return 0, fmt.Errorf("invalid new size: %d", target)
+	case target < current && !force:
+		return 0, fmt.Errorf("refuse to shrink from %s to %s without --force", sizeconv.BSizeCompact(float64(current)), sizeconv.BSizeCompact(float64(target)))
+	}
+	return target, nil
+}
@@ -0,0 +1,111 @@
+package object
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/manifest"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/provisioned"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/status"
+)
+
+type testResizer struct {
+	resource.T
+	resized *[]string
+	err     error
+}
+
+func newTestResizer(rid string, resized *[]string, err error) *testResizer {
+	r := &testResizer{resized: resized, err: err}
+	r.SetRID(rid)
+	return r
+}
+
+func (t testResizer) Label() string                       { return "" }
+func (t testResizer) Manifest() *manifest.T               { return &manifest.T{} }
+func (t testResizer) Start(context.Context) error         { return nil }
+func (t testResizer) Stop(context.Context) error          { return nil }
+func (t testResizer) Status(context.Context) status.T     { return status.NotApplicable }
+func (t testResizer) Provisioned() (provisioned.T, error) { return provisioned.NotApplicable, nil }
+func (t testResizer) Provision(context.Context) error     { return nil }
+func (t testResizer) Unprovision(context.Context) error   { return nil }
+func (t testResizer) Resize(ctx context.Context, size int64) error {
+	if t.err != nil {
+		return t.err
+	}
+	*t.resized = append(*t.resized, t.RID())
+	return nil
+}
+
+func TestResizeTarget(t *testing.T) {
+	const g = 1024 * 1024 * 1024
+	cases := []struct {
+		expr   string
+		force  bool
+		target int64
+		err    bool
+	}{
+		{expr: "+10g", target: 20 * g},
+		{expr: "20g", target: 20 * g},
+		{expr: "10g", target: 10 * g},
+		{expr: "-1g", err: true},
+		{expr: "5g", err: true},
+		{expr: "-1g", force: true, target: 9 * g},
+		{expr: "-10g", force: true, err: true},
+		{expr: "", err: true},
+		{expr: "+foo", err: true},
+	}
+	for _, c := range cases {
+		t.Run(c.expr, func(t *testing.T) {
+			target, err := resizeTarget(10*g, c.expr, c.force)
+			if c.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.target, target)
+		})
+	}
+}
+
+func TestVolResizeResources(t *testing.T) {
+	setTestRoot(t, "10")
+	p, err := path.Parse("ns1/vol/v1")
+	require.NoError(t, err)
+	installTestConfig(t, p, "[DEFAULT]\nsize = 1g\n\n[disk#1]\ntype = loop\nsize = 1g\n\n[disk#2]\ntype = loop\n\n[fs#1]\ntype = ext4\n")
+	o := NewVol(p)
+	require.NotNil(t, o.config)
+	ctx := context.Background()
+
+	t.Run("resizers are resized in order", func(t *testing.T) {
+		resized := make([]string, 0)
+		disabled := newTestResizer("disk#3", &resized, nil)
+		disabled.Disable = true
+		l := []resource.Driver{
+			newTestResizer("fs#1", &resized, nil),
+			newTestResizer("disk#1", &resized, nil),
+			disabled,
+			newTestResizer("disk#2", &resized, nil),
+		}
+		kws, err := o.resizeResources(ctx, l, 2*1024*1024*1024)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"fs#1", "disk#1", "disk#2"}, resized)
+		assert.Equal(t, []string{"disk#1.size=2g"}, kws, "only the disk resources with a size keyword are updated")
+	})
+	t.Run("a resize error stops the resize", func(t *testing.T) {
+		resized := make([]string, 0)
+		l := []resource.Driver{
+			newTestResizer("disk#1", &resized, errors.New("no space left")),
+			newTestResizer("fs#1", &resized, nil),
+		}
+		_, err := o.resizeResources(ctx, l, 2*1024*1024*1024)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "disk#1")
+		assert.Empty(t, resized)
+	})
+}
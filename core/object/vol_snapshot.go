@@ -0,0 +1,140 @@
+package object
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/pool"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/util/key"
+)
+
+type (
+	// OptsSnapshotCreate is the options of the SnapshotCreate object method.
+	OptsSnapshotCreate struct {
+		Global OptsGlobal
+		Lock   OptsLocking
+		Name   string `flag:"snapshotname"`
+	}
+
+	// OptsSnapshotLs is the options of the Snapshots object method.
+	OptsSnapshotLs struct {
+		Global OptsGlobal
+	}
+
+	// OptsSnapshotDelete is the options of the SnapshotDelete object method.
+	OptsSnapshotDelete struct {
+		Global OptsGlobal
+		Lock   OptsLocking
+		Name   string `flag:"snapshotname"`
+	}
+
+	// OptsSnapshotRollback is the options of the SnapshotRollback object method.
+	OptsSnapshotRollback struct {
+		Global OptsGlobal
+		Lock   OptsLocking
+		Name   string `flag:"snapshotname"`
+		Force  bool   `flag:"force"`
+	}
+)
+
+// snapshotter returns the pool the volume was allocated from, if this pool
+// supports snapshots.
+func (t *Vol) snapshotter() (pool.Snapshotter, error) {
+	name := t.config.GetString(key.Parse("pool"))
+	if name == "" {
+		return nil, fmt.Errorf("%s: pool keyword is not set", t.Path)
+	}
+	p := NewNode().Pool(name)
+	if p == nil {
+		return nil, fmt.Errorf("%s: pool %s not found", t.Path, name)
+	}
+	i, ok := p.(pool.Snapshotter)
+	if !ok {
+		return nil, fmt.Errorf("%s: pool %s of type %s does not support snapshots", t.Path, name, p.Type())
+	}
+	return i, nil
+}
+
+// SnapshotCreate creates a snapshot of the volume data.
+func (t *Vol) SnapshotCreate(options OptsSnapshotCreate) error {
+	if err := pool.ValidateSnapshotName(options.Name); err != nil {
+		return errors.Wrapf(err, "%s", t.Path)
+	}
+	return t.lockedAction("", options.Lock, "snapshot create", func() error {
+		p, err := t.snapshotter()
+		if err != nil {
+			return err
+		}
+		t.log.Info().Msgf("create snapshot %s", options.Name)
+		return errors.Wrapf(p.CreateSnapshot(t.FQDN(), options.Name), "%s", t.Path)
+	})
+}
+
+// Snapshots returns the list of the volume snapshots.
+func (t *Vol) Snapshots(options OptsSnapshotLs) (pool.SnapshotList, error) {
+	p, err := t.snapshotter()
+	if err != nil {
+		return nil, err
+	}
+	return p.Snapshots(t.FQDN())
+}
+
+// SnapshotDelete deletes a snapshot of the volume data.
+func (t *Vol) SnapshotDelete(options OptsSnapshotDelete) error {
+	if err := pool.ValidateSnapshotName(options.Name); err != nil {
+		return errors.Wrapf(err, "%s", t.Path)
+	}
+	return t.lockedAction("", options.Lock, "snapshot delete", func() error {
+		p, err := t.snapshotter()
+		if err != nil {
+			return err
+		}
+		t.log.Info().Msgf("delete snapshot %s", options.Name)
+		return errors.Wrapf(p.DeleteSnapshot(t.FQDN(), options.Name), "%s", t.Path)
+	})
+}
+
+// SnapshotRollback restores the volume data from a snapshot.
+//
+// The rollback is refused if the volume instance is not down, unless the
+// Force option is set.
+func (t *Vol) SnapshotRollback(options OptsSnapshotRollback) error {
+	if err := pool.ValidateSnapshotName(options.Name); err != nil {
+		return errors.Wrapf(err, "%s", t.Path)
+	}
+	return t.lockedAction("", options.Lock, "snapshot rollback", func() error {
+		p, err := t.snapshotter()
+		if err != nil {
+			return err
+		}
+		if !options.Force {
+			data, err := t.Status(OptsStatus{})
+			if err != nil {
+				return err
+			}
+			if data.Avail != status.Down && data.Avail != status.NotApplicable {
+				return fmt.Errorf("%s: refuse to rollback a volume with avail status %s without --force", t.Path, data.Avail)
+			}
+		}
+		t.log.Info().Msgf("rollback snapshot %s", options.Name)
+		return errors.Wrapf(p.RollbackSnapshot(t.FQDN(), options.Name), "%s", t.Path)
+	})
+}
+
+// SnapshotClone creates the data of the clone volume from a snapshot of
+// this volume. The clone volume must be configured to use the same pool.
+func (t *Vol) SnapshotClone(name string, clone *Vol) error {
+	if err := pool.ValidateSnapshotName(name); err != nil {
+		return errors.Wrapf(err, "%s", t.Path)
+	}
+	p, err := t.snapshotter()
+	if err != nil {
+		return err
+	}
+	if a, b := t.config.GetString(key.Parse("pool")), clone.config.GetString(key.Parse("pool")); a != b {
+		return fmt.Errorf("%s: can not clone to %s: pool %s differs from %s", t.Path, clone.Path, b, a)
+	}
+	t.log.Info().Msgf("clone snapshot %s to %s", name, clone.Path)
+	return errors.Wrapf(p.CloneSnapshot(t.FQDN(), name, clone.FQDN()), "%s", t.Path)
+}
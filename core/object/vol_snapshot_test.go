@@ -0,0 +1,30 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+)
+
+func TestVolSnapshotInvalidNames(t *testing.T) {
+	setTestRoot(t, "10")
+	p, err := path.Parse("ns1/vol/v1")
+	require.NoError(t, err)
+	installTestConfig(t, p, "[DEFAULT]\npool = default\n")
+	o := NewVol(p)
+	clone := NewVol(path.T{Name: "v2", Namespace: "ns1", Kind: p.Kind})
+	for _, name := range []string{"", "../x", "../../v2"} {
+		for _, err := range []error{
+			o.SnapshotCreate(OptsSnapshotCreate{Name: name}),
+			o.SnapshotDelete(OptsSnapshotDelete{Name: name}),
+			o.SnapshotRollback(OptsSnapshotRollback{Name: name, Force: true}),
+			o.SnapshotClone(name, clone),
+		} {
+			if assert.Error(t, err, name) {
+				assert.Contains(t, err.Error(), "invalid snapshot name", name)
+			}
+		}
+	}
+}
@@ -22,6 +22,8 @@ const (
 	Table
 	// CSV is the csv tabular output format
 	CSV
+	// YAML is the yaml output format
+	YAML
 )
 
 var toString = map[T]string{
@@ -31,6 +33,7 @@ var toString = map[T]string{
 	Flat:     "flat",
 	Table:    "table",
 	CSV:      "csv",
+	YAML:     "yaml",
 }
 
 var toID = map[string]T{
@@ -41,6 +44,7 @@ var toID = map[string]T{
 	"flat_json": Flat, // compat
 	"table":     Table,
 	"csv":       CSV,
+	"yaml":      YAML,
 }
 
 func (t T) String() string {
@@ -6,6 +6,7 @@ import (
 	"regexp"
 
 	"github.com/fatih/color"
+	"gopkg.in/yaml.v2"
 	"opensvc.com/opensvc/util/render"
 	"opensvc.com/opensvc/util/render/palette"
 )
@@ -77,6 +78,8 @@ func (t Renderer) Sprint() string {
 	case JSONLine:
 		b, _ := json.Marshal(t.Data)
 		return string(b) + "\n"
+	case YAML:
+		return sprintYAML(t.Data)
 	default:
 		if t.HumanRenderer != nil {
 			return t.HumanRenderer()
@@ -86,6 +89,22 @@ func (t Renderer) Sprint() string {
 	}
 }
 
+// sprintYAML returns the yaml representation of the data. The data is
+// marshaled to json first, so the json marshalers are honored, then
+// converted to yaml, preserving the maps key order.
+func sprintYAML(data interface{}) string {
+	b, _ := json.Marshal(data)
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(b, &doc); err == nil {
+		b, _ = yaml.Marshal(doc)
+		return string(b)
+	}
+	var v interface{}
+	_ = yaml.Unmarshal(b, &v)
+	b, _ = yaml.Marshal(v)
+	return string(b)
+}
+
 //
 // Print prints the representation of the data in one of the
 // supported format (json, flat, human, ...).
@@ -150,6 +150,22 @@ func Parse(s string) (T, error) {
 	return New(name, namespace, kd)
 }
 
+// ParseIn returns a new path struct from a path string representation,
+// the namespace defaulting to <namespace> instead of root when the
+// representation has no namespace part. Ex: vol/v1 in ns1 is ns1/vol/v1
+func ParseIn(s string, namespace string) (T, error) {
+	p, err := Parse(s)
+	if err != nil {
+		return p, err
+	}
+	switch {
+	case p.Kind == kind.Ccfg || p.Kind == kind.Nscfg:
+	case strings.Count(s, Separator) < 2:
+		return New(p.Name, namespace, p.Kind.String())
+	}
+	return p, nil
+}
+
 // MarshalJSON implements the json interface
 func (t T) MarshalJSON() ([]byte, error) {
 	buffer := bytes.NewBufferString(`"`)
@@ -187,6 +187,22 @@ func TestParse(t *testing.T) {
 
 }
 
+func TestParseIn(t *testing.T) {
+	tests := map[string]string{
+		"svc1":        "ns1/svc/svc1",
+		"vol/v1":      "ns1/vol/v1",
+		"ns2/vol/v1":  "ns2/vol/v1",
+		"root/svc/s1": "s1",
+		"cluster":     "cluster",
+		"ns2/":        "ns2/nscfg/namespace",
+	}
+	for s, expected := range tests {
+		p, err := ParseIn(s, "ns1")
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, p.String(), s)
+	}
+}
+
 func TestMarshalJSON(t *testing.T) {
 	path, _ := New("svc1", "ns1", "svc")
 	b, err := json.Marshal(path)
@@ -14,6 +14,7 @@ import (
 type (
 	manager interface {
 		Pools() []Pooler
+		PoolVolumes() (VolumeStatusList, error)
 	}
 	Lookup struct {
 		Name   string
@@ -24,6 +25,10 @@ type (
 		Shared bool
 		Usage  bool
 
+		// Namespace is the namespace of the volume to allocate, used to
+		// enforce the pool namespace quotas.
+		Namespace string
+
 		manager manager
 	}
 	WeightedPools []Pooler
@@ -65,6 +70,14 @@ func (t Lookup) Do() (Pooler, error) {
 	cause := make([]string, 0)
 	l := NewStatusList()
 	m := make(map[string]Pooler)
+	var volumes VolumeStatusList
+	getVolumes := func() (VolumeStatusList, error) {
+		var err error
+		if volumes == nil {
+			volumes, err = t.manager.PoolVolumes()
+		}
+		return volumes, err
+	}
 	for _, p := range t.manager.Pools() {
 		if t.Name != "" && t.Name != p.Name() {
 			cause = append(cause, fmt.Sprintf("[%s] not matching name %s", p.Name(), t.Name))
@@ -90,6 +103,12 @@ func (t Lookup) Do() (Pooler, error) {
 			cause = append(cause, fmt.Sprintf("[%s] not shared capable", p.Name()))
 			continue
 		}
+		if t.Namespace != "" {
+			if err := checkQuota(p, t.Namespace, t.Size, getVolumes); err != nil {
+				cause = append(cause, fmt.Sprintf("[%s] %s", p.Name(), err))
+				continue
+			}
+		}
 		if t.Usage == true {
 			usage, err := p.Usage()
 			if err != nil {
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/anmitsu/go-shlex"
 	"opensvc.com/opensvc/core/path"
 	"opensvc.com/opensvc/core/rawconfig"
 	"opensvc.com/opensvc/core/volaccess"
@@ -46,6 +47,7 @@ type (
 
 	VolumeStatus struct {
 		Path     path.T   `json:"path"`
+		Pool     string   `json:"pool"`
 		Children []path.T `json:"children"`
 		Orphan   bool     `json:"orphan"`
 		// Size unit is B
@@ -183,6 +185,16 @@ func (t *T) GetString(s string) string {
 	return t.Config().GetString(k)
 }
 
+func (t *T) GetSlice(s string) []string {
+	k := key.New("pool#"+t.name, s)
+	return t.Config().GetSlice(k)
+}
+
+func (t *T) GetBool(s string) bool {
+	k := key.New("pool#"+t.name, s)
+	return t.Config().GetBool(k)
+}
+
 func MountPointFromName(name string) string {
 	return filepath.Join(filepath.FromSlash("/srv"), name)
 }
@@ -356,6 +368,60 @@ func (t VolumeStatusList) Swap(i, j int) {
 	t[i], t[j] = t[j], t[i]
 }
 
+// WithVolumes returns a copy of the list with the volumes of each pool
+// set from the l volume status list.
+func (t StatusList) WithVolumes(l VolumeStatusList) StatusList {
+	m := make(map[string][]VolumeStatus)
+	for _, v := range l {
+		m[v.Pool] = append(m[v.Pool], v)
+	}
+	r := make(StatusList, len(t))
+	for i, data := range t {
+		if vols, ok := m[data.Name]; ok {
+			data.Volumes = vols
+		} else {
+			data.Volumes = make([]VolumeStatus, 0)
+		}
+		r[i] = data
+	}
+	return r
+}
+
+// Orphans returns the volumes not referenced by any object.
+func (t VolumeStatusList) Orphans() VolumeStatusList {
+	l := make(VolumeStatusList, 0)
+	for _, v := range t {
+		if v.Orphan {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
+// NamespaceUsage returns the total size in bytes of the volumes allocated
+// in the <poolName> pool for the <namespace> namespace.
+func (t VolumeStatusList) NamespaceUsage(poolName string, namespace string) float64 {
+	var size float64
+	for _, v := range t {
+		if v.Pool != poolName || v.Path.Namespace != namespace {
+			continue
+		}
+		size += v.Size
+	}
+	return size
+}
+
+func (t VolumeStatusList) Render() string {
+	return t.Tree().Render()
+}
+
+// Tree returns a tree loaded with the type instance.
+func (t VolumeStatusList) Tree() *tree.Tree {
+	tree := tree.New()
+	t.LoadTreeNode(tree.Head())
+	return tree
+}
+
 // LoadTreeNode add the tree nodes representing the type instance into another.
 func (t VolumeStatusList) LoadTreeNode(head *tree.Node) {
 	head.AddColumn().AddText("volume").SetColor(rawconfig.Node.Color.Bold)
@@ -412,3 +478,17 @@ func (t Status) HasCapability(s string) bool {
 	return false
 
 }
+
+// ShlexJoin joins the words, quoting those the shlex converter would
+// otherwise split.
+func ShlexJoin(l []string) string {
+	words := make([]string, len(l))
+	for i, s := range l {
+		if w, err := shlex.Split(s, true); err == nil && len(w) == 1 && w[0] == s {
+			words[i] = s
+			continue
+		}
+		words[i] = fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", `'"'"'`))
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,71 @@
+package pool
+
+import (
+	"fmt"
+	"strings"
+
+	"opensvc.com/opensvc/util/sizeconv"
+)
+
+// Quota returns the maximum total size in bytes of the volumes the pool
+// can allocate for the namespace, as defined by the pool quotas keyword.
+// The boolean is false if no quota applies to the namespace.
+func Quota(p Pooler, namespace string) (int64, bool, error) {
+	l := p.Config().GetSlice(pKey(p, "quotas"))
+	q, ok, err := parseQuotas(l, namespace)
+	if err != nil {
+		return 0, false, fmt.Errorf("pool %s quotas: %s", p.Name(), err)
+	}
+	return q, ok, nil
+}
+
+// parseQuotas returns the quota of the namespace from a list of
+// <namespace>=<size> elements. The * namespace matches the namespaces
+// not explicitly listed.
+func parseQuotas(l []string, namespace string) (int64, bool, error) {
+	var (
+		dflt    int64
+		hasDflt bool
+	)
+	for _, e := range l {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return 0, false, fmt.Errorf("invalid element '%s': expected <namespace>=<size>", e)
+		}
+		size, err := sizeconv.FromSize(kv[1])
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid element '%s': %s", e, err)
+		}
+		switch kv[0] {
+		case namespace:
+			return size, true, nil
+		case "*":
+			dflt = size
+			hasDflt = true
+		}
+	}
+	return dflt, hasDflt, nil
+}
+
+// checkQuota returns an error if allocating size bytes in the pool for
+// the namespace would exceed the namespace quota.
+func checkQuota(p Pooler, namespace string, size float64, volumes func() (VolumeStatusList, error)) error {
+	quota, ok, err := Quota(p, namespace)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	l, err := volumes()
+	if err != nil {
+		return err
+	}
+	used := l.NamespaceUsage(p.Name(), namespace)
+
+	if used+size > float64(quota) {
+		return fmt.Errorf("namespace %s quota exceeded: %s used, %s requested, %s quota",
+			namespace, sizeconv.BSize(used), sizeconv.BSize(size), sizeconv.BSize(float64(quota)))
+	}
+	return nil
+}
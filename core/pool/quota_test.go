@@ -0,0 +1,53 @@
+package pool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+)
+
+func TestParseQuotas(t *testing.T) {
+	l := []string{"test=10m", "*=1g"}
+	q, ok, err := parseQuotas(l, "test")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(10*1024*1024), q)
+
+	q, ok, err = parseQuotas(l, "prod")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1024*1024*1024), q)
+
+	_, ok, err = parseQuotas([]string{"test=10m"}, "prod")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	for _, e := range []string{"test", "=10m", "test=foo"} {
+		_, _, err = parseQuotas([]string{e}, "test")
+		assert.Error(t, err, e)
+	}
+}
+
+func TestVolumeStatusList(t *testing.T) {
+	newPath := func(s string) path.T {
+		p, err := path.Parse(s)
+		require.NoError(t, err)
+		return p
+	}
+	l := VolumeStatusList{
+		{Path: newPath("test/vol/v1"), Pool: "p1", Size: 100, Children: []path.T{newPath("test/svc/s1")}},
+		{Path: newPath("test/vol/v2"), Pool: "p1", Size: 10, Orphan: true},
+		{Path: newPath("test/vol/v3"), Pool: "p2", Size: 1},
+		{Path: newPath("prod/vol/v1"), Pool: "p1", Size: 1000},
+	}
+	assert.Equal(t, float64(110), l.NamespaceUsage("p1", "test"))
+	assert.Equal(t, float64(0), l.NamespaceUsage("p2", "prod"))
+	assert.Len(t, l.Orphans(), 1)
+	assert.Equal(t, "test/vol/v2", l.Orphans()[0].Path.String())
+
+	sl := StatusList{{Name: "p1"}, {Name: "p3"}}.WithVolumes(l)
+	assert.Len(t, sl[0].Volumes, 3)
+	assert.Len(t, sl[1].Volumes, 0)
+}
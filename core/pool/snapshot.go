@@ -0,0 +1,99 @@
+package pool
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/render/tree"
+)
+
+type (
+	// Snapshotter is implemented by pool drivers able to snapshot the
+	// volumes they allocated. The volume argument is the name passed to
+	// the pool translators when the volume was configured.
+	Snapshotter interface {
+		CreateSnapshot(volume string, name string) error
+		DeleteSnapshot(volume string, name string) error
+		RollbackSnapshot(volume string, name string) error
+		Snapshots(volume string) (SnapshotList, error)
+
+		// CloneSnapshot creates the data of the clone volume from the
+		// volume snapshot, so the clone volume provisioning finds it
+		// already allocated.
+		CloneSnapshot(volume string, name string, clone string) error
+	}
+
+	// Snapshot describes a volume snapshot.
+	Snapshot struct {
+		Name    string    `json:"name"`
+		Volume  string    `json:"volume"`
+		Created time.Time `json:"created"`
+	}
+	SnapshotList []Snapshot
+)
+
+var (
+	snapshotNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+)
+
+// ValidateSnapshotName returns an error if the snapshot name can not be
+// used by all pool drivers as a lv or file name suffix.
+func ValidateSnapshotName(s string) error {
+	if !snapshotNameRegexp.MatchString(s) {
+		return fmt.Errorf("invalid snapshot name '%s': allowed characters are a-z, A-Z, 0-9, _ and -, and the first character must be alphanumeric", s)
+	}
+	return nil
+}
+
+func (t SnapshotList) Len() int {
+	return len(t)
+}
+
+func (t SnapshotList) Less(i, j int) bool {
+	return t[i].Created.Before(t[j].Created)
+}
+
+func (t SnapshotList) Swap(i, j int) {
+	t[i], t[j] = t[j], t[i]
+}
+
+// Has returns true if the list has a snapshot named s.
+func (t SnapshotList) Has(s string) bool {
+	for _, e := range t {
+		if e.Name == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (t SnapshotList) Render() string {
+	return t.Tree().Render()
+}
+
+// Tree returns a tree loaded with the type instance.
+func (t SnapshotList) Tree() *tree.Tree {
+	tree := tree.New()
+	t.LoadTreeNode(tree.Head())
+	return tree
+}
+
+// LoadTreeNode add the tree nodes representing the type instance into another.
+func (t SnapshotList) LoadTreeNode(head *tree.Node) {
+	head.AddColumn().AddText("name").SetColor(rawconfig.Node.Color.Bold)
+	head.AddColumn().AddText("created").SetColor(rawconfig.Node.Color.Bold)
+	sort.Sort(t)
+	for _, data := range t {
+		n := head.AddNode()
+		data.LoadTreeNode(n)
+	}
+}
+
+// LoadTreeNode add the tree nodes representing the type instance into another.
+func (t Snapshot) LoadTreeNode(head *tree.Node) {
+	head.AddColumn().AddText(t.Name).SetColor(rawconfig.Node.Color.Primary)
+	head.AddColumn().AddText(t.Created.Format(time.RFC3339))
+}
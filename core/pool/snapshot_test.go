@@ -0,0 +1,16 @@
+package pool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSnapshotName(t *testing.T) {
+	for _, s := range []string{"s1", "daily-2021_01", "A"} {
+		assert.NoError(t, ValidateSnapshotName(s), s)
+	}
+	for _, s := range []string{"", "-s1", "_s1", "s.1", "s/1", "s 1"} {
+		assert.Error(t, ValidateSnapshotName(s), s)
+	}
+}
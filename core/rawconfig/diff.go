@@ -0,0 +1,127 @@
+package rawconfig
+
+import (
+	"fmt"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+type (
+	// Change is a key difference between two configurations.
+	Change struct {
+		Section string   `json:"section"`
+		Option  string   `json:"option"`
+		Op      ChangeOp `json:"op"`
+		From    string   `json:"from,omitempty"`
+		To      string   `json:"to,omitempty"`
+	}
+
+	// Changes is the list of key differences between two configurations,
+	// returned by Diff.
+	Changes []Change
+
+	// ChangeOp is the kind of a Change.
+	ChangeOp string
+)
+
+const (
+	ChangeAdd    ChangeOp = "add"
+	ChangeDelete ChangeOp = "delete"
+	ChangeModify ChangeOp = "modify"
+)
+
+// Diff returns the key differences between the a and b configurations,
+// ordered as the sections and keys of b, the deleted sections and keys
+// ordered as in a.
+func Diff(a, b T) Changes {
+	l := make(Changes, 0)
+	for _, section := range sectionNames(b, a) {
+		am := sectionMap(a, section)
+		bm := sectionMap(b, section)
+		for _, option := range optionNames(bm, am) {
+			av, inA := am.Get(option)
+			bv, inB := bm.Get(option)
+			as, bs := fmt.Sprint(av), fmt.Sprint(bv)
+			switch {
+			case inA && !inB:
+				l = append(l, Change{Section: section, Option: option, Op: ChangeDelete, From: as})
+			case !inA && inB:
+				l = append(l, Change{Section: section, Option: option, Op: ChangeAdd, To: bs})
+			case as != bs:
+				l = append(l, Change{Section: section, Option: option, Op: ChangeModify, From: as, To: bs})
+			}
+		}
+	}
+	return l
+}
+
+func sectionMap(t T, section string) orderedmap.OrderedMap {
+	if t.Data == nil {
+		return *orderedmap.New()
+	}
+	if i, ok := t.Data.Get(section); ok {
+		if m, ok := i.(orderedmap.OrderedMap); ok {
+			return m
+		}
+	}
+	return *orderedmap.New()
+}
+
+func sectionNames(a, b T) []string {
+	l := make([]string, 0)
+	seen := make(map[string]interface{})
+	for _, t := range []T{a, b} {
+		if t.Data == nil {
+			continue
+		}
+		for _, s := range t.Data.Keys() {
+			if _, ok := seen[s]; ok || s == "metadata" {
+				continue
+			}
+			seen[s] = nil
+			l = append(l, s)
+		}
+	}
+	return l
+}
+
+func optionNames(a, b orderedmap.OrderedMap) []string {
+	l := make([]string, 0)
+	seen := make(map[string]interface{})
+	for _, m := range []orderedmap.OrderedMap{a, b} {
+		for _, s := range m.Keys() {
+			if _, ok := seen[s]; ok {
+				continue
+			}
+			seen[s] = nil
+			l = append(l, s)
+		}
+	}
+	return l
+}
+
+// Render returns a colorized text version of the changes, grouped by
+// section.
+func (t Changes) Render() string {
+	s := ""
+	section := ""
+	for _, c := range t {
+		if c.Section != section {
+			if section != "" {
+				s += "\n"
+			}
+			section = c.Section
+			s += Node.Colorize.Primary(fmt.Sprintf("[%s]\n", section))
+		}
+		switch c.Op {
+		case ChangeAdd:
+			s += Node.Colorize.Optimal(fmt.Sprintf("+ %s = %s", c.Option, c.To)) + "\n"
+		case ChangeDelete:
+			s += Node.Colorize.Error(fmt.Sprintf("- %s = %s", c.Option, c.From)) + "\n"
+		case ChangeModify:
+			s += Node.Colorize.Error(fmt.Sprintf("- %s = %s", c.Option, c.From)) + "\n"
+			s += Node.Colorize.Optimal(fmt.Sprintf("+ %s = %s", c.Option, c.To)) + "\n"
+		}
+	}
+	return s
+}
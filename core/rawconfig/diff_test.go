@@ -0,0 +1,27 @@
+package rawconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	load := func(s string) T {
+		var c T
+		require.NoError(t, json.Unmarshal([]byte(s), &c))
+		return c
+	}
+	a := load(`{"DEFAULT": {"id": "1", "nodes": "n1"}, "app#1": {"start": "/bin/false"}, "fs#1": {"type": "flag"}}`)
+	b := load(`{"DEFAULT": {"id": "1", "nodes": "n1 n2"}, "app#1": {"start": "/bin/true", "timeout": "1m"}, "ip#1": {"type": "host"}}`)
+	assert.Equal(t, Changes{
+		{Section: "DEFAULT", Option: "nodes", Op: ChangeModify, From: "n1", To: "n1 n2"},
+		{Section: "app#1", Option: "start", Op: ChangeModify, From: "/bin/false", To: "/bin/true"},
+		{Section: "app#1", Option: "timeout", Op: ChangeAdd, To: "1m"},
+		{Section: "ip#1", Option: "type", Op: ChangeAdd, To: "host"},
+		{Section: "fs#1", Option: "type", Op: ChangeDelete, From: "flag"},
+	}, Diff(a, b))
+	assert.Equal(t, Changes{}, Diff(a, a))
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"opensvc.com/opensvc/util/hostname"
@@ -37,10 +38,12 @@ type (
 	}
 
 	clusterSection struct {
-		Name       string `mapstructure:"name"`
-		Secret     string `mapstructure:"secret"`
-		CASecPaths string `mapstructure:"ca"`
-		Nodes      string `mapstructure:"nodes"`
+		Name                string `mapstructure:"name"`
+		Secret              string `mapstructure:"secret"`
+		PreviousSecret      string `mapstructure:"previous_secret"`
+		PreviousSecretUntil string `mapstructure:"previous_secret_until"`
+		CASecPaths          string `mapstructure:"ca"`
+		Nodes               string `mapstructure:"nodes"`
 	}
 
 	nodeSection struct {
@@ -49,6 +52,21 @@ type (
 	}
 )
 
+// Secrets returns the cluster secrets accepted to decrypt data: the
+// current secret first, then the previous secret if its rotation grace
+// period is not expired.
+func (t clusterSection) Secrets() []string {
+	l := []string{t.Secret}
+	if t.PreviousSecret == "" || t.PreviousSecret == t.Secret {
+		return l
+	}
+	until, err := time.Parse(time.RFC3339, t.PreviousSecretUntil)
+	if err != nil || time.Now().After(until) {
+		return l
+	}
+	return append(l, t.PreviousSecret)
+}
+
 func setDefaults(root string) {
 	NodeViper.SetDefault("hostname", hostname.Hostname())
 	if root == defPathRoot {
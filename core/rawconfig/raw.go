@@ -14,12 +14,17 @@ import (
 type (
 	T struct {
 		Data *orderedmap.OrderedMap
+
+		// Sources is the path of the object setting the key, indexed
+		// by <section>.<option>, for the keys inherited from a template
+		// object. Sources is not serialized.
+		Sources map[string]string
 	}
 )
 
 var (
 	RegexpScope     = regexp.MustCompile(`(@[\w.-_]+)`)
-	RegexpReference = regexp.MustCompile(`({[\w#.-_:]+})`)
+	RegexpReference = regexp.MustCompile(`({[-\w#.-_:]+})`)
 )
 
 // MarshalJSON marshals the enum as a quoted json string
@@ -60,6 +65,10 @@ func (t T) Render() string {
 				s += renderComment(k, v)
 				continue
 			}
+			if src, ok := t.Sources[section+"."+k]; ok {
+				s += renderInheritedKey(k, v, src)
+				continue
+			}
 			s += renderKey(k, v)
 		}
 		s += "\n"
@@ -75,6 +84,11 @@ func renderComment(k string, v interface{}) string {
 	return "# " + strings.ReplaceAll(vs, "\n", "\n# ") + "\n"
 }
 
+func renderInheritedKey(k string, v interface{}, src string) string {
+	s := renderKey(k, v)
+	return s[:len(s)-1] + Node.Colorize.Secondary(" # from "+src) + "\n"
+}
+
 func renderKey(k string, v interface{}) string {
 	k = RegexpScope.ReplaceAllString(k, Node.Colorize.Error("$1"))
 	var vs string
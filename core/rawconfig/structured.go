@@ -0,0 +1,129 @@
+package rawconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	// ErrStructured is returned when a json or yaml document is not a
+	// valid structured configuration.
+	ErrStructured = errors.New("invalid structured configuration")
+)
+
+// ParseStructured returns the configuration described by a json or yaml
+// document, where sections are maps of options. The option values can be
+// strings, numbers, booleans or lists, and are converted to their string
+// representation in an ini configuration file.
+func ParseStructured(b []byte) (T, error) {
+	t := T{Data: orderedmap.New()}
+	if json.Valid(b) {
+		if err := json.Unmarshal(b, t.Data); err != nil {
+			return T{}, errors.Wrapf(ErrStructured, "%s", err)
+		}
+		return t.Normalize()
+	}
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return T{}, errors.Wrapf(ErrStructured, "%s", err)
+	}
+	for _, section := range doc {
+		name, ok := section.Key.(string)
+		if !ok {
+			return T{}, errors.Wrapf(ErrStructured, "section name %v is not a string", section.Key)
+		}
+		options, ok := section.Value.(yaml.MapSlice)
+		if !ok && section.Value != nil {
+			return T{}, errors.Wrapf(ErrStructured, "section %s is not a map", name)
+		}
+		m := orderedmap.New()
+		for _, option := range options {
+			m.Set(fmt.Sprint(option.Key), option.Value)
+		}
+		t.Data.Set(name, *m)
+	}
+	return t.Normalize()
+}
+
+// Normalize returns a copy of the configuration with the option values
+// converted to their string representation in an ini configuration file.
+// The list elements are joined by spaces, the elements containing spaces
+// being quoted.
+func (t T) Normalize() (T, error) {
+	r := T{Data: orderedmap.New()}
+	if t.Data == nil {
+		return r, nil
+	}
+	for _, section := range t.Data.Keys() {
+		i, _ := t.Data.Get(section)
+		var options orderedmap.OrderedMap
+		switch v := i.(type) {
+		case orderedmap.OrderedMap:
+			options = v
+		case nil:
+			options = *orderedmap.New()
+		default:
+			return T{}, errors.Wrapf(ErrStructured, "section %s is not a map", section)
+		}
+		m := orderedmap.New()
+		for _, option := range options.Keys() {
+			v, _ := options.Get(option)
+			s, err := stringValue(v)
+			if err != nil {
+				return T{}, errors.Wrapf(ErrStructured, "%s.%s: %s", section, option, err)
+			}
+			m.Set(option, s)
+		}
+		r.Data.Set(section, *m)
+	}
+	return r, nil
+}
+
+func stringValue(i interface{}) (string, error) {
+	switch v := i.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []interface{}:
+		l := make([]string, len(v))
+		for j, e := range v {
+			s, err := stringValue(e)
+			if err != nil {
+				return "", err
+			}
+			if _, ok := e.([]interface{}); ok {
+				return "", fmt.Errorf("nested lists are not supported")
+			}
+			if strings.ContainsAny(s, " \t") {
+				s = strconv.Quote(s)
+			}
+			l[j] = s
+		}
+		return strings.Join(l, " "), nil
+	case []string:
+		l := make([]interface{}, len(v))
+		for j, e := range v {
+			l[j] = e
+		}
+		return stringValue(l)
+	default:
+		return "", fmt.Errorf("unsupported value type %T", i)
+	}
+}
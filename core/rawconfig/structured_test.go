@@ -0,0 +1,57 @@
+package rawconfig
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStructured(t *testing.T) {
+	get := func(c T, section, option string) string {
+		m := sectionMap(c, section)
+		v, ok := m.Get(option)
+		require.True(t, ok, "option %s.%s", section, option)
+		return v.(string)
+	}
+	docs := map[string]string{
+		"json": `{"DEFAULT": {"nodes": ["n1", "n2"], "priority": 10, "nodes@n1": "n1"}, "app#1": {"start": ["/bin/sh", "-c", "echo a b"], "disable": false}}`,
+		"yaml": `
+DEFAULT:
+  nodes: [n1, n2]
+  priority: 10
+  nodes@n1: n1
+app#1:
+  start: [/bin/sh, -c, echo a b]
+  disable: false
+`,
+	}
+	for name, doc := range docs {
+		t.Run(name, func(t *testing.T) {
+			c, err := ParseStructured([]byte(doc))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"DEFAULT", "app#1"}, c.Data.Keys())
+			assert.Equal(t, "n1 n2", get(c, "DEFAULT", "nodes"))
+			assert.Equal(t, "10", get(c, "DEFAULT", "priority"))
+			assert.Equal(t, "n1", get(c, "DEFAULT", "nodes@n1"))
+			assert.Equal(t, `/bin/sh -c "echo a b"`, get(c, "app#1", "start"))
+			assert.Equal(t, "false", get(c, "app#1", "disable"))
+		})
+	}
+}
+
+func TestParseStructuredErrors(t *testing.T) {
+	for name, doc := range map[string]string{
+		"not a map":      `DEFAULT: [a, b]`,
+		"nested list":    `{"DEFAULT": {"nodes": [["n1"]]}}`,
+		"nested map":     `{"DEFAULT": {"nodes": {"n1": "x"}}}`,
+		"invalid syntax": `DEFAULT: [a,`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseStructured([]byte(doc))
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrStructured))
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// Package rbac implements the api access control based on the grants of
+// the usr objects or bearer tokens.
+//
+// A grant is a <role>[:<namespace>] string. The admin, operator and guest
+// roles are namespaced, the other roles are cluster-wide:
+//
+//   - root
+//     all permissions
+//   - squatter
+//     create objects in namespaces not yet used, and become admin of these
+//   - admin:<ns>
+//     create, delete and configure objects in <ns>, read their secrets
+//   - operator:<ns>
+//     run actions on objects in <ns>
+//   - guest:<ns>
+//     read the status and configuration of objects in <ns>
+//   - prioritizer
+//     change the priority of objects
+//   - blacklistadmin
+//     clear the blacklist of the listeners
+//
+// A namespaced role implies the lesser namespaced roles on the same
+// namespace.
+//
+// The routes not targeting an object return cluster-wide data, not
+// filtered by namespace, so they require the root role or a cluster-wide
+// role: a namespaced grant does not give access to them.
+package rbac
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// Role is the name of a set of permissions.
+	Role string
+
+	// Grant is a role, applying to Namespace if the role is namespaced.
+	Grant struct {
+		Role      Role
+		Namespace string
+	}
+
+	// Grants is a list of Grant.
+	Grants []Grant
+
+	// Access is the grant required to use a route. If Namespaced is true,
+	// the Role is required on the namespace of the request. Otherwise a
+	// namespaced Role is not enough, and the root role is required.
+	Access struct {
+		Role       Role
+		Namespaced bool
+	}
+
+	// Request is the subject of an authorization decision.
+	Request struct {
+		// Route is the "<method> <action>" api route, like "GET object_status".
+		Route string
+
+		// Namespace is the namespace of the object targeted by the request.
+		Namespace string
+
+		// NewNamespace is true if Namespace has no object yet.
+		NewNamespace bool
+	}
+)
+
+const (
+	RoleRoot           Role = "root"
+	RoleSquatter       Role = "squatter"
+	RoleAdmin          Role = "admin"
+	RoleOperator       Role = "operator"
+	RoleGuest          Role = "guest"
+	RolePrioritizer    Role = "prioritizer"
+	RoleBlacklistAdmin Role = "blacklistadmin"
+)
+
+var (
+	// ErrForbidden is returned when the grants don't allow a request.
+	ErrForbidden = errors.New("forbidden")
+
+	// namespacedRoleLevel orders the namespaced roles. A role implies the
+	// roles of lower level.
+	namespacedRoleLevel = map[Role]int{
+		RoleGuest:    1,
+		RoleOperator: 2,
+		RoleAdmin:    3,
+	}
+
+	globalRoles = map[Role]bool{
+		RoleRoot:           true,
+		RoleSquatter:       true,
+		RolePrioritizer:    true,
+		RoleBlacklistAdmin: true,
+	}
+
+	// Routes maps the api routes to the grant required to use them.
+	// The routes not listed require the root role.
+	Routes = map[string]Access{
+		"GET daemon_stats":     {Role: RoleRoot},
+		"GET daemon_status":    {Role: RoleRoot},
+		"GET events":           {Role: RoleRoot},
+		"GET nodes_info":       {Role: RoleRoot},
+		"GET object_selector":  {Role: RoleRoot},
+		"GET pools":            {Role: RoleRoot},
+		"GET schedules":        {Role: RoleRoot},
+		"GET object_config":    {Role: RoleGuest, Namespaced: true},
+		"GET object_status":    {Role: RoleGuest, Namespaced: true},
+		"GET key":              {Role: RoleAdmin, Namespaced: true},
+		"POST key":             {Role: RoleAdmin, Namespaced: true},
+		"POST object_action":   {Role: RoleOperator, Namespaced: true},
+		"POST object_monitor":  {Role: RoleOperator, Namespaced: true},
+		"POST object_create":   {Role: RoleAdmin, Namespaced: true},
+		"POST object_priority": {Role: RolePrioritizer},
+		"POST blacklist_clear": {Role: RoleBlacklistAdmin},
+		"POST node_action":     {Role: RoleRoot},
+		"POST node_monitor":    {Role: RoleRoot},
+		"POST object_status":   {Role: RoleRoot},
+	}
+)
+
+// IsNamespaced returns true if the role applies to a namespace.
+func (t Role) IsNamespaced() bool {
+	_, ok := namespacedRoleLevel[t]
+	return ok
+}
+
+// IsValid returns true if the role is known.
+func (t Role) IsValid() bool {
+	return t.IsNamespaced() || globalRoles[t]
+}
+
+// ParseGrant parses a <role>[:<namespace>] string.
+func ParseGrant(s string) (Grant, error) {
+	l := strings.SplitN(s, ":", 2)
+	g := Grant{Role: Role(l[0])}
+	if len(l) == 2 {
+		g.Namespace = l[1]
+	}
+	switch {
+	case !g.Role.IsValid():
+		return g, fmt.Errorf("invalid grant %s: unknown role %s", s, g.Role)
+	case g.Role.IsNamespaced() && g.Namespace == "":
+		return g, fmt.Errorf("invalid grant %s: the %s role requires a namespace", s, g.Role)
+	case !g.Role.IsNamespaced() && g.Namespace != "":
+		return g, fmt.Errorf("invalid grant %s: the %s role is not namespaced", s, g.Role)
+	}
+	return g, nil
+}
+
+// ParseGrants parses a list of <role>[:<namespace>] strings.
+func ParseGrants(l []string) (Grants, error) {
+	grants := make(Grants, 0)
+	for _, s := range l {
+		g, err := ParseGrant(s)
+		if err != nil {
+			return grants, err
+		}
+		grants = append(grants, g)
+	}
+	return grants, nil
+}
+
+func (t Grant) String() string {
+	if t.Namespace == "" {
+		return string(t.Role)
+	}
+	return string(t.Role) + ":" + t.Namespace
+}
+
+// Strings returns the sorted string representations of the grants.
+func (t Grants) Strings() []string {
+	l := make([]string, len(t))
+	for i, g := range t {
+		l[i] = g.String()
+	}
+	sort.Strings(l)
+	return l
+}
+
+// HasRole returns true if the grants include the role, or a role implying
+// it, on the namespace. The namespace is ignored for cluster-wide roles.
+func (t Grants) HasRole(role Role, namespace string) bool {
+	level, namespaced := namespacedRoleLevel[role]
+	for _, g := range t {
+		switch {
+		case g.Role == RoleRoot:
+			return true
+		case !namespaced:
+			if g.Role == role {
+				return true
+			}
+		case g.Namespace == namespace && namespacedRoleLevel[g.Role] >= level:
+			return true
+		}
+	}
+	return false
+}
+
+// Namespaces returns the namespaces where the grants include the role,
+// or a role implying it.
+func (t Grants) Namespaces(role Role) []string {
+	level := namespacedRoleLevel[role]
+	m := make(map[string]bool)
+	for _, g := range t {
+		if g.Namespace != "" && namespacedRoleLevel[g.Role] >= level {
+			m[g.Namespace] = true
+		}
+	}
+	l := make([]string, 0, len(m))
+	for ns := range m {
+		l = append(l, ns)
+	}
+	sort.Strings(l)
+	return l
+}
+
+// Authorize returns nil if the grants allow the request, or an error
+// wrapping ErrForbidden.
+func (t Grants) Authorize(req Request) error {
+	access, ok := Routes[req.Route]
+	if !ok {
+		access = Access{Role: RoleRoot}
+	}
+	if access.Namespaced {
+		if t.HasRole(access.Role, req.Namespace) {
+			return nil
+		}
+		if req.NewNamespace && access.Role == RoleAdmin && t.HasRole(RoleSquatter, "") {
+			return nil
+		}
+		return errors.Wrapf(ErrForbidden, "%s requires the %s:%s grant", req.Route, access.Role, req.Namespace)
+	}
+	if access.Role.IsNamespaced() {
+		// a namespaced grant can't allow a cluster-wide response
+		access.Role = RoleRoot
+	}
+	if t.HasRole(access.Role, "") {
+		return nil
+	}
+	return errors.Wrapf(ErrForbidden, "%s requires the %s grant", req.Route, access.Role)
+}
@@ -0,0 +1,102 @@
+package rbac
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGrants(t *testing.T) {
+	grants, err := ParseGrants([]string{"admin:ns1", "guest:ns2", "squatter"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin:ns1", "guest:ns2", "squatter"}, grants.Strings())
+
+	for _, s := range []string{"admin", "root:ns1", "superuser", "operator:"} {
+		_, err := ParseGrant(s)
+		assert.Errorf(t, err, "%s", s)
+	}
+}
+
+func TestHasRole(t *testing.T) {
+	grants, _ := ParseGrants([]string{"admin:ns1", "guest:ns2", "prioritizer"})
+	assert.True(t, grants.HasRole(RoleOperator, "ns1"), "admin implies operator")
+	assert.True(t, grants.HasRole(RoleGuest, "ns1"), "admin implies guest")
+	assert.True(t, grants.HasRole(RoleGuest, "ns2"))
+	assert.False(t, grants.HasRole(RoleOperator, "ns2"))
+	assert.False(t, grants.HasRole(RoleGuest, "ns3"))
+	assert.True(t, grants.HasRole(RolePrioritizer, ""))
+	assert.False(t, grants.HasRole(RoleBlacklistAdmin, ""))
+	assert.Equal(t, []string{"ns1", "ns2"}, grants.Namespaces(RoleGuest))
+	assert.Equal(t, []string{"ns1"}, grants.Namespaces(RoleOperator))
+
+	root, _ := ParseGrants([]string{"root"})
+	assert.True(t, root.HasRole(RoleAdmin, "ns3"))
+	assert.True(t, root.HasRole(RoleBlacklistAdmin, ""))
+}
+
+func TestAuthorize(t *testing.T) {
+	grants, _ := ParseGrants([]string{"operator:ns1", "squatter"})
+	cases := []struct {
+		req     Request
+		allowed bool
+	}{
+		{Request{Route: "POST object_action", Namespace: "ns1"}, true},
+		{Request{Route: "POST object_action", Namespace: "ns2"}, false},
+		{Request{Route: "GET object_status", Namespace: "ns1"}, true},
+		{Request{Route: "GET key", Namespace: "ns1"}, false},
+		{Request{Route: "POST object_create", Namespace: "ns1"}, false},
+		{Request{Route: "POST object_create", Namespace: "ns9", NewNamespace: true}, true},
+		{Request{Route: "GET daemon_status"}, false},
+		{Request{Route: "GET events"}, false},
+		{Request{Route: "POST node_action"}, false},
+		{Request{Route: "POST blacklist_clear"}, false},
+		{Request{Route: "GET unknown"}, false},
+	}
+	for _, c := range cases {
+		err := grants.Authorize(c.req)
+		if c.allowed {
+			assert.NoError(t, err, c.req.Route, c.req.Namespace)
+		} else {
+			assert.True(t, errors.Is(err, ErrForbidden), "%s %s: %v", c.req.Route, c.req.Namespace, err)
+		}
+	}
+
+	t.Run("cluster-wide routes", func(t *testing.T) {
+		root, _ := ParseGrants([]string{"root"})
+		assert.NoError(t, root.Authorize(Request{Route: "GET daemon_status"}))
+		prioritizer, _ := ParseGrants([]string{"prioritizer", "admin:ns1"})
+		assert.NoError(t, prioritizer.Authorize(Request{Route: "POST object_priority"}))
+		assert.Error(t, prioritizer.Authorize(Request{Route: "GET pools"}))
+		t.Run("namespaced role on a route not namespaced", func(t *testing.T) {
+			saved := Routes["GET pools"]
+			defer func() { Routes["GET pools"] = saved }()
+			Routes["GET pools"] = Access{Role: RoleGuest}
+			guest, _ := ParseGrants([]string{"guest:ns1"})
+			assert.True(t, errors.Is(guest.Authorize(Request{Route: "GET pools"}), ErrForbidden))
+		})
+	})
+}
+
+func TestToken(t *testing.T) {
+	grants, _ := ParseGrants([]string{"admin:ns1"})
+	s, expire, err := NewToken("u1", "c1", grants, time.Hour, "secret1")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expire, time.Second)
+
+	subject, parsed, err := ParseToken(s, []string{"secret2", "secret1"})
+	require.NoError(t, err, "verified with the previous secret")
+	assert.Equal(t, "u1", subject)
+	assert.Equal(t, grants, parsed)
+
+	_, _, err = ParseToken(s, []string{"secret2"})
+	assert.True(t, errors.Is(err, ErrInvalidToken))
+
+	s, _, err = NewToken("u1", "c1", grants, time.Nanosecond, "secret1")
+	require.NoError(t, err)
+	time.Sleep(1100 * time.Millisecond)
+	_, _, err = ParseToken(s, []string{"secret1"})
+	assert.True(t, errors.Is(err, ErrInvalidToken), "expired")
+}
@@ -0,0 +1,85 @@
+package rbac
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/pkg/errors"
+)
+
+type (
+	// Claims is the payload of the tokens issued for usr objects.
+	Claims struct {
+		jwt.StandardClaims
+		Grant []string `json:"grant"`
+	}
+)
+
+var (
+	// ErrInvalidToken is returned when a token signature, format or
+	// validity period is not valid.
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// signingKey derives the HMAC key signing the tokens from a cluster secret.
+func signingKey(secret string) []byte {
+	sum := sha256.Sum256([]byte("opensvc-token-v1" + secret))
+	return sum[:]
+}
+
+// NewToken returns a HS256 signed JWT for the subject, embedding the
+// grants, and valid for the duration. The issuer is the cluster name.
+func NewToken(subject, issuer string, grants Grants, duration time.Duration, secret string) (string, time.Time, error) {
+	if duration <= 0 {
+		return "", time.Time{}, fmt.Errorf("invalid token duration %s", duration)
+	}
+	now := time.Now()
+	expire := now.Add(duration)
+	claims := Claims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   subject,
+			Issuer:    issuer,
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: expire.Unix(),
+		},
+		Grant: grants.Strings(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	s, err := token.SignedString(signingKey(secret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return s, expire, nil
+}
+
+// ParseToken verifies the token signature with the cluster secrets, in
+// order, and its validity period. It returns the token subject and grants.
+func ParseToken(s string, secrets []string) (string, Grants, error) {
+	var lastErr error
+	for _, secret := range secrets {
+		key := signingKey(secret)
+		claims := Claims{}
+		_, err := jwt.ParseWithClaims(s, &claims, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method %s", token.Header["alg"])
+			}
+			return key, nil
+		})
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		grants, err := ParseGrants(claims.Grant)
+		if err != nil {
+			return "", nil, errors.Wrapf(ErrInvalidToken, "%s", err)
+		}
+		return claims.Subject, grants, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no secret")
+	}
+	return "", nil, errors.Wrapf(ErrInvalidToken, "%s", lastErr)
+}
@@ -80,6 +80,20 @@ type (
 		Abort(ctx context.Context) bool
 	}
 
+	// DataRefresher is implemented by resources installing data from
+	// other objects, like the cfg and sec keys projected in volumes.
+	// RefreshData returns true if the installed data changed.
+	DataRefresher interface {
+		RefreshData() (bool, error)
+	}
+
+	// Resizer is implemented by resources whose device or filesystem
+	// can be resized to a size in bytes. The caller is responsible for
+	// refusing unwanted shrinks.
+	Resizer interface {
+		Resize(ctx context.Context, size int64) error
+	}
+
 	// T is the resource type, embedded in each drivers type
 	T struct {
 		Driver
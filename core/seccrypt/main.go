@@ -0,0 +1,145 @@
+// Package seccrypt implements the encryption at rest of the sec objects
+// key values.
+//
+// Encrypted values are formatted as:
+//
+//	gcm1:<kid>:<base64url(nonce+ciphertext)>
+//
+// where gcm1 is the format version, and kid identifies the cluster
+// secret the AES-256-GCM key is derived from. The key and the key id are
+// derived from the secret with HKDF-SHA256, using distinct context labels. The key id allows to pick
+// the right secret among the current and previous cluster secrets during
+// a secret rotation grace period, and to detect the values that need
+// re-encrypting with the current secret.
+package seccrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// Prefix is the leading string of the values encrypted by this package.
+	Prefix = "gcm1:"
+
+	keyContext   = "opensvc-sec-v1 aes-256-gcm key"
+	keyIDContext = "opensvc-sec-v1 key id"
+)
+
+var (
+	// ErrNoKey is returned by Decrypt when no secret matches the value key id.
+	ErrNoKey = errors.New("no cluster secret matches the value key id")
+)
+
+// derive returns n bytes derived from the secret for the context label.
+func derive(secret, context string, n int) []byte {
+	b := make([]byte, n)
+	r := hkdf.New(sha256.New, []byte(secret), nil, []byte(context))
+	if _, err := io.ReadFull(r, b); err != nil {
+		// hkdf can output up to 255 hashes, way more than requested
+		panic(err)
+	}
+	return b
+}
+
+func deriveKey(secret string) []byte {
+	return derive(secret, keyContext, 32)
+}
+
+// KeyID returns the identifier of the key derived from the secret.
+func KeyID(secret string) string {
+	return hex.EncodeToString(derive(secret, keyIDContext, 4))
+}
+
+// IsEncrypted returns true if the value is formatted by this package.
+func IsEncrypted(s string) bool {
+	return strings.HasPrefix(s, Prefix)
+}
+
+// ValueKeyID returns the key id embedded in an encrypted value.
+func ValueKeyID(s string) (string, error) {
+	kid, _, err := split(s)
+	return kid, err
+}
+
+func split(s string) (string, string, error) {
+	if !IsEncrypted(s) {
+		return "", "", fmt.Errorf("unsupported value (no %s prefix)", Prefix)
+	}
+	l := strings.SplitN(s[len(Prefix):], ":", 2)
+	if len(l) != 2 || l[0] == "" {
+		return "", "", fmt.Errorf("malformed value (no key id)")
+	}
+	return l[0], l[1], nil
+}
+
+// Encrypt encrypts b with the key derived from secret.
+func Encrypt(b []byte, secret string) (string, error) {
+	gcm, err := newGCM(secret)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	data := gcm.Seal(nonce, nonce, b, nil)
+	return Prefix + KeyID(secret) + ":" + base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Decrypt decrypts s with the key derived from the secret matching the
+// value key id, among secrets.
+func Decrypt(s string, secrets []string) ([]byte, error) {
+	kid, payload, err := split(s)
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range secrets {
+		if secret == "" || KeyID(secret) != kid {
+			continue
+		}
+		return decrypt(payload, secret)
+	}
+	return nil, errors.Wrapf(ErrNoKey, "key id %s", kid)
+}
+
+func decrypt(payload string, secret string) ([]byte, error) {
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(secret)
+	if err != nil {
+		return nil, err
+	}
+	n := gcm.NonceSize()
+	if len(data) < n {
+		return nil, fmt.Errorf("malformed value (too short)")
+	}
+	b, err := gcm.Open(nil, data[:n], data[n:], nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypt")
+	}
+	return b, nil
+}
+
+func newGCM(secret string) (cipher.AEAD, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("empty cluster secret")
+	}
+	block, err := aes.NewCipher(deriveKey(secret))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,62 @@
+package seccrypt
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	s, err := Encrypt([]byte("foo\x00bar"), "s1")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(s, Prefix+KeyID("s1")+":"))
+	b, err := Decrypt(s, []string{"s1"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("foo\x00bar"), b)
+}
+
+func TestDecryptKeySelection(t *testing.T) {
+	s, err := Encrypt([]byte("foo"), "old")
+	require.NoError(t, err)
+
+	t.Run("previous secret in grace period", func(t *testing.T) {
+		b, err := Decrypt(s, []string{"new", "old"})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("foo"), b)
+	})
+	t.Run("previous secret expired", func(t *testing.T) {
+		_, err := Decrypt(s, []string{"new"})
+		assert.Error(t, err)
+	})
+}
+
+func TestDecryptTampered(t *testing.T) {
+	s, err := Encrypt([]byte("foo"), "s1")
+	require.NoError(t, err)
+	kid, err := ValueKeyID(s)
+	require.NoError(t, err)
+	assert.Equal(t, KeyID("s1"), kid)
+	tampered := s[:len(s)-2] + "AA"
+	if tampered == s {
+		tampered = s[:len(s)-2] + "BB"
+	}
+	_, err = Decrypt(tampered, []string{"s1"})
+	assert.Error(t, err)
+}
+
+func TestDecryptMalformed(t *testing.T) {
+	for _, s := range []string{"crypt:abc", "gcm1:", "gcm1:abc", "gcm1:" + KeyID("s1") + ":!!"} {
+		_, err := Decrypt(s, []string{"s1"})
+		assert.Error(t, err, s)
+	}
+}
+
+func TestDerive(t *testing.T) {
+	// HKDF-SHA256, no salt, RFC 5869
+	assert.Equal(t, "eaa46920", KeyID("s1"))
+	assert.Equal(t, "dddf81ca9b88f925356c2a98da7733af02eea2522e9ac43d21c6b4e5a6f62b8f", hex.EncodeToString(deriveKey("s1")))
+	assert.NotEqual(t, KeyID("s1"), KeyID("s2"))
+}
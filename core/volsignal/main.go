@@ -1,6 +1,7 @@
 package volsignal
 
 import (
+	"strconv"
 	"strings"
 	"syscall"
 
@@ -8,22 +9,24 @@ import (
 )
 
 type (
+	// T maps signals to the list of resource ids to send them to.
+	// An empty list means all candidate resources.
 	T map[syscall.Signal][]string
 )
 
+// Parse parses a whitespace separated list of <signal>[:<rid>,<rid>]
+// elements, where signal is a signal name or number (ex. 1, hup or
+// sighup). The invalid elements are ignored.
 func Parse(s string) T {
 	t := make(map[syscall.Signal][]string)
 	for _, e := range strings.Fields(s) {
 		l := strings.SplitN(e, ":", 2)
-		if len(l) != 2 {
+		sigNum := parseSignal(l[0])
+		if sigNum == 0 {
 			continue
 		}
-		sigName := strings.ToUpper(l[0])
-		if !strings.HasPrefix(sigName, "SIG") {
-			sigName = "SIG" + sigName
-		}
-		sigNum := unix.SignalNum(sigName)
-		if sigNum == 0 {
+		if len(l) == 1 || l[1] == "" {
+			t[sigNum] = []string{}
 			continue
 		}
 		rids := strings.Split(l[1], ",")
@@ -31,3 +34,17 @@ func Parse(s string) T {
 	}
 	return T(t)
 }
+
+func parseSignal(s string) syscall.Signal {
+	if i, err := strconv.Atoi(s); err == nil {
+		if unix.SignalName(syscall.Signal(i)) == "" {
+			return 0
+		}
+		return syscall.Signal(i)
+	}
+	sigName := strings.ToUpper(s)
+	if !strings.HasPrefix(sigName, "SIG") {
+		sigName = "SIG" + sigName
+	}
+	return unix.SignalNum(sigName)
+}
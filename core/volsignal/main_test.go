@@ -15,3 +15,10 @@ func TestParse(t *testing.T) {
 	assert.Contains(t, m, syscall.SIGHUP, "contains SIGHUP")
 	assert.Contains(t, m, syscall.SIGKILL, "contains SIGKILL")
 }
+
+func TestParseNumberAndBareSignal(t *testing.T) {
+	m := Parse("10:app#1 usr2 999:app#2")
+	assert.Equal(t, 2, len(m))
+	assert.Equal(t, []string{"app#1"}, m[syscall.SIGUSR1])
+	assert.Equal(t, []string{}, m[syscall.SIGUSR2])
+}
@@ -0,0 +1,139 @@
+package xconfig
+
+import (
+	"strings"
+
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/key"
+)
+
+// hasOwnOption returns true if the configuration file, not its parents,
+// sets the option in the section, scoped or not.
+func (t *T) hasOwnOption(section, option string) bool {
+	s, err := t.file.GetSection(section)
+	if err != nil {
+		return false
+	}
+	for _, k := range s.KeyStrings() {
+		if name, _ := splitScope(k); name == option {
+			return true
+		}
+	}
+	return false
+}
+
+// inherits returns true if the option value of the section is read from
+// the parent configuration. The object id and the extends keywords are
+// never inherited.
+func (t *T) inherits(section, option string) bool {
+	if t.Parent == nil || t.hasOwnOption(section, option) {
+		return false
+	}
+	return !(section == "DEFAULT" && (option == "id" || option == "extends"))
+}
+
+// mergedSectionStrings returns the section names of the parent
+// configurations followed by the sections only defined in the
+// configuration file.
+func (t *T) mergedSectionStrings() []string {
+	own := t.file.SectionStrings()
+	if t.Parent == nil {
+		return own
+	}
+	l := t.Parent.mergedSectionStrings()
+	m := make(map[string]interface{})
+	for _, s := range l {
+		m[s] = nil
+	}
+	for _, s := range own {
+		if _, ok := m[s]; !ok {
+			l = append(l, s)
+		}
+	}
+	return l
+}
+
+// mergedKeyStrings returns the key names of the section, the keys set in
+// the configuration file first, followed by the inherited keys.
+func (t *T) mergedKeyStrings(section string) []string {
+	l := make([]string, 0)
+	if s, err := t.file.GetSection(section); err == nil {
+		l = append(l, s.KeyStrings()...)
+	}
+	if t.Parent == nil {
+		return l
+	}
+	for _, k := range t.Parent.mergedKeyStrings(section) {
+		if name, _ := splitScope(k); t.inherits(section, name) {
+			l = append(l, k)
+		}
+	}
+	return l
+}
+
+// mergedSectionMap returns the key values of the section, inherited keys
+// included.
+func (t *T) mergedSectionMap(section string) (map[string]string, bool) {
+	m := make(map[string]string)
+	found := false
+	if t.Parent != nil {
+		if pm, ok := t.Parent.mergedSectionMap(section); ok {
+			found = true
+			for k, v := range pm {
+				if name, _ := splitScope(k); t.inherits(section, name) {
+					m[k] = v
+				}
+			}
+		}
+	}
+	if s, err := t.file.GetSection(section); err == nil {
+		found = true
+		for k, v := range s.KeysHash() {
+			m[k] = v
+		}
+	}
+	return m, found
+}
+
+// KeySource returns the path of the object whose configuration file sets
+// the key option, scoped or not. The returned path is zero if no
+// configuration in the extends chain sets the key.
+func (t *T) KeySource(k key.T) path.T {
+	name, _ := splitScope(k.Option)
+	switch {
+	case t.hasOwnOption(k.Section, name):
+		return t.Path
+	case t.Parent != nil:
+		return t.Parent.KeySource(k)
+	default:
+		return path.T{}
+	}
+}
+
+// Extends returns the paths of the parent configurations, nearest first.
+func (t *T) Extends() []path.T {
+	l := make([]path.T, 0)
+	for p := t.Parent; p != nil; p = p.Parent {
+		l = append(l, p.Path)
+	}
+	return l
+}
+
+// keySources returns the source path of each inherited key of the
+// evaluated configuration, indexed by <section>.<option>.
+func (t *T) keySources(sections []string) map[string]string {
+	m := make(map[string]string)
+	if t.Parent == nil {
+		return m
+	}
+	for _, section := range sections {
+		for _, option := range t.mergedKeyStrings(section) {
+			option = strings.SplitN(option, "@", 2)[0]
+			k := key.New(section, option)
+			if p := t.KeySource(k); p != t.Path {
+				m[section+"."+option] = p.String()
+			}
+		}
+	}
+	return m
+}
@@ -0,0 +1,78 @@
+package xconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/key"
+)
+
+func TestExtends(t *testing.T) {
+	load := func(p, s string) *T {
+		f, err := ini.Load([]byte(s))
+		require.NoError(t, err)
+		objectPath, err := path.Parse(p)
+		require.NoError(t, err)
+		return &T{file: f, Path: objectPath}
+	}
+	base := load("templates/svc/base", `
+[DEFAULT]
+id = 1
+topology = flex
+
+[app#0]
+start = /bin/true
+timeout = 1m
+`)
+	web := load("templates/svc/web", `
+[DEFAULT]
+id = 2
+extends = templates/svc/base
+
+[app#0]
+timeout@n1 = 2m
+
+[app#1]
+start = /usr/sbin/nginx
+`)
+	web.Parent = base
+	w1 := load("w1", `
+[DEFAULT]
+id = 3
+extends = templates/svc/web
+
+[app#1]
+start = /opt/nginx/bin/nginx
+`)
+	w1.Parent = web
+
+	get := func(s string) string {
+		v, _ := w1.GetStrict(key.Parse(s))
+		return v
+	}
+	assert.Equal(t, "3", get("DEFAULT.id"), "id is not inherited")
+	assert.Equal(t, "templates/svc/web", get("DEFAULT.extends"))
+	assert.Equal(t, "flex", get("DEFAULT.topology"))
+	assert.Equal(t, "/bin/true", get("app#0.start"))
+	assert.Equal(t, "2m", get("app#0.timeout@n1"))
+	assert.Equal(t, "", get("app#0.timeout"), "a scoped value overrides all the inherited scopes")
+	assert.Equal(t, "/opt/nginx/bin/nginx", get("app#1.start"))
+
+	assert.Equal(t, []string{"DEFAULT", "app#0", "app#1"}, w1.SectionStrings())
+	assert.True(t, w1.HasKey(key.Parse("app#0.start")))
+	assert.False(t, w1.HasKey(key.Parse("app#0.timeout")))
+
+	assert.Equal(t, "templates/svc/base", w1.KeySource(key.Parse("app#0.start")).String())
+	assert.Equal(t, "templates/svc/web", w1.KeySource(key.Parse("app#0.timeout")).String())
+	assert.Equal(t, "w1", w1.KeySource(key.Parse("app#1.start")).String())
+	assert.Equal(t, []path.T{web.Path, base.Path}, w1.Extends())
+
+	assert.Equal(t, map[string]string{
+		"DEFAULT.topology": "templates/svc/base",
+		"app#0.start":      "templates/svc/base",
+		"app#0.timeout":    "templates/svc/web",
+	}, w1.keySources(w1.SectionStrings()))
+}
@@ -1,6 +1,7 @@
 package xconfig
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -19,6 +20,7 @@ import (
 	"opensvc.com/opensvc/core/keywords"
 	"opensvc.com/opensvc/core/path"
 	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/expr"
 	"opensvc.com/opensvc/util/file"
 	"opensvc.com/opensvc/util/hostname"
 	"opensvc.com/opensvc/util/key"
@@ -35,6 +37,21 @@ type (
 		Referrer       Referrer
 		NodeReferrer   Referrer
 		file           *ini.File
+
+		// Parent is the configuration of the template object this
+		// configuration extends. The keywords not set in the
+		// configuration file, and the sections it does not define,
+		// are read from Parent. Only the configuration file keys are
+		// written on commit.
+		Parent *T
+
+		// warnedAliases is the set of keyword alias keys already
+		// reported as deprecated.
+		warnedAliases map[string]interface{}
+
+		// committed is the configuration as loaded or last committed,
+		// restored when a commit is refused by the validation.
+		committed []byte
 	}
 
 	// Referer is the interface implemented by node and object to
@@ -66,6 +83,10 @@ var (
 	ErrExist        = errors.New("configuration does not exist")
 	ErrNoKeyword    = errors.New("keyword does not exist")
 
+	// RedactedValue replaces the values of sec keys references in the
+	// evaluated configuration returned by RawEvaluated.
+	RedactedValue = "xxxx"
+
 	DriverGroups = set.New("ip", "volume", "disk", "fs", "share", "container", "app", "sync", "task")
 )
 
@@ -82,26 +103,34 @@ func NewErrPostponedRef(ref string, rid string) ErrPostponedRef {
 
 // Keys returns the key names available in a section
 func (t *T) Keys(section string) []string {
-	data := make([]string, 0)
-	for _, s := range t.file.Section(section).Keys() {
-		data = append(data, s.Name())
-	}
-	return data
+	return t.mergedKeyStrings(section)
 }
 
 // HasKey returns true if the k exists
 func (t *T) HasKey(k key.T) bool {
-	return t.file.Section(k.Section).HasKey(k.Option)
+	if t.inherits(k.Section, strings.SplitN(k.Option, "@", 2)[0]) {
+		return t.Parent.HasKey(k)
+	}
+	s, err := t.file.GetSection(k.Section)
+	if err != nil {
+		return false
+	}
+	return s.HasKey(k.Option)
 }
 
+// Get returns the raw value of the k key, or an empty string if the key
+// does not exist. The key is not created in the latter case, so a
+// Commit following a Get does not add empty keys to the file.
 func (t *T) Get(k key.T) string {
-	val := t.file.Section(k.Section).Key(k.Option).Value()
+	val, _ := t.GetStrict(k)
 	return val
 }
 
 func (t *T) GetStrict(k key.T) (string, error) {
-	s := t.file.Section(k.Section)
-	if s.HasKey(k.Option) {
+	if t.inherits(k.Section, strings.SplitN(k.Option, "@", 2)[0]) {
+		return t.Parent.GetStrict(k)
+	}
+	if s, err := t.file.GetSection(k.Section); err == nil && s.HasKey(k.Option) {
 		return s.Key(k.Option).Value(), nil
 	}
 	return "", errors.Wrapf(ErrExist, "key '%s' not found (unscopable kw)", k)
@@ -199,6 +228,19 @@ func (t *T) Unset(ks ...key.T) int {
 	return deleted
 }
 
+// UnsetSections deletes sections and returns the number of deleted sections
+func (t *T) UnsetSections(sections ...string) int {
+	deleted := 0
+	for _, section := range sections {
+		if _, err := t.file.GetSection(section); err != nil {
+			continue
+		}
+		t.file.DeleteSection(section)
+		deleted += 1
+	}
+	return deleted
+}
+
 func (t *T) Set(op keyop.T) error {
 	if !DriverGroups.Has(op.Key.Section) {
 		return t.set(op)
@@ -208,7 +250,7 @@ func (t *T) Set(op keyop.T) error {
 
 func (t *T) DriverGroupSet(op keyop.T) error {
 	prefix := op.Key.Section + "#"
-	for _, section := range t.file.SectionStrings() {
+	for _, section := range t.SectionStrings() {
 		if !strings.HasPrefix(section, prefix) {
 			continue
 		}
@@ -359,7 +401,7 @@ func (t *T) sectionType(k key.T) string {
 }
 
 func (t *T) EvalKeywordAs(k key.T, kw keywords.Keyword, impersonate string) (interface{}, error) {
-	v, err := t.evalStringAs(k, kw, impersonate)
+	v, err := t.evalStringAs(k, kw, impersonate, false)
 	if err != nil {
 		return nil, err
 	}
@@ -378,12 +420,15 @@ func getKeyword(k key.T, sectionType string, referrer Referrer) (keywords.Keywor
 	return kw, nil
 }
 
-func (t *T) evalStringAs(k key.T, kw keywords.Keyword, impersonate string) (string, error) {
+// evalStringAs returns the descoped value of the key, with its references
+// replaced and its expressions evaluated. If redact is set, the references
+// to sec keys are replaced by RedactedValue.
+func (t *T) evalStringAs(k key.T, kw keywords.Keyword, impersonate string, redact bool) (string, error) {
 	v, err := t.mayDescope(k, kw, impersonate)
 	if err != nil {
 		return "", err
 	}
-	return t.replaceReferences(v, k.Section, impersonate)
+	return t.replaceReferences(v, k.Section, impersonate, redact)
 }
 
 func (t *T) convert(v string, kw keywords.Keyword) (interface{}, error) {
@@ -394,14 +439,9 @@ func (t *T) convert(v string, kw keywords.Keyword) (interface{}, error) {
 }
 
 func (t *T) mayDescope(k key.T, kw keywords.Keyword, impersonate string) (string, error) {
-	var (
-		v   string
-		err error
-	)
-	if kw.Scopable {
-		v, err = t.descope(k, impersonate)
-	} else {
-		v, err = t.GetStrict(k)
+	v, err := t.descopeOrGet(k, kw, impersonate)
+	if errors.Is(err, ErrExist) && k.Option == kw.Option {
+		v, err = t.descopeAlias(k, kw, impersonate)
 	}
 	switch {
 	case errors.Is(err, ErrExist):
@@ -417,14 +457,76 @@ func (t *T) mayDescope(k key.T, kw keywords.Keyword, impersonate string) (string
 	return v, nil
 }
 
-func (t *T) replaceReferences(v string, section string, impersonate string) (string, error) {
+func (t *T) descopeOrGet(k key.T, kw keywords.Keyword, impersonate string) (string, error) {
+	if kw.Scopable {
+		return t.descope(k, impersonate)
+	}
+	return t.GetStrict(k)
+}
+
+// descopeAlias returns the value of the first keyword alias set in the
+// section, so the configurations written by older agents are still
+// read. A warning is logged once per alias key.
+func (t *T) descopeAlias(k key.T, kw keywords.Keyword, impersonate string) (string, error) {
+	for _, alias := range kw.Aliases {
+		ak := key.New(k.Section, alias)
+		v, err := t.descopeOrGet(ak, kw, impersonate)
+		if err != nil {
+			continue
+		}
+		if _, ok := t.warnedAliases[ak.String()]; !ok && t.Referrer != nil {
+			if t.warnedAliases == nil {
+				t.warnedAliases = make(map[string]interface{})
+			}
+			t.warnedAliases[ak.String()] = nil
+			t.Referrer.Log().Warn().Msgf("keyword %s is deprecated, use %s", ak, k)
+		}
+		return v, nil
+	}
+	return "", errors.Wrapf(ErrExist, "key '%s' not found (aliases tried)", k)
+}
+
+// replaceReferences returns the value with the {...} references replaced
+// and the expressions evaluated. If redact is set, the references to sec
+// keys are replaced by RedactedValue.
+func (t *T) replaceReferences(v string, section string, impersonate string, redact bool) (string, error) {
+	segments, err := expr.Split(v)
+	if err != nil {
+		return v, err
+	}
+	var (
+		b    strings.Builder
+		errs = make([]error, 0)
+	)
+	for _, seg := range segments {
+		var (
+			s string
+			e error
+		)
+		if seg.Expr == nil {
+			s, e = t.replaceTextReferences(seg.Text, section, impersonate, redact)
+		} else {
+			s, e = t.evalExpression(seg, section, impersonate, redact)
+		}
+		if e != nil {
+			errs = append(errs, e)
+		}
+		b.WriteString(s)
+	}
+	for _, e := range errs {
+		return b.String(), e
+	}
+	return b.String(), nil
+}
+
+func (t *T) replaceTextReferences(v string, section string, impersonate string, redact bool) (string, error) {
 	errs := make([]error, 0)
 	v = rawconfig.RegexpReference.ReplaceAllStringFunc(v, func(ref string) string {
 		var (
 			s string
 			e error
 		)
-		s, e = t.dereference(ref, section, impersonate)
+		s, e = t.dereference(ref, section, impersonate, redact)
 		if e != nil {
 			switch e.(type) {
 			case ErrPostponedRef:
@@ -440,12 +542,34 @@ func (t *T) replaceReferences(v string, section string, impersonate string) (str
 	return v, nil
 }
 
+// evalExpression returns the value of an expression segment, or the
+// expression source on error. A postponed reference error is returned
+// unwrapped, even if the expression falls back to a default value.
+func (t *T) evalExpression(seg expr.Segment, section string, impersonate string, redact bool) (string, error) {
+	var postponed error
+	s, err := expr.Eval(seg.Expr, func(name string) (string, error) {
+		s, err := t.dereference("{"+name+"}", section, impersonate, redact)
+		if _, ok := err.(ErrPostponedRef); ok {
+			postponed = err
+		}
+		return s, err
+	})
+	switch {
+	case postponed != nil:
+		return seg.Text, postponed
+	case err != nil:
+		return seg.Text, errors.Wrapf(err, "%s", seg.Text)
+	default:
+		return s, nil
+	}
+}
+
 func (t T) sectionMap(section string) (map[string]string, error) {
-	s, err := t.file.GetSection(section)
-	if err != nil {
+	m, ok := t.mergedSectionMap(section)
+	if !ok {
 		return nil, errors.Wrapf(ErrExist, "section '%s'", section)
 	}
-	return s.KeysHash(), nil
+	return m, nil
 }
 
 func (t *T) descope(k key.T, impersonate string) (string, error) {
@@ -479,14 +603,50 @@ func (t T) Raw() rawconfig.T {
 	r.Data = orderedmap.New()
 	for _, s := range t.file.Sections() {
 		sectionMap := *orderedmap.New()
-		for k, v := range s.KeysHash() {
-			sectionMap.Set(k, v)
+		for _, k := range s.Keys() {
+			sectionMap.Set(k.Name(), k.Value())
 		}
 		r.Data.Set(s.Name(), sectionMap)
 	}
 	return r
 }
 
+// RawEvaluated returns the configuration with the values dereferenced and
+// descoped for the impersonated node. The scoped keys are merged into their
+// unscoped key. The references to sec keys are replaced by RedactedValue.
+// The keys inherited from the parent configurations are included, and
+// their source object is reported in the returned Sources.
+func (t *T) RawEvaluated(impersonate string) (rawconfig.T, error) {
+	r := rawconfig.T{}
+	r.Data = orderedmap.New()
+	sections := t.SectionStrings()
+	for _, section := range sections {
+		sectionType := t.sectionType(key.New(section, ""))
+		sectionMap := *orderedmap.New()
+		for _, option := range t.mergedKeyStrings(section) {
+			option = strings.SplitN(option, "@", 2)[0]
+			if _, ok := sectionMap.Get(option); ok {
+				continue
+			}
+			k := key.New(section, option)
+			kw, err := getKeyword(k, sectionType, t.Referrer)
+			if err != nil {
+				// unknown keyword, show the raw value
+				sectionMap.Set(option, t.Get(k))
+				continue
+			}
+			v, err := t.evalStringAs(k, kw, impersonate, true)
+			if err != nil {
+				return r, errors.Wrapf(err, "%s", k)
+			}
+			sectionMap.Set(option, v)
+		}
+		r.Data.Set(section, sectionMap)
+	}
+	r.Sources = t.keySources(sections)
+	return r, nil
+}
+
 func (t T) HasSectionString(s string) bool {
 	for _, e := range t.SectionStrings() {
 		if s == e {
@@ -497,7 +657,7 @@ func (t T) HasSectionString(s string) bool {
 }
 
 func (t T) SectionStrings() []string {
-	return t.file.SectionStrings()
+	return t.mergedSectionStrings()
 }
 
 func (t *T) IsInNodes(impersonate string) bool {
@@ -524,7 +684,7 @@ func (t *T) IsInEncapNodes(impersonate string) bool {
 	return s.Has(impersonate)
 }
 
-func (t T) dereference(ref string, section string, impersonate string) (string, error) {
+func (t T) dereference(ref string, section string, impersonate string, redact bool) (string, error) {
 	type f func(string) string
 	var (
 		modifier f
@@ -549,18 +709,27 @@ func (t T) dereference(ref string, section string, impersonate string) (string,
 	}
 	switch {
 	case strings.HasPrefix(ref, "node."):
-		if val, err = t.dereferenceNodeKey(ref, impersonate); err != nil {
+		if val, err = t.dereferenceNodeKey(ref, impersonate, redact); err != nil {
+			return ref, err
+		}
+	case l[0] == "sec" && redact:
+		val = RedactedValue
+	case l[0] == "sec" || l[0] == "cfg":
+		if t.Referrer == nil {
+			return ref, fmt.Errorf("no referrer to resolve %s", ref)
+		}
+		if val, err = t.Referrer.Dereference(ref); err != nil {
 			return ref, err
 		}
 	default:
-		if val, err = t.dereferenceWellKnown(ref, section, impersonate); err != nil {
+		if val, err = t.dereferenceWellKnown(ref, section, impersonate, redact); err != nil {
 			return ref, err
 		}
 	}
 	return modifier(val), nil
 }
 
-func (t T) dereferenceNodeKey(ref string, impersonate string) (string, error) {
+func (t T) dereferenceNodeKey(ref string, impersonate string, redact bool) (string, error) {
 	t.Referrer.Log().Debug().Msgf("dereference node key %s", ref)
 
 	//
@@ -594,28 +763,28 @@ func (t T) dereferenceNodeKey(ref string, impersonate string) (string, error) {
 		return ref, fmt.Errorf("denied reference to node key %s", ref)
 	}
 
-	val, err := t.NodeReferrer.Config().evalStringAs(nodeKey, kw, impersonate)
+	val, err := t.NodeReferrer.Config().evalStringAs(nodeKey, kw, impersonate, redact)
 	if err != nil {
 		return ref, err
 	}
 	return val, nil
 }
 
-func (t T) dereferenceKey(ref string, section string, impersonate string) (string, error) {
+func (t T) dereferenceKey(ref string, section string, impersonate string, redact bool) (string, error) {
 	t.Referrer.Log().Debug().Msgf("dereference well known key %s", ref)
 	refKey := key.Parse(ref)
 	if refKey.Section == "" {
 		refKey.Section = section
 	}
-	key, err := t.file.Section(refKey.Section).GetKey(refKey.Option)
+	v, err := t.GetStrict(refKey)
 	if err != nil {
 		return "", err
 	}
-	return t.replaceReferences(key.String(), refKey.Section, impersonate)
+	return t.replaceReferences(v, refKey.Section, impersonate, redact)
 }
 
-func (t T) dereferenceWellKnown(ref string, section string, impersonate string) (string, error) {
-	if v, err := t.dereferenceKey(ref, section, impersonate); err == nil {
+func (t T) dereferenceWellKnown(ref string, section string, impersonate string, redact bool) (string, error) {
+	if v, err := t.dereferenceKey(ref, section, impersonate, redact); err == nil {
 		return v, nil
 	}
 	switch ref {
@@ -712,6 +881,7 @@ func (t *T) rawCommit(configData rawconfig.T, configPath string, validate bool)
 	}
 	if validate {
 		if err := t.validate(); err != nil {
+			t.rollback()
 			return err
 		}
 	}
@@ -720,12 +890,44 @@ func (t *T) rawCommit(configData rawconfig.T, configPath string, validate bool)
 			return err
 		}
 	}
+	t.snapshot()
 	//t.clearRefCache()
 	return t.postCommit()
 }
 
-func (t T) validate() error {
-	return nil
+// snapshot saves the configuration, so rollback can restore it if a
+// later commit is refused.
+func (t *T) snapshot() {
+	var b bytes.Buffer
+	if _, err := t.file.WriteTo(&b); err != nil {
+		return
+	}
+	t.committed = b.Bytes()
+}
+
+// rollback restores the configuration saved by the last snapshot, so the
+// changes refused by a commit don't stay in memory and make the next
+// commits fail.
+func (t *T) rollback() {
+	if t.committed == nil {
+		return
+	}
+	f, err := ini.LoadSources(loadOptions, t.committed)
+	if err != nil {
+		return
+	}
+	t.file = f
+}
+
+func (t *T) validate() error {
+	alerts, err := t.Validate()
+	if err != nil {
+		return err
+	}
+	if !alerts.HasError() {
+		return nil
+	}
+	return errors.Wrapf(ErrInvalid, "%s", alerts.Errors())
 }
 
 func (t *T) Commit() error {
@@ -760,7 +962,7 @@ func (t T) postCommit() error {
 	if t.Referrer == nil {
 		return nil
 	}
-	return nil
+	return t.Referrer.PostCommit()
 }
 
 func (t T) DeleteSections(sections []string) error {
@@ -0,0 +1,189 @@
+package xconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/expr"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/render/tree"
+	"opensvc.com/opensvc/util/stringslice"
+)
+
+type (
+	// Matrix is the evaluated value of the configuration keys for each
+	// node of the object nodes and drpnodes, so the scoped values can be
+	// reviewed before a failover.
+	Matrix struct {
+		Nodes []string    `json:"nodes"`
+		Keys  []MatrixKey `json:"keys"`
+	}
+
+	// MatrixKey is the evaluated values of a key, indexed by node name.
+	// Differ is true if the values are not the same on all nodes.
+	MatrixKey struct {
+		Key    string                `json:"key"`
+		Differ bool                  `json:"differ"`
+		Values map[string]MatrixCell `json:"values"`
+	}
+
+	// MatrixCell is the value of a key evaluated for a node, or the
+	// evaluation error.
+	MatrixCell struct {
+		Value string `json:"value"`
+		Error string `json:"error,omitempty"`
+	}
+)
+
+// EvalMatrix returns the values of the configuration keys evaluated for
+// each of the nodes and drpnodes. The scoped keys are merged into their
+// unscoped key, the inherited keys are included and the references to
+// sec keys are replaced by RedactedValue, like in RawEvaluated. The values
+// not accepted by the keyword converter are reported as errors.
+func (t *T) EvalMatrix() Matrix {
+	m := Matrix{
+		Nodes: t.matrixNodes(),
+		Keys:  make([]MatrixKey, 0),
+	}
+	for _, section := range t.SectionStrings() {
+		sectionType := t.sectionType(key.New(section, ""))
+		seen := make(map[string]interface{})
+		for _, option := range t.mergedKeyStrings(section) {
+			option, _ = splitScope(option)
+			if _, ok := seen[option]; ok {
+				continue
+			}
+			seen[option] = nil
+			k := key.New(section, option)
+			mk := MatrixKey{
+				Key:    k.String(),
+				Values: make(map[string]MatrixCell),
+			}
+			for _, node := range m.Nodes {
+				mk.Values[node] = t.evalMatrixCell(k, sectionType, node)
+			}
+			mk.Differ = mk.differ(m.Nodes)
+			m.Keys = append(m.Keys, mk)
+		}
+	}
+	return m
+}
+
+// matrixNodes returns the nodes and drpnodes of the object, without
+// duplicates.
+func (t *T) matrixNodes() []string {
+	l := make([]string, 0)
+	if t.Referrer == nil {
+		return l
+	}
+	for _, node := range append(t.Referrer.Nodes(), t.Referrer.DRPNodes()...) {
+		if !stringslice.Has(node, l) {
+			l = append(l, node)
+		}
+	}
+	return l
+}
+
+func (t *T) evalMatrixCell(k key.T, sectionType, node string) MatrixCell {
+	kw, err := getKeyword(k, sectionType, t.Referrer)
+	if err != nil {
+		// unknown keyword, show the raw value
+		return MatrixCell{Value: t.Get(k)}
+	}
+	v, err := t.evalStringAs(k, kw, node, true)
+	if err != nil {
+		return MatrixCell{Value: v, Error: err.Error()}
+	}
+	if err := t.checkMatrixReferences(k, kw, node); err != "" {
+		return MatrixCell{Value: v, Error: err}
+	}
+	if v == "" {
+		// unset, with no default
+		return MatrixCell{}
+	}
+	if _, err := t.convert(v, kw); err != nil {
+		return MatrixCell{Value: v, Error: err.Error()}
+	}
+	return MatrixCell{Value: v}
+}
+
+// checkMatrixReferences returns the first error raised by the reference
+// check of the config validation on the text segments of the value of k
+// for node, as their evaluation keeps the unresolvable references as
+// text. The expression errors are already returned by the evaluation.
+func (t *T) checkMatrixReferences(k key.T, kw keywords.Keyword, node string) string {
+	raw, err := t.mayDescope(k, kw, node)
+	if err != nil {
+		return err.Error()
+	}
+	segments, err := expr.Split(raw)
+	if err != nil {
+		return err.Error()
+	}
+	alerts := make(Alerts, 0)
+	ok := true
+	for _, seg := range segments {
+		if seg.Expr == nil {
+			t.validateTextReferences(&alerts, k, k.Section, seg.Text, node, &ok)
+		}
+	}
+	for _, alert := range alerts.Errors() {
+		return alert.Comment
+	}
+	return ""
+}
+
+func (t MatrixKey) differ(nodes []string) bool {
+	for i := 1; i < len(nodes); i++ {
+		if t.Values[nodes[i]] != t.Values[nodes[0]] {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Matrix) Render() string {
+	return t.Tree().Render()
+}
+
+// Tree returns a tree loaded with the type instance.
+func (t Matrix) Tree() *tree.Tree {
+	tree := tree.New()
+	t.LoadTreeNode(tree.Head())
+	return tree
+}
+
+// LoadTreeNode add the tree nodes representing the type instance into another.
+// The keys whose values differ between nodes are highlighted, and the
+// evaluation errors are displayed in place of the value.
+func (t Matrix) LoadTreeNode(head *tree.Node) {
+	head.AddColumn().AddText("key").SetColor(rawconfig.Node.Color.Bold)
+	for _, node := range t.Nodes {
+		head.AddColumn().AddText(node).SetColor(rawconfig.Node.Color.Bold)
+	}
+	for _, k := range t.Keys {
+		n := head.AddNode()
+		if k.Differ {
+			n.AddColumn().AddText(k.Key).SetColor(rawconfig.Node.Color.Warning)
+		} else {
+			n.AddColumn().AddText(k.Key).SetColor(rawconfig.Node.Color.Primary)
+		}
+		for _, node := range t.Nodes {
+			cell := k.Values[node]
+			if cell.Error != "" {
+				n.AddColumn().AddText(cell.String()).SetColor(rawconfig.Node.Color.Error)
+			} else {
+				n.AddColumn().AddText(cell.String())
+			}
+		}
+	}
+}
+
+func (t MatrixCell) String() string {
+	if t.Error != "" {
+		return fmt.Sprintf("error: %s", strings.TrimSpace(t.Error))
+	}
+	return t.Value
+}
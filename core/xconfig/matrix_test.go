@@ -0,0 +1,98 @@
+package xconfig
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/util/key"
+)
+
+func TestEvalMatrix(t *testing.T) {
+	cfg := newTestConfig(t, `
+[DEFAULT]
+topology = flex
+flex_min = 1
+flex_min@n2 = 2
+foo = bar
+
+[app#1]
+disable@n1 = false
+disable@n2 = maybe
+`)
+	m := cfg.EvalMatrix()
+	assert.Equal(t, []string{"n1", "n2"}, m.Nodes)
+	require.Len(t, m.Keys, 4)
+
+	assert.Equal(t, "topology", m.Keys[0].Key)
+	assert.False(t, m.Keys[0].Differ)
+	assert.Equal(t, MatrixCell{Value: "flex"}, m.Keys[0].Values["n2"])
+
+	assert.Equal(t, "flex_min", m.Keys[1].Key, "scoped keys are merged")
+	assert.True(t, m.Keys[1].Differ)
+	assert.Equal(t, "1", m.Keys[1].Values["n1"].Value)
+	assert.Equal(t, "2", m.Keys[1].Values["n2"].Value)
+
+	assert.Equal(t, MatrixCell{Value: "bar"}, m.Keys[2].Values["n1"], "unknown keyword raw value")
+
+	assert.Equal(t, "app#1.disable", m.Keys[3].Key)
+	assert.True(t, m.Keys[3].Differ)
+	assert.Empty(t, m.Keys[3].Values["n1"].Error)
+	assert.Equal(t, "maybe", m.Keys[3].Values["n2"].Value)
+	assert.NotEmpty(t, m.Keys[3].Values["n2"].Error)
+	assert.Contains(t, m.Keys[3].Values["n2"].String(), "error: ")
+}
+
+func TestEvalMatrixReferences(t *testing.T) {
+	cfg := newTestConfig(t, `
+[disk#1]
+dev = /dev/{name}
+dev@n2 = /dev/{nonexist}
+`)
+	r := cfg.Referrer.(testReferrer)
+	r.store = append(r.store, keywords.Keyword{Section: "disk", Option: "dev", Scopable: true, Kind: kind.Or(kind.Svc)})
+	cfg.Referrer = r
+
+	m := cfg.EvalMatrix()
+	require.Len(t, m.Keys, 1)
+	assert.Equal(t, "disk#1.dev", m.Keys[0].Key)
+	assert.Equal(t, MatrixCell{Value: "/dev/svc1"}, m.Keys[0].Values["n1"])
+	assert.Equal(t, "/dev/{nonexist}", m.Keys[0].Values["n2"].Value)
+	assert.Contains(t, m.Keys[0].Values["n2"].Error, "nonexist", "unresolvable references are reported")
+}
+
+func TestRedaction(t *testing.T) {
+	cfg := newTestConfig(t, `
+[app#1]
+start = /bin/login {sec:s1/password}
+`)
+	k := key.New("app#1", "start")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			m := cfg.EvalMatrix()
+			if assert.Len(t, m.Keys, 1) {
+				assert.Equal(t, "/bin/login "+RedactedValue, m.Keys[0].Values["n1"].Value)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			v, err := cfg.EvalAs(k, "n1")
+			assert.NoError(t, err)
+			assert.Equal(t, "/bin/login s3cr3t", v, "evaluations are not redacted by a concurrent display")
+		}()
+	}
+	wg.Wait()
+	r, err := cfg.RawEvaluated("n1")
+	require.NoError(t, err)
+	i, _ := r.Data.Get("app#1")
+	sectionMap := i.(orderedmap.OrderedMap)
+	v, _ := sectionMap.Get("start")
+	assert.Equal(t, "/bin/login "+RedactedValue, v)
+}
@@ -8,20 +8,24 @@ import (
 	"gopkg.in/ini.v1"
 )
 
+// loadOptions are the options of the configuration files parser.
+var loadOptions = ini.LoadOptions{
+	Loose:                      true,
+	AllowPythonMultilineValues: true,
+	SpaceBeforeInlineComment:   true,
+}
+
 // NewObject configures and returns a Viper instance
 func NewObject(p string, others ...interface{}) (t *T, err error) {
 	cf := filepath.FromSlash(p)
 	t = &T{
 		ConfigFilePath: cf,
 	}
-	t.file, err = ini.LoadSources(ini.LoadOptions{
-		Loose:                      true,
-		AllowPythonMultilineValues: true,
-		SpaceBeforeInlineComment:   true,
-	}, cf, others...)
+	t.file, err = ini.LoadSources(loadOptions, cf, others...)
 	if err != nil {
 		return nil, errors.Wrap(err, "load config error")
 	}
+	t.snapshot()
 	log.Debug().Msgf("new config for %s: %d sections", p, len(t.file.Sections()))
 	return t, nil
 }
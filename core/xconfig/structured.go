@@ -0,0 +1,56 @@
+package xconfig
+
+import (
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/converters"
+	"opensvc.com/opensvc/util/key"
+)
+
+// Structured returns a copy of the configuration data, with the values of
+// the list keywords, scoped or not, converted to lists. The values of the
+// other keywords, including the shlex-formatted commands, are kept as
+// strings.
+func (t *T) Structured(data rawconfig.T) rawconfig.T {
+	if data.Data == nil || t.Referrer == nil {
+		return data
+	}
+	r := rawconfig.T{Data: orderedmap.New(), Sources: data.Sources}
+	for _, section := range data.Data.Keys() {
+		i, _ := data.Data.Get(section)
+		options, ok := i.(orderedmap.OrderedMap)
+		if !ok {
+			r.Data.Set(section, i)
+			continue
+		}
+		sectionType := ""
+		if v, ok := options.Get("type"); ok {
+			sectionType, _ = v.(string)
+		}
+		m := orderedmap.New()
+		for _, option := range options.Keys() {
+			v, _ := options.Get(option)
+			s, ok := v.(string)
+			if ok && t.isListKeyword(section, option, sectionType) {
+				m.Set(option, strings.Fields(s))
+			} else {
+				m.Set(option, v)
+			}
+		}
+		r.Data.Set(section, *m)
+	}
+	return r
+}
+
+func (t *T) isListKeyword(section, option, sectionType string) bool {
+	name, _ := splitScope(option)
+	kw := t.Referrer.KeywordLookup(key.New(section, name), sectionType)
+	switch kw.Converter.(type) {
+	case converters.TList, converters.TListLowercase, converters.TSet, TNodesConverter, TOtherNodesConverter:
+		return true
+	default:
+		return false
+	}
+}
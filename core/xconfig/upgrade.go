@@ -0,0 +1,68 @@
+package xconfig
+
+import (
+	"opensvc.com/opensvc/core/keyop"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+// UpgradeChanges returns the changes rewriting the configuration file
+// to the current keyword names and value formats: the keys set with a
+// keyword alias are renamed to the keyword name, and the values written
+// in a legacy format are converted. An alias key is dropped if the
+// keyword is also set with its name, which has precedence. The keys
+// inherited from the parent configurations are not considered.
+func (t *T) UpgradeChanges() rawconfig.Changes {
+	l := make(rawconfig.Changes, 0)
+	if t.Referrer == nil {
+		return l
+	}
+	for _, s := range t.file.Sections() {
+		section := s.Name()
+		sectionType := t.sectionType(key.New(section, ""))
+		added := make(map[string]interface{})
+		for _, k := range s.Keys() {
+			option, value := k.Name(), k.Value()
+			name, scope := splitScope(option)
+			kw := t.Referrer.KeywordLookup(key.New(section, name), sectionType)
+			if kw.IsZero() {
+				continue
+			}
+			upgraded := kw.UpgradeValue(value)
+			if !kw.HasAlias(name) {
+				if upgraded != value {
+					l = append(l, rawconfig.Change{Section: section, Option: option, Op: rawconfig.ChangeModify, From: value, To: upgraded})
+				}
+				continue
+			}
+			l = append(l, rawconfig.Change{Section: section, Option: option, Op: rawconfig.ChangeDelete, From: value})
+			newOption := kw.Option
+			if scope != "" {
+				newOption += "@" + scope
+			}
+			if _, ok := added[newOption]; ok || s.HasKey(newOption) {
+				continue
+			}
+			added[newOption] = nil
+			l = append(l, rawconfig.Change{Section: section, Option: newOption, Op: rawconfig.ChangeAdd, To: upgraded})
+		}
+	}
+	return l
+}
+
+// ApplyChanges sets the added and modified keys, and unsets the deleted
+// keys, of the configuration file. The changes are not committed.
+func (t *T) ApplyChanges(changes rawconfig.Changes) error {
+	for _, c := range changes {
+		k := key.New(c.Section, c.Option)
+		switch c.Op {
+		case rawconfig.ChangeDelete:
+			t.Unset(k)
+		default:
+			if err := t.set(keyop.T{Key: k, Op: keyop.Set, Value: c.To}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
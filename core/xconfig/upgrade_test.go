@@ -0,0 +1,139 @@
+package xconfig
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/converters"
+	"opensvc.com/opensvc/util/key"
+)
+
+type testReferrer struct {
+	store  keywords.Store
+	config *T
+	log    zerolog.Logger
+}
+
+func (t testReferrer) KeywordLookup(k key.T, sectionType string) keywords.Keyword {
+	if k.Section == "data" {
+		return keywords.Keyword{Option: "*", Scopable: true}
+	}
+	return t.store.Lookup(k, kind.Svc, sectionType)
+}
+
+func (t testReferrer) PostCommit() error    { return nil }
+func (t testReferrer) IsVolatile() bool     { return true }
+func (t testReferrer) Log() *zerolog.Logger { return &t.log }
+func (t testReferrer) Config() *T           { return t.config }
+func (t testReferrer) Nodes() []string      { return []string{"n1", "n2"} }
+func (t testReferrer) DRPNodes() []string   { return []string{} }
+func (t testReferrer) EncapNodes() []string { return []string{} }
+
+func (t testReferrer) Dereference(ref string) (string, error) {
+	switch ref {
+	case "name":
+		return "svc1", nil
+	case "sec:s1/password":
+		return "s3cr3t", nil
+	default:
+		return ref, fmt.Errorf("unknown reference: %s", ref)
+	}
+}
+
+func (t testReferrer) SectionKeywords(section string, sectionType string) []keywords.Keyword {
+	l := make([]keywords.Keyword, 0)
+	driverGroup := strings.Split(section, "#")[0]
+	for _, kw := range t.store {
+		if kw.Section == section || kw.Section == driverGroup {
+			l = append(l, kw)
+		}
+	}
+	return l
+}
+
+func newTestConfig(t *testing.T, s string) *T {
+	f, err := ini.LoadSources(loadOptions, []byte(s))
+	require.NoError(t, err)
+	cfg := &T{file: f}
+	cfg.Referrer = testReferrer{
+		config: cfg,
+		log:    zerolog.Nop(),
+		store: keywords.Store{
+			{Section: "DEFAULT", Option: "topology", Aliases: []string{"cluster_type"}, Kind: kind.Or(kind.Svc)},
+			{Section: "DEFAULT", Option: "flex_min", Aliases: []string{"flex_min_nodes"}, Converter: converters.Int, Scopable: true, Kind: kind.Or(kind.Svc)},
+			{Section: "DEFAULT", Option: "flex_max", Aliases: []string{"flex_max_nodes"}, Converter: converters.Int, Kind: kind.Or(kind.Svc)},
+			{Section: "DEFAULT", Option: "orchestrate", Deprecated: "2.1", ReplacedBy: "DEFAULT.placement", Kind: kind.Or(kind.Svc)},
+			{Option: "disable", Converter: converters.Bool, Scopable: true, Kind: kind.Or(kind.Svc)},
+			{Section: "app", Option: "start", Kind: kind.Or(kind.Svc)},
+			{Section: "fs", Option: "mnt", Required: true, Kind: kind.Or(kind.Svc)},
+			{Section: "fs", Option: "type", Candidates: []string{"ext4", "xfs"}, Kind: kind.Or(kind.Svc)},
+			{Section: "fs", Option: "size", Converter: converters.Size, Kind: kind.Or(kind.Svc)},
+		},
+	}
+	cfg.snapshot()
+	return cfg
+}
+
+func TestAliases(t *testing.T) {
+	cfg := newTestConfig(t, `
+[DEFAULT]
+cluster_type = flex
+flex_min_nodes@n1 = 2
+flex_max_nodes = 3
+flex_max = 4
+orchestrate = ha
+
+[app#1]
+disable = yes
+`)
+	assert.Equal(t, "flex", cfg.GetString(key.Parse("topology")), "read from alias")
+	flexMin := func() interface{} {
+		v, err := cfg.EvalAs(key.Parse("flex_min"), "n1")
+		require.NoError(t, err)
+		return v
+	}
+	assert.Equal(t, 2, flexMin(), "read from scoped alias")
+	assert.Equal(t, 4, cfg.GetInt(key.Parse("flex_max")), "keyword name has precedence over alias")
+	assert.True(t, cfg.GetBool(key.Parse("app#1.disable")), "legacy bool value")
+
+	alerts, err := cfg.Validate()
+	require.NoError(t, err)
+	assert.False(t, alerts.HasError())
+	comments := make(map[string]string)
+	for _, a := range alerts {
+		assert.Equal(t, AlertKindDeprecated, a.Kind)
+		comments[a.Key] = a.Comment
+	}
+	assert.Equal(t, map[string]string{
+		"cluster_type":      "keyword name is deprecated, use topology",
+		"flex_min_nodes@n1": "keyword name is deprecated, use flex_min",
+		"flex_max_nodes":    "keyword name is deprecated, use flex_max",
+		"orchestrate":       "keyword is deprecated since 2.1, use DEFAULT.placement",
+		"app#1.disable":     "value format is deprecated, use true",
+	}, comments)
+
+	changes := cfg.UpgradeChanges()
+	assert.Equal(t, rawconfig.Changes{
+		{Section: "DEFAULT", Option: "cluster_type", Op: rawconfig.ChangeDelete, From: "flex"},
+		{Section: "DEFAULT", Option: "topology", Op: rawconfig.ChangeAdd, To: "flex"},
+		{Section: "DEFAULT", Option: "flex_min_nodes@n1", Op: rawconfig.ChangeDelete, From: "2"},
+		{Section: "DEFAULT", Option: "flex_min@n1", Op: rawconfig.ChangeAdd, To: "2"},
+		{Section: "DEFAULT", Option: "flex_max_nodes", Op: rawconfig.ChangeDelete, From: "3"},
+		{Section: "app#1", Option: "disable", Op: rawconfig.ChangeModify, From: "yes", To: "true"},
+	}, changes)
+
+	require.NoError(t, cfg.ApplyChanges(changes))
+	assert.Empty(t, cfg.UpgradeChanges(), "upgrade is idempotent")
+	assert.Equal(t, "flex", cfg.Get(key.Parse("topology")))
+	assert.Equal(t, 2, flexMin())
+	assert.Equal(t, 4, cfg.GetInt(key.Parse("flex_max")))
+	assert.False(t, cfg.HasKey(key.Parse("cluster_type")))
+}
@@ -0,0 +1,457 @@
+package xconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/resourcereqs"
+	"opensvc.com/opensvc/util/expr"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/render/tree"
+	"opensvc.com/opensvc/util/stringslice"
+)
+
+type (
+	// Alert is a configuration validation finding.
+	Alert struct {
+		Level   AlertLevel `json:"level"`
+		Kind    AlertKind  `json:"kind"`
+		Key     string     `json:"key"`
+		Comment string     `json:"comment"`
+	}
+
+	// Alerts is the list of findings returned by Validate.
+	Alerts []Alert
+
+	// AlertLevel is the severity of an Alert. Only error alerts make
+	// a configuration invalid.
+	AlertLevel string
+
+	// AlertKind is the check raising an Alert.
+	AlertKind string
+
+	// SectionKeywordser is implemented by referrers able to list the
+	// keywords applicable to a section. The validator uses it to report
+	// missing required keywords.
+	SectionKeywordser interface {
+		SectionKeywords(section string, sectionType string) []keywords.Keyword
+	}
+
+	// StrictKeyworder is implemented by referrers able to tell if the
+	// unknown keywords make the configuration invalid. The validator
+	// reports them as errors instead of warnings if StrictKeywords
+	// returns true.
+	StrictKeyworder interface {
+		StrictKeywords() bool
+	}
+)
+
+const (
+	AlertLevelWarn  AlertLevel = "warning"
+	AlertLevelError AlertLevel = "error"
+
+	AlertKindUnknownKeyword AlertKind = "unknown keyword"
+	AlertKindScoping        AlertKind = "scoping"
+	AlertKindReference      AlertKind = "reference"
+	AlertKindConvert        AlertKind = "value"
+	AlertKindCandidates     AlertKind = "candidates"
+	AlertKindRequired       AlertKind = "required"
+	AlertKindRequires       AlertKind = "requires"
+	AlertKindRequiresCycle  AlertKind = "requires cycle"
+	AlertKindDeprecated     AlertKind = "deprecated"
+	AlertKindExpression     AlertKind = "expression"
+)
+
+var (
+	// ErrInvalid is returned by Commit when the configuration has
+	// error alerts.
+	ErrInvalid = errors.New("invalid configuration")
+)
+
+func (t Alert) String() string {
+	return fmt.Sprintf("%s: %s: %s", t.Level, t.Key, t.Comment)
+}
+
+// HasError returns true if at least one alert has the error level.
+func (t Alerts) HasError() bool {
+	for _, a := range t {
+		if a.Level == AlertLevelError {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors returns the alerts with the error level.
+func (t Alerts) Errors() Alerts {
+	l := make(Alerts, 0)
+	for _, a := range t {
+		if a.Level == AlertLevelError {
+			l = append(l, a)
+		}
+	}
+	return l
+}
+
+func (t Alerts) String() string {
+	l := make([]string, len(t))
+	for i, a := range t {
+		l[i] = a.String()
+	}
+	return strings.Join(l, "\n")
+}
+
+func (t Alerts) Render() string {
+	return t.Tree().Render()
+}
+
+// Tree returns a tree loaded with the type instance.
+func (t Alerts) Tree() *tree.Tree {
+	tree := tree.New()
+	t.LoadTreeNode(tree.Head())
+	return tree
+}
+
+// LoadTreeNode add the tree nodes representing the type instance into another.
+func (t Alerts) LoadTreeNode(head *tree.Node) {
+	head.AddColumn().AddText("key").SetColor(rawconfig.Node.Color.Bold)
+	head.AddColumn().AddText("level").SetColor(rawconfig.Node.Color.Bold)
+	head.AddColumn().AddText("kind").SetColor(rawconfig.Node.Color.Bold)
+	head.AddColumn().AddText("comment").SetColor(rawconfig.Node.Color.Bold)
+	for _, a := range t {
+		n := head.AddNode()
+		a.LoadTreeNode(n)
+	}
+}
+
+// LoadTreeNode add the tree nodes representing the type instance into another.
+func (t Alert) LoadTreeNode(head *tree.Node) {
+	head.AddColumn().AddText(t.Key).SetColor(rawconfig.Node.Color.Primary)
+	switch t.Level {
+	case AlertLevelError:
+		head.AddColumn().AddText(string(t.Level)).SetColor(rawconfig.Node.Color.Error)
+	default:
+		head.AddColumn().AddText(string(t.Level)).SetColor(rawconfig.Node.Color.Warning)
+	}
+	head.AddColumn().AddText(string(t.Kind))
+	head.AddColumn().AddText(t.Comment)
+}
+
+func (t *Alerts) add(level AlertLevel, kind AlertKind, k key.T, format string, args ...interface{}) {
+	*t = append(*t, Alert{
+		Level:   level,
+		Kind:    kind,
+		Key:     k.String(),
+		Comment: fmt.Sprintf(format, args...),
+	})
+}
+
+// Validate returns the list of problems found in the configuration:
+// unknown keywords, scoped values of unscopable keywords, unresolvable
+// references, values not accepted by the keyword converter or not in
+// the keyword candidates, missing required keywords, and unknown or
+// cyclic resource ids in the *_requires keywords.
+func (t *T) Validate() (Alerts, error) {
+	alerts := make(Alerts, 0)
+	if t.Referrer == nil {
+		return alerts, nil
+	}
+	for _, section := range t.SectionStrings() {
+		sectionType := t.sectionType(key.New(section, ""))
+		m, _ := t.mergedSectionMap(section)
+		for _, option := range t.mergedKeyStrings(section) {
+			t.validateKey(&alerts, section, sectionType, option, m[option])
+		}
+		t.validateRequired(&alerts, section, sectionType)
+	}
+	t.validateRequiresCycles(&alerts)
+	return alerts, nil
+}
+
+// splitScope splits a <option>@<scope> option name.
+func splitScope(s string) (string, string) {
+	l := strings.SplitN(s, "@", 2)
+	if len(l) == 1 {
+		return l[0], ""
+	}
+	return l[0], l[1]
+}
+
+func (t *T) validateKey(alerts *Alerts, section, sectionType, option, value string) {
+	name, scope := splitScope(option)
+	k := key.New(section, name)
+	ks := key.New(section, option)
+	kw := t.Referrer.KeywordLookup(k, sectionType)
+	if kw.IsZero() {
+		level := AlertLevelWarn
+		if i, ok := t.Referrer.(StrictKeyworder); ok && i.StrictKeywords() {
+			level = AlertLevelError
+		}
+		alerts.add(level, AlertKindUnknownKeyword, ks, "keyword is not supported")
+		return
+	}
+	if scope != "" && !kw.Scopable {
+		alerts.add(AlertLevelError, AlertKindScoping, ks, "keyword is not scopable")
+	}
+	t.validateDeprecated(alerts, ks, name, value, kw)
+	if section == "data" {
+		// the keystore values are opaque, they may contain
+		// braces not meant as references.
+		return
+	}
+	v, ok := t.validateReferences(alerts, ks, section, value)
+	if !ok {
+		// the value can not be evaluated without the object
+		// resources, or has unresolvable references.
+		return
+	}
+	if strings.HasSuffix(name, "_requires") {
+		t.validateRequires(alerts, ks, v)
+	}
+	// Don't leak the values derived from sec keys in the alerts.
+	secret := v != t.redactedValue(section, value)
+	var converted interface{} = v
+	if kw.Converter != nil {
+		var err error
+		if converted, err = kw.Converter.Convert(v); err != nil {
+			if secret {
+				alerts.add(AlertLevelError, AlertKindConvert, ks, "invalid value derived from sec keys")
+			} else {
+				alerts.add(AlertLevelError, AlertKindConvert, ks, "%s", err)
+			}
+			return
+		}
+	}
+	if len(kw.Candidates) == 0 || v == "" {
+		return
+	}
+	var l []string
+	switch o := converted.(type) {
+	case []string:
+		l = o
+	default:
+		l = []string{v}
+	}
+	for _, e := range l {
+		switch {
+		case stringslice.Has(e, kw.Candidates):
+		case secret:
+			alerts.add(AlertLevelError, AlertKindCandidates, ks, "value derived from sec keys is not in %s", strings.Join(kw.Candidates, ","))
+		default:
+			alerts.add(AlertLevelError, AlertKindCandidates, ks, "%s is not in %s", e, strings.Join(kw.Candidates, ","))
+		}
+	}
+}
+
+// validateDeprecated reports the keys set with a keyword alias, the
+// deprecated keywords and the values written in a legacy format. These
+// are fixed by the config upgrade command, except the deprecated keywords.
+func (t *T) validateDeprecated(alerts *Alerts, k key.T, name, value string, kw keywords.Keyword) {
+	if kw.HasAlias(name) {
+		alerts.add(AlertLevelWarn, AlertKindDeprecated, k, "keyword name is deprecated, use %s", kw.Option)
+	}
+	switch {
+	case kw.Deprecated == "":
+	case kw.ReplacedBy != "":
+		alerts.add(AlertLevelWarn, AlertKindDeprecated, k, "keyword is deprecated since %s, use %s", kw.Deprecated, kw.ReplacedBy)
+	default:
+		alerts.add(AlertLevelWarn, AlertKindDeprecated, k, "keyword is deprecated since %s", kw.Deprecated)
+	}
+	if v := kw.UpgradeValue(value); v != value {
+		alerts.add(AlertLevelWarn, AlertKindDeprecated, k, "value format is deprecated, use %s", v)
+	}
+}
+
+// redactedValue returns the value with its references replaced, the sec
+// keys references replaced by RedactedValue.
+func (t *T) redactedValue(section, value string) string {
+	v, _ := t.replaceReferences(value, section, hostname.Hostname(), true)
+	return v
+}
+
+// validateReferences returns the value with its references replaced and
+// its expressions evaluated, and false if a reference can not be resolved
+// or an expression is invalid.
+func (t *T) validateReferences(alerts *Alerts, k key.T, section, value string) (string, bool) {
+	segments, err := expr.Split(value)
+	if err != nil {
+		alerts.add(AlertLevelError, AlertKindExpression, k, "%s", err)
+		return value, false
+	}
+	ok := true
+	impersonate := hostname.Hostname()
+	var b strings.Builder
+	for _, seg := range segments {
+		if seg.Expr == nil {
+			b.WriteString(t.validateTextReferences(alerts, k, section, seg.Text, impersonate, &ok))
+			continue
+		}
+		unresolved := false
+		s, err := expr.Eval(seg.Expr, func(name string) (string, error) {
+			ref := "{" + name + "}"
+			if strings.HasPrefix(ref, "{node.") && t.NodeReferrer == nil {
+				unresolved = true
+				return "", fmt.Errorf("%s can not be resolved without a node", ref)
+			}
+			s, err := t.dereference(ref, section, impersonate, false)
+			if _, postponed := err.(ErrPostponedRef); postponed {
+				unresolved = true
+			}
+			return s, err
+		})
+		switch {
+		case unresolved:
+			ok = false
+		case err != nil:
+			alerts.add(AlertLevelError, AlertKindExpression, k, "%s: %s", seg.Text, err)
+			ok = false
+		}
+		b.WriteString(s)
+	}
+	return b.String(), ok
+}
+
+func (t *T) validateTextReferences(alerts *Alerts, k key.T, section, value, impersonate string, ok *bool) string {
+	return rawconfig.RegexpReference.ReplaceAllStringFunc(value, func(ref string) string {
+		if strings.HasPrefix(ref, "{node.") && t.NodeReferrer == nil {
+			*ok = false
+			return ref
+		}
+		s, err := t.dereference(ref, section, impersonate, false)
+		switch err.(type) {
+		case nil:
+			return s
+		case ErrPostponedRef:
+		default:
+			alerts.add(AlertLevelError, AlertKindReference, k, "%s", err)
+		}
+		*ok = false
+		return ref
+	})
+}
+
+func (t *T) validateRequired(alerts *Alerts, section, sectionType string) {
+	i, ok := t.Referrer.(SectionKeywordser)
+	if !ok {
+		return
+	}
+	keys := t.mergedKeyStrings(section)
+	for _, kw := range i.SectionKeywords(section, sectionType) {
+		if !kw.Required || kw.Option == "type" {
+			continue
+		}
+		if t.hasScopedKey(keys, append([]string{kw.Option}, kw.Aliases...)...) {
+			continue
+		}
+		alerts.add(AlertLevelError, AlertKindRequired, key.New(section, kw.Option), "keyword is required")
+	}
+}
+
+// hasScopedKey returns true if a key of the list is one of the options,
+// scoped or not.
+func (t *T) hasScopedKey(l []string, options ...string) bool {
+	for _, s := range l {
+		if name, _ := splitScope(s); stringslice.Has(name, options) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *T) validateRequires(alerts *Alerts, k key.T, value string) {
+	for _, rid := range requiresRIDs(value) {
+		if !t.HasSectionString(rid) {
+			alerts.add(AlertLevelError, AlertKindRequires, k, "resource %s does not exist", rid)
+		}
+	}
+}
+
+func requiresRIDs(value string) []string {
+	l := make([]string, 0)
+	for rid := range resourcereqs.New(value).Requirements() {
+		l = append(l, rid)
+	}
+	sort.Strings(l)
+	return l
+}
+
+// validateRequiresCycles reports the cycles in the dependency graphs
+// described by each <action>_requires keyword.
+func (t *T) validateRequiresCycles(alerts *Alerts) {
+	graphs := make(map[string]map[string][]string)
+	sections := t.SectionStrings()
+	sort.Strings(sections)
+	for _, section := range sections {
+		m, _ := t.mergedSectionMap(section)
+		for _, option := range t.mergedKeyStrings(section) {
+			name, _ := splitScope(option)
+			if !strings.HasSuffix(name, "_requires") {
+				continue
+			}
+			if _, ok := graphs[name]; !ok {
+				graphs[name] = make(map[string][]string)
+			}
+			graphs[name][section] = append(graphs[name][section], requiresRIDs(m[option])...)
+		}
+	}
+	options := make([]string, 0, len(graphs))
+	for option := range graphs {
+		options = append(options, option)
+	}
+	sort.Strings(options)
+	for _, option := range options {
+		for _, cycle := range requiresCycles(graphs[option]) {
+			alerts.add(AlertLevelError, AlertKindRequiresCycle, key.New(cycle[0], option), "%s", strings.Join(cycle, " -> "))
+		}
+	}
+}
+
+// requiresCycles returns the cycles found in the graph, each cycle
+// starting and ending with the same node.
+func requiresCycles(graph map[string][]string) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	cycles := make([][]string, 0)
+	state := make(map[string]int)
+	stack := make([]string, 0)
+	var visit func(string)
+	visit = func(n string) {
+		state[n] = visiting
+		stack = append(stack, n)
+		for _, m := range graph[n] {
+			switch state[m] {
+			case unvisited:
+				visit(m)
+			case visiting:
+				for i, e := range stack {
+					if e == m {
+						cycle := append([]string{}, stack[i:]...)
+						cycles = append(cycles, append(cycle, m))
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[n] = visited
+	}
+	nodes := make([]string, 0, len(graph))
+	for n := range graph {
+		nodes = append(nodes, n)
+	}
+	sort.Strings(nodes)
+	for _, n := range nodes {
+		if state[n] == unvisited {
+			visit(n)
+		}
+	}
+	return cycles
+}
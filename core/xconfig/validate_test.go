@@ -0,0 +1,223 @@
+package xconfig
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/keyop"
+	"opensvc.com/opensvc/util/key"
+)
+
+func TestRequiresCycles(t *testing.T) {
+	cases := map[string]struct {
+		graph  map[string][]string
+		cycles [][]string
+	}{
+		"none": {
+			graph: map[string][]string{
+				"app#1": {"fs#1", "ip#1"},
+				"fs#1":  {"disk#1"},
+			},
+			cycles: [][]string{},
+		},
+		"self": {
+			graph: map[string][]string{
+				"app#1": {"app#1"},
+			},
+			cycles: [][]string{{"app#1", "app#1"}},
+		},
+		"loop": {
+			graph: map[string][]string{
+				"app#1":  {"fs#1"},
+				"fs#1":   {"disk#1"},
+				"disk#1": {"app#1"},
+			},
+			cycles: [][]string{{"app#1", "fs#1", "disk#1", "app#1"}},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.cycles, requiresCycles(c.graph))
+		})
+	}
+}
+
+func TestRequiresRIDs(t *testing.T) {
+	assert.Equal(t, []string{"fs#0", "ip#0"}, requiresRIDs("ip#0 fs#0(down,stdby down)"))
+	assert.Equal(t, []string{}, requiresRIDs(""))
+}
+
+func TestAlerts(t *testing.T) {
+	alerts := Alerts{
+		{Level: AlertLevelWarn, Kind: AlertKindUnknownKeyword, Key: "fs#1.foo"},
+		{Level: AlertLevelError, Kind: AlertKindCandidates, Key: "topology", Comment: "foo is not in failover,flex"},
+	}
+	assert.True(t, alerts.HasError())
+	assert.Len(t, alerts.Errors(), 1)
+	assert.Equal(t, "error: topology: foo is not in failover,flex", alerts.Errors().String())
+	assert.False(t, alerts[:1].HasError())
+}
+
+func TestExpressions(t *testing.T) {
+	cfg := newTestConfig(t, `
+[DEFAULT]
+topology = flex
+flex_max = 4
+flex_min = $(({flex_max} / 2))
+flex_min@n2 = {nodename == "n2" ? flex_max - 1 : 1}
+orchestrate = $((1 +))
+
+[app#1]
+disable = {len(nodename) / 0 > 1}
+start = sh -c "{ true && echo ok; }" && awk '{print $1}' /etc/hosts
+`)
+	k := key.New("DEFAULT", "flex_min")
+	v, err := cfg.EvalAs(k, "n1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, v)
+	v, err = cfg.EvalAs(k, "n2")
+	require.NoError(t, err)
+	assert.Equal(t, 3, v)
+
+	_, err = cfg.EvalAs(key.New("DEFAULT", "orchestrate"), "n1")
+	assert.Error(t, err)
+
+	v, err = cfg.EvalAs(key.New("app#1", "start"), "n1")
+	require.NoError(t, err, "shell brace groups are not expressions")
+	assert.Equal(t, `sh -c "{ true && echo ok; }" && awk '{print $1}' /etc/hosts`, v)
+
+	alerts, err := cfg.Validate()
+	require.NoError(t, err)
+	errs := alerts.Errors()
+	require.Len(t, errs, 2)
+	assert.Equal(t, AlertKindExpression, errs[0].Kind)
+	assert.Equal(t, "orchestrate", errs[0].Key)
+	assert.Equal(t, AlertKindExpression, errs[1].Kind)
+	assert.Equal(t, "app#1.disable", errs[1].Key)
+	assert.Contains(t, errs[1].Comment, "division by zero")
+}
+
+func TestKeystoreDataNotValidated(t *testing.T) {
+	cfg := newTestConfig(t, `
+[data]
+nginx.conf = server_name {hostname_tpl}; $((1 +))
+`)
+	alerts, err := cfg.Validate()
+	require.NoError(t, err)
+	assert.False(t, alerts.HasError(), "%s", alerts)
+}
+
+func TestCommitRollback(t *testing.T) {
+	cfg := newTestConfig(t, `
+[DEFAULT]
+flex_max = 4
+`)
+	err := cfg.Set(keyop.T{Key: key.Parse("flex_max"), Op: keyop.Set, Value: "four"})
+	require.NoError(t, err)
+	err = cfg.Commit()
+	assert.Equal(t, ErrInvalid, errors.Cause(err), "invalid value refused")
+	assert.Equal(t, 4, cfg.GetInt(key.Parse("flex_max")), "refused change rolled back")
+
+	err = cfg.Set(keyop.T{Key: key.Parse("flex_min"), Op: keyop.Set, Value: "2"})
+	require.NoError(t, err)
+	require.NoError(t, cfg.Commit(), "next commit not affected by the refused one")
+
+	err = cfg.Set(keyop.T{Key: key.Parse("flex_max"), Op: keyop.Set, Value: "x"})
+	require.NoError(t, err)
+	require.Error(t, cfg.Commit())
+	assert.Equal(t, 2, cfg.GetInt(key.Parse("flex_min")), "rolled back to the last commit")
+	assert.Equal(t, 4, cfg.GetInt(key.Parse("flex_max")))
+}
+
+func TestValidateCommit(t *testing.T) {
+	type alert struct {
+		level AlertLevel
+		kind  AlertKind
+		key   string
+	}
+	cases := map[string]struct {
+		config string
+		alerts []alert
+	}{
+		"valid": {
+			config: `
+[DEFAULT]
+topology = flex
+flex_min = 1
+
+[fs#1]
+mnt = /srv/{name}
+type = xfs
+size = 1G
+`,
+			alerts: []alert{},
+		},
+		"unknown keyword": {
+			config: `
+[DEFAULT]
+foo = bar
+`,
+			alerts: []alert{{AlertLevelWarn, AlertKindUnknownKeyword, "foo"}},
+		},
+		"converter error": {
+			config: `
+[DEFAULT]
+flex_max = many
+`,
+			alerts: []alert{{AlertLevelError, AlertKindConvert, "flex_max"}},
+		},
+		"not in candidates": {
+			config: `
+[fs#1]
+mnt = /srv
+type = zfs
+`,
+			alerts: []alert{{AlertLevelError, AlertKindCandidates, "fs#1.type"}},
+		},
+		"required": {
+			config: `
+[fs#1]
+type = xfs
+`,
+			alerts: []alert{{AlertLevelError, AlertKindRequired, "fs#1.mnt"}},
+		},
+		"scoped unscopable": {
+			config: `
+[DEFAULT]
+flex_max@n1 = 2
+flex_min@n1 = 1
+`,
+			alerts: []alert{{AlertLevelError, AlertKindScoping, "flex_max@n1"}},
+		},
+		"unresolved reference": {
+			config: `
+[fs#1]
+mnt = /srv/{nope}
+`,
+			alerts: []alert{{AlertLevelError, AlertKindReference, "fs#1.mnt"}},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := newTestConfig(t, c.config)
+			alerts, err := cfg.Validate()
+			require.NoError(t, err)
+			l := make([]alert, len(alerts))
+			for i, a := range alerts {
+				l[i] = alert{a.Level, a.Kind, a.Key}
+			}
+			assert.Equal(t, c.alerts, l)
+
+			err = cfg.Commit()
+			if alerts.HasError() {
+				assert.Equal(t, ErrInvalid, errors.Cause(err), "invalid commit refused")
+				assert.Contains(t, err.Error(), c.alerts[0].key)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, cfg.CommitInvalid(), "commit without validation")
+		})
+	}
+}
@@ -0,0 +1,263 @@
+// Package keystorevault is the keystore backend driver resolving
+// references to values stored in a HashiCorp Vault compatible KV version 2
+// secrets engine.
+//
+// A <backend>://<mount>/<path>#<field> reference is read from
+// <url>/v1/<mount>/data/<path>. If field is empty, the whole data map is
+// returned in json format.
+//
+// The Vault token is read from the token_key key of the sec object set by
+// the sec keyword, or obtained by an AppRole login with the role_id_key
+// and secret_id_key keys of this sec object.
+package keystorevault
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/keystorebackend"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	T struct {
+		keystorebackend.T
+	}
+
+	// client is a minimal Vault http api client.
+	client struct {
+		URL        string
+		Namespace  string
+		HTTPClient *http.Client
+	}
+
+	tokenEntry struct {
+		token   string
+		expires time.Time
+	}
+
+	loginResponse struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}
+
+	kvResponse struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+
+	errorResponse struct {
+		Errors []string `json:"errors"`
+	}
+)
+
+const (
+	authToken   = "token"
+	authAppRole = "approle"
+)
+
+var (
+	// tokens caches the AppRole login tokens, indexed by backend name.
+	tokens   = make(map[string]tokenEntry)
+	tokensMu sync.Mutex
+
+	httpClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+func init() {
+	keystorebackend.Register("vault", NewBackender)
+}
+
+func NewBackender() keystorebackend.Backender {
+	t := New()
+	var i interface{} = t
+	return i.(keystorebackend.Backender)
+}
+
+func New() *T {
+	t := T{}
+	return &t
+}
+
+// Get returns the value referenced by ref.
+func (t *T) Get(ref keystorebackend.Ref) ([]byte, error) {
+	c := t.client()
+	if c.URL == "" {
+		return nil, fmt.Errorf("keystore backend %s: url is not set", t.Name())
+	}
+	token, err := t.token(c)
+	if err != nil {
+		return nil, errors.Wrapf(err, "keystore backend %s: auth", t.Name())
+	}
+	b, err := c.read(token, ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "keystore backend %s: read %s", t.Name(), ref)
+	}
+	return b, nil
+}
+
+func (t *T) client() client {
+	return client{
+		URL:        strings.TrimSuffix(t.GetString("url"), "/"),
+		Namespace:  t.GetString("namespace"),
+		HTTPClient: httpClient,
+	}
+}
+
+func (t *T) secretKey(option string) ([]byte, error) {
+	s := t.GetString("sec")
+	if s == "" {
+		return nil, fmt.Errorf("the sec keyword is not set")
+	}
+	p, err := path.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	k := t.GetString(option)
+	b, err := t.DecodeSecret(p, k)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s key %s", p, k)
+	}
+	return bytes.TrimSpace(b), nil
+}
+
+func (t *T) token(c client) (string, error) {
+	switch auth := t.GetString("auth"); auth {
+	case "", authToken:
+		b, err := t.secretKey("token_key")
+		return string(b), err
+	case authAppRole:
+		return t.appRoleToken(c)
+	default:
+		return "", fmt.Errorf("unsupported auth method '%s'", auth)
+	}
+}
+
+func (t *T) appRoleToken(c client) (string, error) {
+	tokensMu.Lock()
+	defer tokensMu.Unlock()
+	if e, ok := tokens[t.Name()]; ok && time.Now().Before(e.expires) {
+		return e.token, nil
+	}
+	roleID, err := t.secretKey("role_id_key")
+	if err != nil {
+		return "", err
+	}
+	secretID, err := t.secretKey("secret_id_key")
+	if err != nil {
+		return "", err
+	}
+	token, ttl, err := c.login(t.GetString("approle_mount"), string(roleID), string(secretID))
+	if err != nil {
+		return "", err
+	}
+	tokens[t.Name()] = tokenEntry{
+		token: token,
+		// renew before the lease expires
+		expires: time.Now().Add(ttl * 9 / 10),
+	}
+	return token, nil
+}
+
+func (c client) do(method, uri, token string, body interface{}, data interface{}) error {
+	var r *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	} else {
+		r = bytes.NewReader([]byte{})
+	}
+	req, err := http.NewRequest(method, c.URL+uri, r)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if c.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.Namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e errorResponse
+		if err := json.Unmarshal(b, &e); err == nil && len(e.Errors) > 0 {
+			return fmt.Errorf("%s %s: %s: %s", method, uri, resp.Status, strings.Join(e.Errors, ", "))
+		}
+		return fmt.Errorf("%s %s: %s", method, uri, resp.Status)
+	}
+	return json.Unmarshal(b, data)
+}
+
+// login authenticates with the AppRole method and returns the client
+// token and its lease duration.
+func (c client) login(mount, roleID, secretID string) (string, time.Duration, error) {
+	if mount == "" {
+		mount = authAppRole
+	}
+	var data loginResponse
+	body := map[string]string{
+		"role_id":   roleID,
+		"secret_id": secretID,
+	}
+	if err := c.do("POST", "/v1/auth/"+mount+"/login", "", body, &data); err != nil {
+		return "", 0, err
+	}
+	if data.Auth.ClientToken == "" {
+		return "", 0, fmt.Errorf("approle login: no client token in response")
+	}
+	return data.Auth.ClientToken, time.Duration(data.Auth.LeaseDuration) * time.Second, nil
+}
+
+// dataURI returns the /v1/<mount>/data/<path> uri of the KV version 2
+// secret referenced by ref, with each path element escaped.
+func dataURI(ref keystorebackend.Ref) string {
+	l := []string{"", "v1", url.PathEscape(ref.Mount), "data"}
+	for _, e := range ref.Segments() {
+		l = append(l, url.PathEscape(e))
+	}
+	return strings.Join(l, "/")
+}
+
+// read returns the value of the referenced field of a KV version 2 secret.
+func (c client) read(token string, ref keystorebackend.Ref) ([]byte, error) {
+	var data kvResponse
+	if err := c.do("GET", dataURI(ref), token, nil, &data); err != nil {
+		return nil, err
+	}
+	if ref.Field == "" {
+		return json.Marshal(data.Data.Data)
+	}
+	v, ok := data.Data.Data[ref.Field]
+	if !ok {
+		return nil, fmt.Errorf("field %s not found", ref.Field)
+	}
+	if s, ok := v.(string); ok {
+		return []byte(s), nil
+	}
+	return json.Marshal(v)
+}
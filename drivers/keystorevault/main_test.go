@@ -0,0 +1,83 @@
+package keystorevault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/keystorebackend"
+)
+
+func newStubServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/auth/approle/login", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body["role_id"] != "r1" || body["secret_id"] != "s1" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":["invalid role or secret ID"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"t1","lease_duration":3600}}`))
+	})
+	mux.HandleFunc("/v1/secret/data/app/db", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "t1" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"data":{"password":"p4ss","port":5432},"metadata":{"version":3}}}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestClientLogin(t *testing.T) {
+	srv := newStubServer(t)
+	defer srv.Close()
+	c := client{URL: srv.URL, HTTPClient: srv.Client()}
+
+	token, ttl, err := c.login("", "r1", "s1")
+	require.NoError(t, err)
+	assert.Equal(t, "t1", token)
+	assert.Equal(t, time.Hour, ttl)
+
+	_, _, err = c.login("approle", "r1", "bad")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid role or secret ID")
+}
+
+func TestDataURI(t *testing.T) {
+	ref := keystorebackend.Ref{Backend: "vault", Mount: "secret", Path: "app 1/db?x#y", Field: "pw"}
+	assert.Equal(t, "/v1/secret/data/app%201/db%3Fx%23y", dataURI(ref))
+}
+
+func TestClientRead(t *testing.T) {
+	srv := newStubServer(t)
+	defer srv.Close()
+	c := client{URL: srv.URL, HTTPClient: srv.Client()}
+
+	cases := map[string]string{
+		"vault://secret/app/db#password": "p4ss",
+		"vault://secret/app/db#port":     "5432",
+		"vault://secret/app/db":          `{"password":"p4ss","port":5432}`,
+	}
+	for s, expected := range cases {
+		ref, err := keystorebackend.ParseRef(s)
+		require.NoError(t, err)
+		b, err := c.read("t1", ref)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, string(b), s)
+	}
+
+	ref, _ := keystorebackend.ParseRef("vault://secret/app/db#user")
+	_, err := c.read("t1", ref)
+	assert.Error(t, err, "missing field")
+
+	ref, _ = keystorebackend.ParseRef("vault://secret/app/db#password")
+	_, err = c.read("bad", ref)
+	assert.Error(t, err, "bad token")
+}
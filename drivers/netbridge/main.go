@@ -0,0 +1,67 @@
+package netbridge
+
+import (
+	"opensvc.com/opensvc/core/network"
+)
+
+type (
+	T struct {
+		network.T
+	}
+)
+
+func init() {
+	network.Register("bridge", NewNetworker)
+}
+
+func NewNetworker() network.Networker {
+	t := New()
+	var i interface{} = t
+	return i.(network.Networker)
+}
+
+func New() *T {
+	t := T{}
+	return &t
+}
+
+// BridgeName returns the name of the bridge interface hosting the
+// network backend addresses. The name is truncated to respect the
+// interface name length limit.
+func BridgeName(name string) string {
+	s := "obr_" + name
+	if len(s) > 15 {
+		s = s[:15]
+	}
+	return s
+}
+
+func (t T) CNIConfigData() (interface{}, error) {
+	m := map[string]interface{}{
+		"cniVersion": network.CNIVersion,
+		"name":       t.Name(),
+		"plugins": []map[string]interface{}{
+			{
+				"type":      "bridge",
+				"bridge":    BridgeName(t.Name()),
+				"isGateway": true,
+				"ipMasq":    true,
+				"ipam": map[string]interface{}{
+					"type":   "host-local",
+					"subnet": t.Network(),
+					"routes": []map[string]interface{}{
+						{"dst": "0.0.0.0/0"},
+					},
+				},
+			},
+			{
+				"type": "portmap",
+				"capabilities": map[string]bool{
+					"portMappings": true,
+				},
+				"snat": true,
+			},
+		},
+	}
+	return m, nil
+}
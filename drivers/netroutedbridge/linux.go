@@ -0,0 +1,139 @@
+// +build linux
+
+package netroutedbridge
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/key"
+)
+
+const (
+	rtTablesFile = "/etc/iproute2/rt_tables"
+)
+
+// Setup installs the routes to the backend subnets of the peer nodes.
+func (t *T) Setup() error {
+	localname := hostname.Hostname()
+	tables, err := t.tables()
+	if err != nil {
+		return err
+	}
+	for _, nodename := range t.nodes() {
+		if nodename == localname {
+			continue
+		}
+		if err := t.setupPeerRoutes(nodename, tables); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *T) setupPeerRoutes(nodename string, tables []int) error {
+	subnet, err := t.NodeSubnet(nodename)
+	if err != nil {
+		return err
+	}
+	gw, err := t.peerGateway(nodename)
+	if err != nil {
+		return err
+	}
+	switch t.GetString("tunnel") {
+	case "always":
+		return fmt.Errorf("network %s: tunnel=always is not supported by this agent", t.Name())
+	}
+	for _, table := range tables {
+		route := netlink.Route{
+			Dst:   subnet,
+			Gw:    gw,
+			Table: table,
+		}
+		if err := netlink.RouteReplace(&route); err != nil {
+			return errors.Wrapf(err, "network %s: route %s via %s table %d", t.Name(), subnet, gw, table)
+		}
+	}
+	return nil
+}
+
+// peerGateway returns the gateway to use to reach the backend subnet of
+// <nodename>: the gateway keyword value scoped for this node, or the node
+// address.
+func (t *T) peerGateway(nodename string) (net.IP, error) {
+	k := key.New("network#"+t.Name(), "gateway")
+	if v, err := t.Config().EvalAs(k, nodename); err == nil {
+		if s, ok := v.(string); ok && s != "" {
+			if ip := net.ParseIP(s); ip != nil {
+				return ip, nil
+			}
+			return nil, fmt.Errorf("network %s: invalid gateway %s@%s", t.Name(), s, nodename)
+		}
+	}
+	k = key.New("network#"+t.Name(), "addr")
+	if v, err := t.Config().EvalAs(k, nodename); err == nil {
+		if s, ok := v.(string); ok && s != "" {
+			nodename = s
+		}
+	}
+	l, err := net.LookupIP(nodename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "network %s: resolve node %s address", t.Name(), nodename)
+	}
+	for _, ip := range l {
+		if ip.To4() != nil && !ip.IsLoopback() {
+			return ip, nil
+		}
+	}
+	return nil, fmt.Errorf("network %s: no usable ipv4 address for node %s", t.Name(), nodename)
+}
+
+func (t *T) tables() ([]int, error) {
+	l := make([]int, 0)
+	names := t.GetSlice("tables")
+	if len(names) == 0 {
+		names = []string{"main"}
+	}
+	for _, name := range names {
+		i, err := tableID(name)
+		if err != nil {
+			return l, errors.Wrapf(err, "network %s", t.Name())
+		}
+		l = append(l, i)
+	}
+	return l, nil
+}
+
+func tableID(name string) (int, error) {
+	if i, err := strconv.Atoi(name); err == nil {
+		return i, nil
+	}
+	f, err := os.Open(rtTablesFile)
+	if err != nil {
+		if name == "main" {
+			return 254, nil
+		}
+		return 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		l := strings.Fields(line)
+		if len(l) < 2 || l[1] != name {
+			continue
+		}
+		return strconv.Atoi(l[0])
+	}
+	return 0, fmt.Errorf("routing table %s not found in %s", name, rtTablesFile)
+}
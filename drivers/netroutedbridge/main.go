@@ -0,0 +1,127 @@
+package netroutedbridge
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"net"
+
+	"opensvc.com/opensvc/core/network"
+	"opensvc.com/opensvc/drivers/netbridge"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/key"
+)
+
+type (
+	T struct {
+		network.T
+	}
+)
+
+func init() {
+	network.Register("routed_bridge", NewNetworker)
+}
+
+func NewNetworker() network.Networker {
+	t := New()
+	var i interface{} = t
+	return i.(network.Networker)
+}
+
+func New() *T {
+	t := T{}
+	return &t
+}
+
+func (t T) CNIConfigData() (interface{}, error) {
+	subnet, err := t.NodeSubnet(hostname.Hostname())
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{
+		"cniVersion": network.CNIVersion,
+		"name":       t.Name(),
+		"plugins": []map[string]interface{}{
+			{
+				"type":      "bridge",
+				"bridge":    netbridge.BridgeName(t.Name()),
+				"isGateway": true,
+				"ipMasq":    false,
+				"ipam": map[string]interface{}{
+					"type":   "host-local",
+					"subnet": subnet.String(),
+					"routes": []map[string]interface{}{
+						{"dst": "0.0.0.0/0"},
+					},
+				},
+			},
+			{
+				"type": "portmap",
+				"capabilities": map[string]bool{
+					"portMappings": true,
+				},
+				"snat": true,
+			},
+		},
+	}
+	return m, nil
+}
+
+func (t *T) nodes() []string {
+	l := t.Config().GetSlice(key.Parse("cluster.nodes"))
+	if len(l) == 0 {
+		return []string{hostname.Hostname()}
+	}
+	return l
+}
+
+// NodeSubnet returns the subnet handled by <nodename>. The subnet is
+// either set by the scoped subnet keyword, or allocated from the network
+// based on the node index in cluster.nodes.
+func (t *T) NodeSubnet(nodename string) (*net.IPNet, error) {
+	k := key.New("network#"+t.Name(), "subnet")
+	if v, err := t.Config().EvalAs(k, nodename); err == nil {
+		if s, ok := v.(string); ok && s != "" {
+			_, subnet, err := net.ParseCIDR(s)
+			return subnet, err
+		}
+	}
+	for i, n := range t.nodes() {
+		if n == nodename {
+			return AllocSubnet(t.Network(), t.GetInt("ips_per_node"), i)
+		}
+	}
+	return nil, fmt.Errorf("network %s: node %s is not a cluster node", t.Name(), nodename)
+}
+
+// AllocSubnet returns the <index>th subnet of <ipsPerNode> addresses in
+// the <network> cidr. <ipsPerNode> is rounded to the next power of two.
+func AllocSubnet(network string, ipsPerNode int, index int) (*net.IPNet, error) {
+	_, ipnet, err := net.ParseCIDR(network)
+	if err != nil {
+		return nil, err
+	}
+	ip4 := ipnet.IP.To4()
+	if ip4 == nil {
+		return nil, fmt.Errorf("network %s: only ipv4 networks are supported", network)
+	}
+	if ipsPerNode <= 0 {
+		return nil, fmt.Errorf("network %s: invalid ips per node: %d", network, ipsPerNode)
+	}
+	hostBits := bits.Len(uint(ipsPerNode - 1))
+	ones, size := ipnet.Mask.Size()
+	if size-hostBits < ones {
+		return nil, fmt.Errorf("network %s is too small for %d ips per node", network, ipsPerNode)
+	}
+	if uint64(index) >= uint64(1)<<uint(size-hostBits-ones) {
+		return nil, fmt.Errorf("network %s is too small for %d nodes with %d ips per node", network, index+1, ipsPerNode)
+	}
+	base := binary.BigEndian.Uint32(ip4)
+	base += uint32(index) << uint(hostBits)
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, base)
+	return &net.IPNet{
+		IP:   ip,
+		Mask: net.CIDRMask(size-hostBits, size),
+	}, nil
+}
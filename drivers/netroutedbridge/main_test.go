@@ -0,0 +1,38 @@
+package netroutedbridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocSubnet(t *testing.T) {
+	cases := []struct {
+		network    string
+		ipsPerNode int
+		index      int
+		expected   string
+	}{
+		{"10.22.0.0/16", 1024, 0, "10.22.0.0/22"},
+		{"10.22.0.0/16", 1024, 1, "10.22.4.0/22"},
+		{"10.22.0.0/16", 1000, 2, "10.22.8.0/22"},
+		{"10.22.0.0/16", 256, 3, "10.22.3.0/24"},
+	}
+	for _, c := range cases {
+		subnet, err := AllocSubnet(c.network, c.ipsPerNode, c.index)
+		require.NoError(t, err)
+		assert.Equal(t, c.expected, subnet.String())
+	}
+}
+
+func TestAllocSubnetErrors(t *testing.T) {
+	_, err := AllocSubnet("10.22.0.0/24", 1024, 0)
+	assert.Error(t, err, "network too small for ips per node")
+
+	_, err = AllocSubnet("10.22.0.0/22", 512, 2)
+	assert.Error(t, err, "network too small for node index")
+
+	_, err = AllocSubnet("fd00::/64", 1024, 0)
+	assert.Error(t, err, "ipv6 network")
+}
@@ -0,0 +1,193 @@
+package pooldirectory
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"opensvc.com/opensvc/core/pool"
+	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/file"
+	"opensvc.com/opensvc/util/reflink"
+)
+
+type (
+	// snapshots implements the snapshot operations on the volume
+	// directories hosted in the pool head directory.
+	snapshots string
+)
+
+// CreateSnapshot copies the volume directory to the pool snapshots
+// directory, using reflink clones if the filesystem supports them.
+func (t T) CreateSnapshot(volume string, name string) error {
+	return snapshots(t.path()).create(volume, name)
+}
+
+func (t T) DeleteSnapshot(volume string, name string) error {
+	return snapshots(t.path()).delete(volume, name)
+}
+
+// RollbackSnapshot replaces the volume directory by a copy of the
+// snapshot directory. The snapshot is kept.
+func (t T) RollbackSnapshot(volume string, name string) error {
+	return snapshots(t.path()).rollback(volume, name)
+}
+
+func (t T) Snapshots(volume string) (pool.SnapshotList, error) {
+	return snapshots(t.path()).list(volume)
+}
+
+// CloneSnapshot copies the snapshot directory to the clone volume
+// directory.
+func (t T) CloneSnapshot(volume string, name string, clone string) error {
+	return snapshots(t.path()).clone(volume, name, clone)
+}
+
+func (t snapshots) volumeDir(volume string) string {
+	return filepath.Join(string(t), volume)
+}
+
+func (t snapshots) snapshotsDir(volume string) string {
+	return filepath.Join(string(t), ".snapshots", volume)
+}
+
+func (t snapshots) snapshotDir(volume string, name string) string {
+	return filepath.Join(t.snapshotsDir(volume), name)
+}
+
+func (t snapshots) create(volume string, name string) error {
+	if err := pool.ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	src := t.volumeDir(volume)
+	dst := t.snapshotDir(volume, name)
+	if !file.ExistsAndDir(src) {
+		return fmt.Errorf("volume directory %s does not exist", src)
+	}
+	if file.Exists(dst) {
+		return fmt.Errorf("snapshot %s of %s already exists", name, volume)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+	if err := t.copyTree(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return err
+	}
+	now := time.Now()
+	return os.Chtimes(dst, now, now)
+}
+
+func (t snapshots) delete(volume string, name string) error {
+	if err := pool.ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	p := t.snapshotDir(volume, name)
+	if !file.ExistsAndDir(p) {
+		return fmt.Errorf("snapshot %s of %s does not exist", name, volume)
+	}
+	return os.RemoveAll(p)
+}
+
+func (t snapshots) rollback(volume string, name string) error {
+	if err := pool.ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	src := t.snapshotDir(volume, name)
+	dst := t.volumeDir(volume)
+	if !file.ExistsAndDir(src) {
+		return fmt.Errorf("snapshot %s of %s does not exist", name, volume)
+	}
+	tmp := dst + ".rollback"
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	if err := t.copyTree(src, tmp); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	old := dst + ".old"
+	if err := os.Rename(dst, old); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Rename(old, dst)
+		return err
+	}
+	return os.RemoveAll(old)
+}
+
+func (t snapshots) list(volume string) (pool.SnapshotList, error) {
+	l := make(pool.SnapshotList, 0)
+	entries, err := ioutil.ReadDir(t.snapshotsDir(volume))
+	switch {
+	case os.IsNotExist(err):
+		return l, nil
+	case err != nil:
+		return l, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		l = append(l, pool.Snapshot{
+			Name:    e.Name(),
+			Volume:  volume,
+			Created: e.ModTime(),
+		})
+	}
+	return l, nil
+}
+
+func (t snapshots) clone(volume string, name string, clone string) error {
+	if err := pool.ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	src := t.snapshotDir(volume, name)
+	dst := t.volumeDir(clone)
+	if !file.ExistsAndDir(src) {
+		return fmt.Errorf("snapshot %s of %s does not exist", name, volume)
+	}
+	if file.Exists(dst) {
+		return fmt.Errorf("volume directory %s already exists", dst)
+	}
+	if err := t.copyTree(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return err
+	}
+	return nil
+}
+
+// copyTree copies the src directory to the non-existing dst directory,
+// with reflink clones of the files if supported, with rsync otherwise,
+// or with cp if rsync is not installed.
+func (t snapshots) copyTree(src string, dst string) error {
+	var (
+		name string
+		args []string
+	)
+	if reflink.IsCapable(string(t)) {
+		name = "cp"
+		args = []string{"-a", "--reflink=always", src, dst}
+	} else if _, err := exec.LookPath("rsync"); err == nil {
+		name = "rsync"
+		args = []string{"-aHAX", src + "/", dst + "/"}
+	} else {
+		name = "cp"
+		args = []string{"-a", src, dst}
+	}
+	cmd := command.New(
+		command.WithName(name),
+		command.WithArgs(args),
+		command.WithLogger(&log.Logger),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	return cmd.Run()
+}
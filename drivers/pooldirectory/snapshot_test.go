@@ -0,0 +1,73 @@
+package pooldirectory
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshots(t *testing.T) {
+	head, err := ioutil.TempDir("", "pooldirectory")
+	require.NoError(t, err)
+	defer os.RemoveAll(head)
+	s := snapshots(head)
+	dataFile := filepath.Join(s.volumeDir("v1"), "data")
+	read := func(p string) string {
+		b, err := ioutil.ReadFile(p)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	require.NoError(t, os.MkdirAll(s.volumeDir("v1"), 0755))
+	require.NoError(t, ioutil.WriteFile(dataFile, []byte("before"), 0644))
+
+	t.Run("create", func(t *testing.T) {
+		assert.Error(t, s.create("v1", "-bad"), "invalid name")
+		assert.Error(t, s.create("v2", "s1"), "missing volume")
+		require.NoError(t, s.create("v1", "s1"))
+		assert.Error(t, s.create("v1", "s1"), "duplicate")
+		l, err := s.list("v1")
+		require.NoError(t, err)
+		assert.Len(t, l, 1)
+		assert.True(t, l.Has("s1"))
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(dataFile, []byte("after"), 0644))
+		require.NoError(t, s.rollback("v1", "s1"))
+		assert.Equal(t, "before", read(dataFile))
+		l, err := s.list("v1")
+		require.NoError(t, err)
+		assert.True(t, l.Has("s1"), "the snapshot is kept")
+	})
+
+	t.Run("clone", func(t *testing.T) {
+		require.NoError(t, s.clone("v1", "s1", "v3"))
+		assert.Equal(t, "before", read(filepath.Join(s.volumeDir("v3"), "data")))
+		assert.Error(t, s.clone("v1", "s1", "v3"), "existing clone")
+	})
+
+	t.Run("invalid names", func(t *testing.T) {
+		require.NoError(t, os.MkdirAll(s.volumeDir("v2"), 0755))
+		for _, name := range []string{"", "../x", "../../v2", "."} {
+			assert.Error(t, s.delete("v1", name), name)
+			assert.Error(t, s.rollback("v1", name), name)
+			assert.Error(t, s.clone("v1", name, "v4"), name)
+		}
+		assert.DirExists(t, s.snapshotDir("v1", "s1"), "snapshots are kept")
+		assert.DirExists(t, s.volumeDir("v2"), "other volumes are kept")
+		assert.NoDirExists(t, s.volumeDir("v4"))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, s.delete("v1", "s1"))
+		assert.Error(t, s.delete("v1", "s1"))
+		l, err := s.list("v1")
+		require.NoError(t, err)
+		assert.Len(t, l, 0)
+	})
+}
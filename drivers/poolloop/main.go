@@ -0,0 +1,203 @@
+// +build linux
+
+package poolloop
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"opensvc.com/opensvc/core/pool"
+	"opensvc.com/opensvc/util/df"
+	"opensvc.com/opensvc/util/reflink"
+	"opensvc.com/opensvc/util/sizeconv"
+)
+
+var (
+	// reflinkCapable caches the reflink capability, indexed by pool path.
+	reflinkCapable   = make(map[string]bool)
+	reflinkCapableMu sync.Mutex
+
+	reflinkIsCapable = reflink.IsCapable
+)
+
+type (
+	T struct {
+		pool.T
+	}
+
+	// settings are the pool keywords values used by the translators.
+	settings struct {
+		Path        string
+		FSType      string
+		Sparse      bool
+		MKFSOptions []string
+		MntOptions  string
+	}
+
+	// imagesUsage is the space accounting of the pool loop files.
+	imagesUsage struct {
+		// Apparent is the sum of the loop files sizes, in bytes.
+		Apparent int64
+		// Allocated is the sum of the loop files allocated blocks, in bytes.
+		Allocated int64
+	}
+)
+
+func init() {
+	pool.Register("loop", NewPooler)
+}
+
+func NewPooler() pool.Pooler {
+	t := New()
+	var i interface{} = t
+	return i.(pool.Pooler)
+}
+
+func New() *T {
+	t := T{}
+	return &t
+}
+
+func (t T) Head() string {
+	return t.path()
+}
+
+// Capabilities advertises the snap capability if the filesystem hosting
+// the loop files supports reflink clones.
+func (t T) Capabilities() []string {
+	l := []string{"rox", "rwx", "roo", "rwo", "blk"}
+	if isReflinkCapable(t.path()) {
+		l = append(l, "snap")
+	}
+	return l
+}
+
+// isReflinkCapable returns true if the filesystem hosting dir supports
+// reflink clones. The result is cached per directory, as the test
+// creates and clones a temporary file.
+func isReflinkCapable(dir string) bool {
+	reflinkCapableMu.Lock()
+	defer reflinkCapableMu.Unlock()
+	if v, ok := reflinkCapable[dir]; ok {
+		return v
+	}
+	v := reflinkIsCapable(dir)
+	reflinkCapable[dir] = v
+	return v
+}
+
+// Usage returns the usage of the filesystem hosting the loop files,
+// where the space not yet allocated by the sparse loop files is
+// accounted as used, as it is committed to the loop devices.
+func (t T) Usage() (pool.StatusUsage, error) {
+	entries, err := df.MountUsage(t.path())
+	if err != nil {
+		return pool.StatusUsage{}, err
+	}
+	if len(entries) == 0 {
+		return pool.StatusUsage{}, fmt.Errorf("not mounted")
+	}
+	images, err := getImagesUsage(t.path())
+	if err != nil {
+		return pool.StatusUsage{}, err
+	}
+	return images.statusUsage(entries[0]), nil
+}
+
+func (t *T) Translate(name string, size float64, shared bool) []string {
+	return t.settings().translate(name, size)
+}
+
+func (t *T) BlkTranslate(name string, size float64, shared bool) []string {
+	return t.settings().blkTranslate(name, size)
+}
+
+func (t T) path() string {
+	return t.GetString("path")
+}
+
+func (t *T) settings() settings {
+	return settings{
+		Path:        t.path(),
+		FSType:      t.GetString("fs_type"),
+		Sparse:      t.GetBool("sparse"),
+		MKFSOptions: t.GetSlice("mkfs_opt"),
+		MntOptions:  t.GetString("mnt_opt"),
+	}
+}
+
+func (t settings) loopFile(name string) string {
+	return filepath.Join(t.Path, name+".img")
+}
+
+func (t settings) blkTranslate(name string, size float64) []string {
+	return []string{
+		"disk#0.type=loop",
+		"disk#0.file=" + t.loopFile(name),
+		"disk#0.size=" + sizeconv.ExactBSizeCompact(size),
+		fmt.Sprintf("disk#0.sparse=%t", t.Sparse),
+	}
+}
+
+func (t settings) translate(name string, size float64) []string {
+	data := t.blkTranslate(name, size)
+	data = append(data,
+		"fs#0.type="+t.FSType,
+		"fs#0.dev={disk#0.exposed_devs[0]}",
+		"fs#0.mnt="+pool.MountPointFromName(name),
+	)
+	if len(t.MKFSOptions) > 0 {
+		data = append(data, "fs#0.mkfs_opt="+pool.ShlexJoin(t.MKFSOptions))
+	}
+	if t.MntOptions != "" {
+		data = append(data, "fs#0.mnt_opt="+t.MntOptions)
+	}
+	return data
+}
+
+// getImagesUsage sums the apparent and allocated sizes of the loop files
+// hosted in dir.
+func getImagesUsage(dir string) (imagesUsage, error) {
+	var data imagesUsage
+	matches, err := filepath.Glob(filepath.Join(dir, "*.img"))
+	if err != nil {
+		return data, err
+	}
+	for _, p := range matches {
+		info, err := os.Stat(p)
+		if err != nil {
+			return data, err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		data.Apparent += info.Size()
+		if st, ok := info.Sys().(*syscall.Stat_t); ok {
+			data.Allocated += st.Blocks * 512
+		} else {
+			data.Allocated += info.Size()
+		}
+	}
+	return data, nil
+}
+
+// statusUsage returns the pool usage from the hosting filesystem usage,
+// moving the committed but not yet allocated space from free to used.
+func (t imagesUsage) statusUsage(entry df.Entry) pool.StatusUsage {
+	var pending float64
+	if t.Apparent > t.Allocated {
+		pending = float64(t.Apparent-t.Allocated) / 1024
+	}
+	usage := pool.StatusUsage{
+		Size: float64(entry.Total),
+		Free: float64(entry.Free) - pending,
+		Used: float64(entry.Used) + pending,
+	}
+	if usage.Free < 0 {
+		usage.Free = 0
+	}
+	return usage
+}
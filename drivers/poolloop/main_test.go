@@ -0,0 +1,93 @@
+// +build linux
+
+package poolloop
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/pool"
+	"opensvc.com/opensvc/util/df"
+)
+
+func TestTranslate(t *testing.T) {
+	s := settings{
+		Path:        "/srv/pool",
+		FSType:      "ext4",
+		Sparse:      true,
+		MKFSOptions: []string{"-L", "my label"},
+		MntOptions:  "noatime",
+	}
+	assert.Equal(t, []string{
+		"disk#0.type=loop",
+		"disk#0.file=/srv/pool/v1.img",
+		"disk#0.size=10m",
+		"disk#0.sparse=true",
+		"fs#0.type=ext4",
+		"fs#0.dev={disk#0.exposed_devs[0]}",
+		"fs#0.mnt=/srv/v1",
+		"fs#0.mkfs_opt=-L 'my label'",
+		"fs#0.mnt_opt=noatime",
+	}, s.translate("v1", 10*1024*1024))
+}
+
+func TestImagesUsage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "poolloop")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	f, err := os.Create(filepath.Join(dir, "v1.img"))
+	require.NoError(t, err)
+	require.NoError(t, f.Truncate(1024*1024))
+	require.NoError(t, f.Close())
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "other"), []byte("data"), 0600))
+
+	images, err := getImagesUsage(dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1024*1024), images.Apparent)
+	assert.Less(t, images.Allocated, images.Apparent)
+
+	images = imagesUsage{Apparent: 1024 * 1024, Allocated: 0}
+	assert.Equal(t, pool.StatusUsage{Size: 4096, Free: 1024, Used: 3072}, images.statusUsage(df.Entry{Total: 4096, Free: 2048, Used: 2048}))
+	assert.Equal(t, pool.StatusUsage{Size: 1024, Free: 0, Used: 1536}, images.statusUsage(df.Entry{Total: 1024, Free: 512, Used: 512}))
+}
+
+func TestCheckDetached(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("need root to attach a loop device")
+	}
+	dir, err := ioutil.TempDir("", "poolloop")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	p := filepath.Join(dir, "v1.img")
+	require.NoError(t, ioutil.WriteFile(p, make([]byte, 1024*1024), 0600))
+	assert.NoError(t, checkDetached(p))
+
+	b, err := exec.Command("losetup", "-f", "--show", p).Output()
+	if err != nil {
+		t.Skipf("attach a loop device: %s", err)
+	}
+	dev := strings.TrimSpace(string(b))
+	defer func() { _ = exec.Command("losetup", "-d", dev).Run() }()
+	assert.Error(t, checkDetached(p))
+}
+
+func TestIsReflinkCapableCache(t *testing.T) {
+	saved := reflinkIsCapable
+	defer func() { reflinkIsCapable = saved }()
+	calls := make(map[string]int)
+	reflinkIsCapable = func(dir string) bool {
+		calls[dir]++
+		return dir == "/srv/reflink"
+	}
+	for i := 0; i < 3; i++ {
+		assert.True(t, isReflinkCapable("/srv/reflink"))
+		assert.False(t, isReflinkCapable("/srv/other"))
+	}
+	assert.Equal(t, map[string]int{"/srv/reflink": 1, "/srv/other": 1}, calls)
+}
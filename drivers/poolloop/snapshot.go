@@ -0,0 +1,133 @@
+// +build linux
+
+package poolloop
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"opensvc.com/opensvc/core/pool"
+	"opensvc.com/opensvc/util/file"
+	"opensvc.com/opensvc/util/loop"
+	"opensvc.com/opensvc/util/reflink"
+)
+
+func (t T) snapshotsDir(volume string) string {
+	return filepath.Join(t.path(), ".snapshots", volume)
+}
+
+func (t T) snapshotFile(volume string, name string) string {
+	return filepath.Join(t.snapshotsDir(volume), name+".img")
+}
+
+// CreateSnapshot creates a reflink clone of the volume loop file in the
+// pool snapshots directory.
+func (t T) CreateSnapshot(volume string, name string) error {
+	if err := pool.ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	src := t.settings().loopFile(volume)
+	dst := t.snapshotFile(volume, name)
+	if file.Exists(dst) {
+		return fmt.Errorf("snapshot %s of %s already exists", name, volume)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+	return reflink.Clone(src, dst)
+}
+
+func (t T) DeleteSnapshot(volume string, name string) error {
+	if err := pool.ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	p := t.snapshotFile(volume, name)
+	if !file.ExistsAndRegular(p) {
+		return fmt.Errorf("snapshot %s of %s does not exist", name, volume)
+	}
+	return os.Remove(p)
+}
+
+// RollbackSnapshot replaces the volume loop file by a reflink clone of
+// the snapshot file. The snapshot is kept.
+//
+// The rollback is refused while the loop file is attached to a loop
+// device, even if the caller forces it: the device would keep using the
+// replaced file, and the data written to it would be lost.
+func (t T) RollbackSnapshot(volume string, name string) error {
+	if err := pool.ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	src := t.snapshotFile(volume, name)
+	dst := t.settings().loopFile(volume)
+	if !file.ExistsAndRegular(src) {
+		return fmt.Errorf("snapshot %s of %s does not exist", name, volume)
+	}
+	if err := checkDetached(dst); err != nil {
+		return errors.Wrapf(err, "refuse to rollback %s", volume)
+	}
+	tmp := dst + ".rollback"
+	_ = os.Remove(tmp)
+	if err := reflink.Clone(src, tmp); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// checkDetached returns an error if the file is attached to a loop
+// device, or if the loop devices can not be listed.
+func checkDetached(p string) error {
+	if !loop.IsCapable() {
+		return nil
+	}
+	attached, err := loop.New(loop.WithLogger(&log.Logger)).FileExists(p)
+	switch {
+	case err != nil:
+		return errors.Wrapf(err, "check if %s is attached to a loop device", p)
+	case attached:
+		return fmt.Errorf("%s is attached to a loop device", p)
+	default:
+		return nil
+	}
+}
+
+func (t T) Snapshots(volume string) (pool.SnapshotList, error) {
+	l := make(pool.SnapshotList, 0)
+	matches, err := filepath.Glob(filepath.Join(t.snapshotsDir(volume), "*.img"))
+	if err != nil {
+		return l, err
+	}
+	for _, p := range matches {
+		info, err := os.Stat(p)
+		if err != nil {
+			return l, err
+		}
+		l = append(l, pool.Snapshot{
+			Name:    strings.TrimSuffix(filepath.Base(p), ".img"),
+			Volume:  volume,
+			Created: info.ModTime(),
+		})
+	}
+	return l, nil
+}
+
+// CloneSnapshot creates the clone volume loop file as a reflink clone of
+// the snapshot file.
+func (t T) CloneSnapshot(volume string, name string, clone string) error {
+	if err := pool.ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	src := t.snapshotFile(volume, name)
+	dst := t.settings().loopFile(clone)
+	if !file.ExistsAndRegular(src) {
+		return fmt.Errorf("snapshot %s of %s does not exist", name, volume)
+	}
+	if file.Exists(dst) {
+		return fmt.Errorf("volume loop file %s already exists", dst)
+	}
+	return reflink.Clone(src, dst)
+}
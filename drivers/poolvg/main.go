@@ -0,0 +1,130 @@
+// +build linux
+
+package poolvg
+
+import (
+	"github.com/rs/zerolog/log"
+	"opensvc.com/opensvc/core/pool"
+	"opensvc.com/opensvc/util/lvm2"
+	"opensvc.com/opensvc/util/sizeconv"
+)
+
+type (
+	T struct {
+		pool.T
+	}
+
+	// settings are the pool keywords values used by the translators.
+	settings struct {
+		VG            string
+		ThinPool      string
+		FSType        string
+		CreateOptions []string
+		MKFSOptions   []string
+		MntOptions    string
+	}
+)
+
+func init() {
+	pool.Register("vg", NewPooler)
+}
+
+func NewPooler() pool.Pooler {
+	t := New()
+	var i interface{} = t
+	return i.(pool.Pooler)
+}
+
+func New() *T {
+	t := T{}
+	return &t
+}
+
+func (t T) Head() string {
+	return t.vgName()
+}
+
+func (t T) Capabilities() []string {
+	return []string{"rox", "rwx", "roo", "rwo", "blk", "snap"}
+}
+
+// Usage returns the usage of the thin pool logical volume if set, or of
+// the volume group.
+func (t T) Usage() (pool.StatusUsage, error) {
+	if thinPool := t.GetString("thin_pool"); thinPool != "" {
+		size, percent, err := lvm2.NewLV(t.vgName(), thinPool, lvm2.WithLogger(&log.Logger)).Usage()
+		if err != nil {
+			return pool.StatusUsage{}, err
+		}
+		used := float64(size) * percent / 100
+		return pool.StatusUsage{
+			Size: float64(size) / 1024,
+			Used: used / 1024,
+			Free: (float64(size) - used) / 1024,
+		}, nil
+	}
+	size, free, err := lvm2.NewVG(t.vgName(), lvm2.WithLogger(&log.Logger)).Usage()
+	if err != nil {
+		return pool.StatusUsage{}, err
+	}
+	return pool.StatusUsage{
+		Size: float64(size) / 1024,
+		Free: float64(free) / 1024,
+		Used: float64(size-free) / 1024,
+	}, nil
+}
+
+func (t *T) Translate(name string, size float64, shared bool) []string {
+	return t.settings().translate(name, size)
+}
+
+func (t *T) BlkTranslate(name string, size float64, shared bool) []string {
+	return t.settings().blkTranslate(name, size)
+}
+
+func (t T) vgName() string {
+	return t.GetString("name")
+}
+
+func (t *T) settings() settings {
+	return settings{
+		VG:            t.vgName(),
+		ThinPool:      t.GetString("thin_pool"),
+		FSType:        t.GetString("fs_type"),
+		CreateOptions: t.GetSlice("create_options"),
+		MKFSOptions:   t.GetSlice("mkfs_opt"),
+		MntOptions:    t.GetString("mnt_opt"),
+	}
+}
+
+func (t settings) blkTranslate(name string, size float64) []string {
+	data := []string{
+		"disk#0.type=lv",
+		"disk#0.name=" + name,
+		"disk#0.vg=" + t.VG,
+		"disk#0.size=" + sizeconv.ExactBSizeCompact(size),
+	}
+	if t.ThinPool != "" {
+		data = append(data, "disk#0.thin_pool="+t.ThinPool)
+	}
+	if len(t.CreateOptions) > 0 {
+		data = append(data, "disk#0.create_options="+pool.ShlexJoin(t.CreateOptions))
+	}
+	return data
+}
+
+func (t settings) translate(name string, size float64) []string {
+	data := t.blkTranslate(name, size)
+	data = append(data,
+		"fs#0.type="+t.FSType,
+		"fs#0.dev="+lvm2.NewLV(t.VG, name).DevPath(),
+		"fs#0.mnt="+pool.MountPointFromName(name),
+	)
+	if len(t.MKFSOptions) > 0 {
+		data = append(data, "fs#0.mkfs_opt="+pool.ShlexJoin(t.MKFSOptions))
+	}
+	if t.MntOptions != "" {
+		data = append(data, "fs#0.mnt_opt="+t.MntOptions)
+	}
+	return data
+}
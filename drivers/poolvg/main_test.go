@@ -0,0 +1,45 @@
+// +build linux
+
+package poolvg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslate(t *testing.T) {
+	s := settings{
+		VG:            "vg1",
+		FSType:        "ext4",
+		CreateOptions: []string{"--contiguous", "y"},
+		MKFSOptions:   []string{"-L", "my label"},
+		MntOptions:    "noatime",
+	}
+	assert.Equal(t, []string{
+		"disk#0.type=lv",
+		"disk#0.name=v1",
+		"disk#0.vg=vg1",
+		"disk#0.size=10m",
+		"disk#0.create_options=--contiguous y",
+		"fs#0.type=ext4",
+		"fs#0.dev=/dev/vg1/v1",
+		"fs#0.mnt=/srv/v1",
+		"fs#0.mkfs_opt=-L 'my label'",
+		"fs#0.mnt_opt=noatime",
+	}, s.translate("v1", 10*1024*1024))
+}
+
+func TestBlkTranslateThin(t *testing.T) {
+	s := settings{
+		VG:       "vg1",
+		ThinPool: "tp1",
+	}
+	assert.Equal(t, []string{
+		"disk#0.type=lv",
+		"disk#0.name=v1",
+		"disk#0.vg=vg1",
+		"disk#0.size=1g",
+		"disk#0.thin_pool=tp1",
+	}, s.blkTranslate("v1", 1024*1024*1024))
+}
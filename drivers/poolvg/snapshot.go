@@ -0,0 +1,108 @@
+// +build linux
+
+package poolvg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"opensvc.com/opensvc/core/pool"
+	"opensvc.com/opensvc/util/lvm2"
+)
+
+// snapshotLVName returns the name of the logical volume holding the
+// volume snapshot.
+func snapshotLVName(volume string, name string) string {
+	return volume + ".snap." + name
+}
+
+func (t T) lv(name string) *lvm2.LV {
+	return lvm2.NewLV(t.vgName(), name, lvm2.WithLogger(&log.Logger))
+}
+
+// CreateSnapshot creates a snapshot logical volume of the volume. The
+// snapshots of thick volumes are allocated the snap_size pool keyword
+// value.
+func (t T) CreateSnapshot(volume string, name string) error {
+	if err := pool.ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	size := ""
+	if t.GetString("thin_pool") == "" {
+		size = t.GetString("snap_size")
+	}
+	return t.lv(volume).CreateSnapshot(snapshotLVName(volume, name), size)
+}
+
+func (t T) DeleteSnapshot(volume string, name string) error {
+	if err := pool.ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	lv := t.lv(snapshotLVName(volume, name))
+	if exists, err := lv.Exists(); err != nil {
+		return err
+	} else if !exists {
+		return fmt.Errorf("snapshot %s of %s does not exist", name, volume)
+	}
+	return lv.Remove([]string{"-f"})
+}
+
+// RollbackSnapshot merges the snapshot logical volume into the volume.
+// The snapshot is consumed by the merge, which is deferred to the next
+// volume activation if the volume is open.
+func (t T) RollbackSnapshot(volume string, name string) error {
+	if err := pool.ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	lv := t.lv(snapshotLVName(volume, name))
+	if exists, err := lv.Exists(); err != nil {
+		return err
+	} else if !exists {
+		return fmt.Errorf("snapshot %s of %s does not exist", name, volume)
+	}
+	return lv.Merge()
+}
+
+func (t T) Snapshots(volume string) (pool.SnapshotList, error) {
+	l := make(pool.SnapshotList, 0)
+	infos, err := lvm2.NewVG(t.vgName(), lvm2.WithLogger(&log.Logger)).LVs()
+	if err != nil {
+		return l, err
+	}
+	prefix := snapshotLVName(volume, "")
+	for _, info := range infos {
+		if info.Origin != volume || !strings.HasPrefix(info.LVName, prefix) {
+			continue
+		}
+		created, err := info.Time()
+		if err != nil {
+			return l, errors.Wrapf(err, "lv %s creation time", info.LVName)
+		}
+		l = append(l, pool.Snapshot{
+			Name:    strings.TrimPrefix(info.LVName, prefix),
+			Volume:  volume,
+			Created: created,
+		})
+	}
+	return l, nil
+}
+
+// CloneSnapshot creates the clone logical volume as a thin snapshot of
+// the volume snapshot. Thick snapshots can not be cloned.
+func (t T) CloneSnapshot(volume string, name string, clone string) error {
+	if err := pool.ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	if t.GetString("thin_pool") == "" {
+		return fmt.Errorf("pool %s: cloning a snapshot requires the thin_pool keyword", t.Name())
+	}
+	lv := t.lv(snapshotLVName(volume, name))
+	if exists, err := lv.Exists(); err != nil {
+		return err
+	} else if !exists {
+		return fmt.Errorf("snapshot %s of %s does not exist", name, volume)
+	}
+	return lv.CreateSnapshot(clone, "")
+}
@@ -14,6 +14,7 @@ import (
 	"opensvc.com/opensvc/core/status"
 	"opensvc.com/opensvc/drivers/resdisk"
 	"opensvc.com/opensvc/util/capabilities"
+	"opensvc.com/opensvc/util/converters"
 	"opensvc.com/opensvc/util/device"
 	"opensvc.com/opensvc/util/df"
 	"opensvc.com/opensvc/util/file"
@@ -29,8 +30,9 @@ const (
 type (
 	T struct {
 		resdisk.T
-		File string `json:"file"`
-		Size string `json:"size"`
+		File   string `json:"file"`
+		Size   string `json:"size"`
+		Sparse bool   `json:"sparse"`
 	}
 )
 
@@ -67,6 +69,15 @@ func (t T) Manifest() *manifest.T {
 			Text:         "The size of the loop file to provision.",
 			Example:      "100m",
 		},
+		{
+			Option:       "sparse",
+			Attr:         "Sparse",
+			Scopable:     true,
+			Provisioning: true,
+			Converter:    converters.Bool,
+			Default:      "true",
+			Text:         "Provision the loop file as a sparse file. If false, the file blocks are allocated at provision time, so the loop device can not fail writes when the hosting filesystem fills up.",
+		},
 	}...)
 	return m
 }
@@ -198,7 +209,7 @@ func (t T) UnprovisionLeader(ctx context.Context) error {
 }
 
 func (t T) provisionBase(ctx context.Context) error {
-	base := filepath.Base(t.File)
+	base := filepath.Dir(t.File)
 	if file.ExistsAndDir(base) {
 		return nil
 	}
@@ -232,7 +243,12 @@ func (t T) provision(ctx context.Context) error {
 	if size, err = sizeconv.FromSize(t.Size); err != nil {
 		return err
 	}
-	offset := (size / 512 * 512) - 1
+	size = size / 512 * 512
+	if !t.Sparse {
+		t.Log().Info().Msgf("allocate %d bytes", size)
+		return allocate(f, size)
+	}
+	offset := size - 1
 	t.Log().Info().Msgf("seek/write file, offset %d", offset)
 	if _, err = f.Seek(offset, 0); err != nil {
 		return err
@@ -243,6 +259,39 @@ func (t T) provision(ctx context.Context) error {
 	return nil
 }
 
+// Resize sets the loop file size, and makes the loop device, if any,
+// reread the new size.
+func (t T) Resize(ctx context.Context, size int64) error {
+	size = size / 512 * 512
+	info, err := os.Stat(t.File)
+	if err != nil {
+		return err
+	}
+	t.Log().Info().Msgf("resize file %s from %d to %d bytes", t.File, info.Size(), size)
+	f, err := os.OpenFile(t.File, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := f.Truncate(size); err != nil {
+		return err
+	}
+	if !t.Sparse && size > info.Size() {
+		if err := allocate(f, size); err != nil {
+			return err
+		}
+	}
+	lo := t.loop()
+	i, err := lo.FileGet(t.File)
+	if err != nil {
+		return err
+	}
+	if i == nil {
+		return nil
+	}
+	return lo.SetCapacity(i.Name)
+}
+
 func (t T) unprovision(ctx context.Context) error {
 	t.Log().Info().Msgf("unlink %s", t.File)
 	return os.RemoveAll(t.File)
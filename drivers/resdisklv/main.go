@@ -30,6 +30,7 @@ type (
 		LVName        string   `json:"name"`
 		VGName        string   `json:"vg"`
 		Size          string   `json:"size"`
+		ThinPool      string   `json:"thin_pool"`
 		CreateOptions []string `json:"create_options"`
 	}
 	LVDriver interface {
@@ -44,6 +45,12 @@ type (
 	LVDriverProvisioner interface {
 		Create(string, []string) error
 	}
+	LVDriverThinProvisioner interface {
+		CreateThin(string, string, []string) error
+	}
+	LVDriverResizer interface {
+		Resize(string) error
+	}
 	LVDriverUnprovisioner interface {
 		Remove([]string) error
 	}
@@ -90,6 +97,14 @@ func (t T) Manifest() *manifest.T {
 			Text:         "The size of the logical volume to provision. A size expression or <n>%{FREE|PVS|VG}.",
 			Example:      "10m",
 		},
+		{
+			Option:       "thin_pool",
+			Attr:         "ThinPool",
+			Scopable:     true,
+			Provisioning: true,
+			Text:         "The name of the thin pool logical volume to allocate the logical volume from. The :kw:`size` is then the virtual size of the thin logical volume.",
+			Example:      "tp1",
+		},
 		{
 			Option:       "create_options",
 			Attr:         "CreateOptions",
@@ -172,6 +187,9 @@ func (t T) Label() string {
 
 func (t T) ProvisionLeader(ctx context.Context) error {
 	lv := t.lv()
+	if t.ThinPool != "" {
+		return t.provisionThin(lv)
+	}
 	lvi, ok := lv.(LVDriverProvisioner)
 	if !ok {
 		return fmt.Errorf("lv %s %s driver does not implement provisioning", lv.FQN(), lv.DriverName())
@@ -187,6 +205,32 @@ func (t T) ProvisionLeader(ctx context.Context) error {
 	return lvi.Create(t.Size, t.CreateOptions)
 }
 
+func (t T) provisionThin(lv LVDriver) error {
+	lvi, ok := lv.(LVDriverThinProvisioner)
+	if !ok {
+		return fmt.Errorf("lv %s %s driver does not implement thin provisioning", lv.FQN(), lv.DriverName())
+	}
+	exists, err := lv.Exists()
+	if err != nil {
+		return err
+	}
+	if exists {
+		t.Log().Info().Msgf("%s is already provisioned", lv.FQN())
+		return nil
+	}
+	return lvi.CreateThin(t.Size, t.ThinPool, t.CreateOptions)
+}
+
+// Resize sets the logical volume size.
+func (t T) Resize(ctx context.Context, size int64) error {
+	lv := t.lv()
+	lvi, ok := lv.(LVDriverResizer)
+	if !ok {
+		return fmt.Errorf("lv %s %s driver does not implement resizing", lv.FQN(), lv.DriverName())
+	}
+	return lvi.Resize(fmt.Sprintf("%d", size))
+}
+
 func (t T) UnprovisionLeader(ctx context.Context) error {
 	lv := t.lv()
 	exists, err := lv.Exists()
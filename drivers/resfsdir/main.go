@@ -128,6 +128,11 @@ func (t T) Label() string {
 	return t.path()
 }
 
+// Head returns the path of the directory.
+func (t T) Head() string {
+	return t.path()
+}
+
 func (t T) path() string {
 	return t.Path
 }
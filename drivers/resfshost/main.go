@@ -349,6 +349,11 @@ func (t T) mountOptions() string {
 	return t.MountOptions
 }
 
+// Head returns the mount point of the filesystem.
+func (t T) Head() string {
+	return t.mountPoint()
+}
+
 func (t T) mountPoint() string {
 	// add zonepath translation, and cache ?
 	return filepath.Clean(t.MountPoint)
@@ -495,6 +500,18 @@ func (t *T) promoteDevicesReadWrite(ctx context.Context) error {
 	return nil
 }
 
+// Resize changes the size of the filesystem to size bytes. The
+// filesystem is resized online if mounted.
+func (t T) Resize(ctx context.Context, size int64) error {
+	mnt := ""
+	if v, err := t.isMounted(); err != nil {
+		return err
+	} else if v {
+		mnt = t.mountPoint()
+	}
+	return filesystems.Resize(t.fs(), t.devpath(), mnt, size)
+}
+
 func (t T) fs() filesystems.I {
 	fs := filesystems.FromType(t.Type)
 	fs.SetLog(t.Log())
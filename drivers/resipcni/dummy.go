@@ -0,0 +1,21 @@
+// +build !linux
+
+package resipcni
+
+import (
+	"context"
+
+	"opensvc.com/opensvc/core/status"
+)
+
+func (t T) Start(ctx context.Context) error {
+	return nil
+}
+
+func (t T) Stop(ctx context.Context) error {
+	return nil
+}
+
+func (t *T) Status(ctx context.Context) status.T {
+	return status.NotApplicable
+}
@@ -0,0 +1,215 @@
+// +build linux
+
+package resipcni
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/containernetworking/cni/libcni"
+	"github.com/containernetworking/cni/pkg/types/current"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/core/actionrollback"
+	"opensvc.com/opensvc/core/network"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/file"
+)
+
+func (t T) cni() (*libcni.CNIConfig, *libcni.NetworkConfigList, error) {
+	config := object.NewNode().MergedConfig()
+	confDir := network.CNIConfigDir(config)
+	pluginsDir := network.CNIPluginsDir(config)
+	list, err := libcni.LoadConfList(confDir, t.Network)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "load cni network %s configuration from %s (see 'network setup')", t.Network, confDir)
+	}
+	c := libcni.NewCNIConfigWithCacheDir([]string{pluginsDir}, t.cacheDir(), nil)
+	return c, list, nil
+}
+
+// runtimeConf returns the CNI runtime configuration of the attachment
+// described by the state. The port mappings are the ones stored on
+// start, so a DEL releases the mappings set up by the ADD even if the
+// expose keyword changed meanwhile.
+func (t T) runtimeConf(s state) *libcni.RuntimeConf {
+	rt := &libcni.RuntimeConf{
+		ContainerID: s.ContainerID,
+		NetNS:       s.NetNS,
+		IfName:      s.NSDev,
+		CacheDir:    t.cacheDir(),
+	}
+	if len(s.PortMappings) > 0 {
+		rt.CapabilityArgs = map[string]interface{}{
+			"portMappings": s.PortMappings,
+		}
+	}
+	return rt
+}
+
+func (t *T) StatusInfo() map[string]interface{} {
+	data := make(map[string]interface{})
+	s, err := t.loadState()
+	if err != nil {
+		return data
+	}
+	data["netns"] = s.NetNS
+	data["nsdev"] = s.NSDev
+	data["network"] = s.Network
+	data["expose"] = t.Expose
+	if r, err := current.NewResult(s.Result); err == nil {
+		if res, err := current.GetResult(r); err == nil && len(res.IPs) > 0 {
+			ones, _ := res.IPs[0].Address.Mask.Size()
+			data["ipaddr"] = res.IPs[0].Address.IP.String()
+			data["netmask"] = ones
+		}
+	}
+	return data
+}
+
+func (t T) Start(ctx context.Context) error {
+	if _, err := t.loadState(); err == nil {
+		if initialStatus := t.Status(ctx); initialStatus == status.Up {
+			t.Log().Info().Msgf("%s is already up", t.Network)
+			return nil
+		}
+	}
+	mappings, err := t.portMappings()
+	if err != nil {
+		return err
+	}
+	c, list, err := t.cni()
+	if err != nil {
+		return err
+	}
+	netns, own, err := t.netnsPath()
+	if err != nil {
+		return err
+	}
+	if own {
+		if err := t.addNetNS(netns); err != nil {
+			return err
+		}
+		actionrollback.Register(ctx, func() error {
+			return t.delNetNS(netns)
+		})
+	}
+	s := state{
+		ContainerID:  t.containerID(),
+		Network:      t.Network,
+		NetNS:        netns,
+		NSDev:        t.NSDev,
+		OwnNetNS:     own,
+		PortMappings: mappings,
+	}
+	rt := t.runtimeConf(s)
+	t.Log().Info().Msgf("cni add %s to netns %s dev %s (%d port mappings)", t.Network, netns, t.NSDev, len(mappings))
+	result, err := c.AddNetworkList(ctx, list, rt)
+	if err != nil {
+		return errors.Wrapf(err, "cni add %s", t.Network)
+	}
+	actionrollback.Register(ctx, func() error {
+		return c.DelNetworkList(context.Background(), list, rt)
+	})
+	if r, err := current.GetResult(result); err == nil {
+		for _, ip := range r.IPs {
+			t.Log().Info().Msgf("cni allocated %s", ip.Address.String())
+		}
+		s.Result, _ = json.Marshal(r)
+	}
+	if err := t.writeState(s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t T) Stop(ctx context.Context) error {
+	s, err := t.loadState()
+	if os.IsNotExist(err) {
+		t.Log().Info().Msgf("%s is already down", t.Network)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	t.Network = s.Network
+	c, list, err := t.cni()
+	if err != nil {
+		return err
+	}
+	// the DEL is sent even if the netns is gone, so the plugins release
+	// the ip address and the port mappings. The state is kept on error,
+	// so the next stop retries.
+	t.Log().Info().Msgf("cni del %s from netns %s dev %s", s.Network, s.NetNS, s.NSDev)
+	if err := c.DelNetworkList(ctx, list, t.runtimeConf(*s)); err != nil {
+		return errors.Wrapf(err, "cni del %s", s.Network)
+	}
+	if s.OwnNetNS {
+		if err := t.delNetNS(s.NetNS); err != nil {
+			return err
+		}
+	}
+	return t.removeState()
+}
+
+func (t *T) Status(ctx context.Context) status.T {
+	s, err := t.loadState()
+	if os.IsNotExist(err) {
+		return status.Down
+	}
+	if err != nil {
+		t.StatusLog().Error("%s", err)
+		return status.Down
+	}
+	if !file.Exists(s.NetNS) {
+		t.StatusLog().Warn("netns %s does not exist", s.NetNS)
+		return status.Down
+	}
+	t.Network = s.Network
+	c, list, err := t.cni()
+	if err != nil {
+		t.StatusLog().Error("%s", err)
+		return status.Undef
+	}
+	if err := c.CheckNetworkList(ctx, list, t.runtimeConf(*s)); err != nil {
+		t.StatusLog().Error("cni check: %s", err)
+		return status.Down
+	}
+	return status.Up
+}
+
+func (t T) addNetNS(p string) error {
+	if file.Exists(p) {
+		return nil
+	}
+	t.Log().Info().Msgf("create netns %s", p)
+	cmd := command.New(
+		command.WithName("ip"),
+		command.WithVarArgs("netns", "add", filepath.Base(p)),
+		command.WithLogger(t.Log()),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	return cmd.Run()
+}
+
+func (t T) delNetNS(p string) error {
+	if !file.Exists(p) {
+		return nil
+	}
+	t.Log().Info().Msgf("delete netns %s", p)
+	cmd := command.New(
+		command.WithName("ip"),
+		command.WithVarArgs("netns", "delete", filepath.Base(p)),
+		command.WithLogger(t.Log()),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	return cmd.Run()
+}
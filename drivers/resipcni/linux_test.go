@@ -0,0 +1,39 @@
+// +build linux
+
+package resipcni
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type testObjectDriver struct {
+	varDir string
+}
+
+func (t testObjectDriver) Log() *zerolog.Logger {
+	l := zerolog.Nop()
+	return &l
+}
+
+func (t testObjectDriver) VarDir() string {
+	return t.varDir
+}
+
+func TestRuntimeConfPortMappings(t *testing.T) {
+	stored := []portMapping{{HostPort: 8443, ContainerPort: 443, Protocol: "tcp"}}
+	r := T{Expose: []string{"80/tcp:8080"}}
+	r.SetRID("ip#1")
+	r.SetObjectDriver(testObjectDriver{varDir: t.TempDir()})
+	s := state{ContainerID: "c1", NetNS: "/var/run/netns/c1", NSDev: "eth12", PortMappings: stored}
+
+	rt := r.runtimeConf(s)
+	assert.Equal(t, "c1", rt.ContainerID)
+	assert.Equal(t, "eth12", rt.IfName)
+	assert.Equal(t, stored, rt.CapabilityArgs["portMappings"], "the stored port mappings are used, not the expose keyword")
+
+	s.PortMappings = nil
+	assert.Nil(t, r.runtimeConf(s).CapabilityArgs)
+}
@@ -0,0 +1,234 @@
+package resipcni
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"opensvc.com/opensvc/core/drivergroup"
+	"opensvc.com/opensvc/core/expose"
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/core/manifest"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/provisioned"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/util/converters"
+	"opensvc.com/opensvc/util/file"
+)
+
+const (
+	driverGroup = drivergroup.IP
+	driverName  = "cni"
+
+	netnsRunDir = "/var/run/netns"
+)
+
+type (
+	T struct {
+		resource.T
+
+		// config
+		Network string   `json:"network"`
+		NetNS   string   `json:"netns"`
+		NSDev   string   `json:"nsdev"`
+		Expose  []string `json:"expose"`
+
+		// context
+		Path     path.T
+		ObjectID uuid.UUID
+	}
+
+	// state is the allocation information stored in the resource var
+	// dir on start, and used by stop and status to address the same
+	// CNI attachment even if the configuration changed meanwhile.
+	state struct {
+		ContainerID  string          `json:"container_id"`
+		Network      string          `json:"network"`
+		NetNS        string          `json:"netns"`
+		NSDev        string          `json:"nsdev"`
+		OwnNetNS     bool            `json:"own_netns"`
+		PortMappings []portMapping   `json:"port_mappings,omitempty"`
+		Result       json.RawMessage `json:"result"`
+	}
+
+	// portMapping is the element of the "portMappings" CNI capability
+	// argument, as expected by the portmap plugin.
+	portMapping struct {
+		HostPort      int    `json:"hostPort"`
+		ContainerPort int    `json:"containerPort"`
+		Protocol      string `json:"protocol"`
+	}
+)
+
+func init() {
+	resource.Register(driverGroup, driverName, New)
+}
+
+func New() resource.Driver {
+	t := &T{}
+	return t
+}
+
+// Manifest exposes to the core the input expected by the driver.
+func (t T) Manifest() *manifest.T {
+	m := manifest.New(driverGroup, driverName, t)
+	m.AddKeyword([]keywords.Keyword{
+		{
+			Option:   "network",
+			Attr:     "Network",
+			Scopable: true,
+			Default:  "default",
+			Example:  "mynet",
+			Text:     "The name of the CNI network to plug into. The default network is created using the host-local bridge plugin if no existing configuration already exists. The network CNI configuration files are installed by the ``network setup`` command from the ``network#<name>`` node configuration sections.",
+		},
+		{
+			Option:   "netns",
+			Attr:     "NetNS",
+			Scopable: true,
+			Example:  "/var/run/netns/svc1",
+			Text:     "The network namespace to plumb the ip into, expressed as a netns name or a netns file path. If not set, the driver creates a network namespace dedicated to the resource, and deletes it on stop.",
+		},
+		{
+			Option:   "nsdev",
+			Attr:     "NSDev",
+			Scopable: true,
+			Default:  "eth12",
+			Example:  "eth0",
+			Text:     "The interface name in the network namespace.",
+		},
+		{
+			Option:    "expose",
+			Attr:      "Expose",
+			Scopable:  true,
+			Converter: converters.List,
+			Example:   "443/tcp:8443 53/udp",
+			Text:      "A whitespace-separated list of ``<port>/<protocol>[:<host port>]`` describing socket services that mandate a SRV exposition. With <host_port> set, the ip.cni driver configures port mappings too.",
+		},
+	}...)
+	m.AddContext([]manifest.Context{
+		{
+			Key:  "path",
+			Attr: "Path",
+			Ref:  "object.path",
+		},
+		{
+			Key:  "object_id",
+			Attr: "ObjectID",
+			Ref:  "object.id",
+		},
+	}...)
+	return m
+}
+
+func (t T) Label() string {
+	return t.Network
+}
+
+func (t *T) Provision(ctx context.Context) error {
+	return nil
+}
+
+func (t *T) Unprovision(ctx context.Context) error {
+	return nil
+}
+
+func (t T) Provisioned() (provisioned.T, error) {
+	return provisioned.NotApplicable, nil
+}
+
+// containerID returns the CNI container id, unique for each resource
+// of each object.
+func (t T) containerID() string {
+	rid := strings.Replace(t.RID(), "#", ".", 1)
+	return t.ObjectID.String() + "-" + rid
+}
+
+func (t T) stateFile() string {
+	return filepath.Join(t.VarDir(), "cni.json")
+}
+
+func (t T) cacheDir() string {
+	return filepath.Join(t.VarDir(), "cni")
+}
+
+func (t T) loadState() (*state, error) {
+	b, err := ioutil.ReadFile(t.stateFile())
+	if err != nil {
+		return nil, err
+	}
+	s := &state{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (t T) writeState(s state) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	p := t.stateFile()
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return err
+	}
+	tmp := p + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+func (t T) removeState() error {
+	p := t.stateFile()
+	if !file.Exists(p) {
+		return nil
+	}
+	return os.Remove(p)
+}
+
+// netnsPath returns the netns file path to plumb the ip into, and a
+// boolean set to true if the netns is dedicated to the resource.
+func (t T) netnsPath() (string, bool, error) {
+	switch {
+	case t.NetNS == "":
+		return filepath.Join(netnsRunDir, t.containerID()), true, nil
+	case strings.Contains(t.NetNS, "#"):
+		return "", false, fmt.Errorf("netns %s: resource id references are not supported by this agent", t.NetNS)
+	case strings.HasPrefix(t.NetNS, "/"):
+		return t.NetNS, false, nil
+	default:
+		return filepath.Join(netnsRunDir, t.NetNS), false, nil
+	}
+}
+
+// portMappings returns the portMappings CNI capability argument
+// parsed from the expose keyword. Only the expose entries with a host
+// port produce a port mapping.
+func (t T) portMappings() ([]portMapping, error) {
+	return parsePortMappings(t.Expose)
+}
+
+func parsePortMappings(l []string) ([]portMapping, error) {
+	mappings := make([]portMapping, 0)
+	exposes, err := expose.ParseList(l)
+	if err != nil {
+		return mappings, err
+	}
+	for _, e := range exposes {
+		if e.HostPort == 0 {
+			continue
+		}
+		mappings = append(mappings, portMapping{
+			HostPort:      e.HostPort,
+			ContainerPort: e.Port,
+			Protocol:      e.Protocol,
+		})
+	}
+	return mappings, nil
+}
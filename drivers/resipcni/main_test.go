@@ -0,0 +1,25 @@
+package resipcni
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePortMappings(t *testing.T) {
+	l, err := parsePortMappings([]string{"443/tcp:8443", "53/udp", "80:8080", "53/UDP:5353"})
+	require.NoError(t, err)
+	assert.Equal(t, []portMapping{
+		{HostPort: 8443, ContainerPort: 443, Protocol: "tcp"},
+		{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+		{HostPort: 5353, ContainerPort: 53, Protocol: "udp"},
+	}, l)
+}
+
+func TestParsePortMappingsErrors(t *testing.T) {
+	for _, s := range []string{"a/tcp", "443/sctp:443", "443/tcp:b"} {
+		_, err := parsePortMappings([]string{s})
+		assert.Errorf(t, err, "%s", s)
+	}
+}
@@ -0,0 +1,169 @@
+package resiphost
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"opensvc.com/opensvc/core/actionrollback"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/dnsupdate"
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+const (
+	tsigNameKey      = "tsig_name"
+	tsigAlgorithmKey = "tsig_algorithm"
+	tsigSecretKey    = "tsig_secret"
+)
+
+// dnsRecordName returns the name of the record registered by the dns
+// update, relative to the dns_update_zone.
+func (t T) dnsRecordName() string {
+	name := t.Path.Name
+	if t.DNSNameSuffix != "" {
+		name = name + "-" + t.DNSNameSuffix
+	}
+	return name
+}
+
+func (t T) dnsUpdater() (*dnsupdate.T, error) {
+	opts := []funcopt.O{
+		dnsupdate.WithServer(t.DNSUpdateServer),
+		dnsupdate.WithZone(t.DNSUpdateZone),
+	}
+	if t.DNSUpdateTTL > 0 {
+		opts = append(opts, dnsupdate.WithTTL(uint32(t.DNSUpdateTTL)))
+	}
+	if t.DNSUpdateSec != "" {
+		name, algorithm, secret, err := t.tsigKey()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, dnsupdate.WithTSIG(name, algorithm, secret))
+	}
+	return dnsupdate.New(opts...), nil
+}
+
+func (t T) tsigSecPath() (path.T, error) {
+	if strings.Contains(t.DNSUpdateSec, "/") {
+		return path.Parse(t.DNSUpdateSec)
+	}
+	return path.New(t.DNSUpdateSec, t.Path.Namespace, "sec")
+}
+
+// tsigKey returns the name, algorithm and secret of the TSIG key stored
+// in the dns_update_sec object.
+func (t T) tsigKey() (string, string, string, error) {
+	p, err := t.tsigSecPath()
+	if err != nil {
+		return "", "", "", err
+	}
+	sec := object.NewSec(p, object.WithVolatile(true))
+	if !sec.Exists() {
+		return "", "", "", fmt.Errorf("dns_update_sec %s does not exist", p)
+	}
+	decode := func(k string) (string, error) {
+		b, err := sec.Decode(object.OptsDecode{Key: k})
+		if err != nil {
+			return "", fmt.Errorf("dns_update_sec %s: %s", p, err)
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	name, err := decode(tsigNameKey)
+	if err != nil {
+		return "", "", "", err
+	}
+	secret, err := decode(tsigSecretKey)
+	if err != nil {
+		return "", "", "", err
+	}
+	var algorithm string
+	if sec.HasKey(tsigAlgorithmKey) {
+		if algorithm, err = decode(tsigAlgorithmKey); err != nil {
+			return "", "", "", err
+		}
+	}
+	return name, algorithm, secret, nil
+}
+
+func (t T) startDNS(ctx context.Context) error {
+	if err := t.dnsRegister(ctx); err != nil {
+		return err
+	}
+	if err := t.waitDNS(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// stopDNS unregisters the dns record. The ip is already stopped, so an
+// unregister error is only logged: a stale record resolves to an
+// unreachable address, and must not block the stop.
+func (t T) stopDNS() {
+	if err := t.dnsUnregister(); err != nil {
+		t.Log().Warn().Msgf("%s", err)
+	}
+}
+
+func (t T) dnsRegister(ctx context.Context) error {
+	if !t.DNSUpdate {
+		return nil
+	}
+	c, err := t.dnsUpdater()
+	if err != nil {
+		return err
+	}
+	ip := t.ipaddr()
+	name := c.RecordName(t.dnsRecordName())
+	t.Log().Info().Msgf("dns update: register %s %s on %s", name, ip, c.Server())
+	if err := c.Register(t.dnsRecordName(), ip); err != nil {
+		return fmt.Errorf("dns update: register %s %s: %s", name, ip, err)
+	}
+	actionrollback.Register(ctx, func() error {
+		return t.dnsUnregister()
+	})
+	return nil
+}
+
+func (t T) dnsUnregister() error {
+	if !t.DNSUpdate {
+		return nil
+	}
+	c, err := t.dnsUpdater()
+	if err != nil {
+		return err
+	}
+	ip := t.ipaddr()
+	name := c.RecordName(t.dnsRecordName())
+	t.Log().Info().Msgf("dns update: unregister %s %s on %s", name, ip, c.Server())
+	if err := c.Unregister(t.dnsRecordName(), ip); err != nil {
+		return fmt.Errorf("dns update: unregister %s %s: %s", name, ip, err)
+	}
+	return nil
+}
+
+// waitDNSName returns the name expected to resolve to the resource ip
+// address: the dns update record name if dns_update is set, or ipname.
+func (t T) waitDNSName() string {
+	if t.DNSUpdate {
+		return dnsupdate.New(dnsupdate.WithZone(t.DNSUpdateZone)).RecordName(t.dnsRecordName())
+	}
+	return t.IpName
+}
+
+func (t T) waitDNS(ctx context.Context) error {
+	if !t.WaitDNS {
+		return nil
+	}
+	timeout := time.Minute
+	if t.WaitDNSTimeout != nil {
+		timeout = *t.WaitDNSTimeout
+	}
+	name := t.waitDNSName()
+	ip := t.ipaddr()
+	t.Log().Info().Msgf("wait for %s to resolve to %s (timeout %s)", name, ip, timeout)
+	return dnsupdate.Wait(ctx, t.DNSUpdateServer, name, ip, timeout)
+}
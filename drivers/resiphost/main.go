@@ -14,6 +14,7 @@ import (
 	"opensvc.com/opensvc/core/drivergroup"
 	"opensvc.com/opensvc/core/keywords"
 	"opensvc.com/opensvc/core/manifest"
+	"opensvc.com/opensvc/core/path"
 	"opensvc.com/opensvc/core/provisioned"
 	"opensvc.com/opensvc/core/resource"
 	"opensvc.com/opensvc/core/status"
@@ -38,18 +39,26 @@ type (
 		resource.T
 
 		// config
-		IpName        string   `json:"ipname"`
-		IpDev         string   `json:"ipdev"`
-		Netmask       string   `json:"netmask"`
-		Network       string   `json:"network"`
-		Gateway       string   `json:"gateway"`
-		WaitDNS       bool     `json:"wait_dns"`
-		DNSUpdate     bool     `json:"dns_update"`
-		DNSNameSuffix string   `json:"dns_name_suffix"`
-		Provisioner   string   `json:"provisioner"`
-		CheckCarrier  bool     `json:"check_carrier"`
-		Alias         bool     `json:"alias"`
-		Expose        []string `json:"expose"`
+		IpName          string         `json:"ipname"`
+		IpDev           string         `json:"ipdev"`
+		Netmask         string         `json:"netmask"`
+		Network         string         `json:"network"`
+		Gateway         string         `json:"gateway"`
+		WaitDNS         bool           `json:"wait_dns"`
+		WaitDNSTimeout  *time.Duration `json:"wait_dns_timeout"`
+		DNSUpdate       bool           `json:"dns_update"`
+		DNSUpdateServer string         `json:"dns_update_server"`
+		DNSUpdateZone   string         `json:"dns_update_zone"`
+		DNSUpdateSec    string         `json:"dns_update_sec"`
+		DNSUpdateTTL    int            `json:"dns_update_ttl"`
+		DNSNameSuffix   string         `json:"dns_name_suffix"`
+		Provisioner     string         `json:"provisioner"`
+		CheckCarrier    bool           `json:"check_carrier"`
+		Alias           bool           `json:"alias"`
+		Expose          []string       `json:"expose"`
+
+		// context
+		Path path.T
 
 		// cache
 		_ipaddr net.IP
@@ -107,13 +116,21 @@ func (t T) Manifest() *manifest.T {
 			Attr:      "WaitDNS",
 			Scopable:  true,
 			Converter: converters.Bool,
-			Text:      "Wait for the cluster DNS records associated to the resource to appear after a resource start and before the next resource can be started. This can be used for apps or containers that require the ip or ip name to be resolvable to provision or execute properly.",
+			Text:      "Wait for the DNS records associated to the resource to resolve to the resource ip address after a resource start and before the next resource can be started. The resolution is asked to :kw:`dns_update_server` if set, or to the system resolver. This can be used for apps or containers that require the ip or ip name to be resolvable to provision or execute properly.",
+		},
+		{
+			Option:    "wait_dns_timeout",
+			Attr:      "WaitDNSTimeout",
+			Scopable:  true,
+			Converter: converters.Duration,
+			Default:   "1m",
+			Text:      "The maximum time to wait for the DNS records to resolve when :kw:`wait_dns` is set. The start action fails when the timeout expires.",
 		},
 		{
 			Option:   "dns_name_suffix",
 			Attr:     "DNSNameSuffix",
 			Scopable: true,
-			Text:     "Add the value as a suffix to the DNS record name. The record created is thus formatted as ``<name>-<dns_name_suffix>.<app>.<managed zone>``.",
+			Text:     "Add the value as a suffix to the DNS record name. The record created is thus formatted as ``<name>-<dns_name_suffix>.<dns_update_zone>``.",
 		},
 		{
 			Option:       "provisioner",
@@ -137,7 +154,36 @@ func (t T) Manifest() *manifest.T {
 			Attr:      "DNSUpdate",
 			Scopable:  true,
 			Converter: converters.Bool,
-			Text:      "Setting this parameter triggers a DNS update. The record created is formatted as ``<name>.<app>.<managed zone>``, unless dns_record_name is specified.",
+			Text:      "Setting this parameter triggers a RFC2136 DNS update of the A or AAAA record on start, and its removal on stop. The record created is formatted as ``<name>.<dns_update_zone>``.",
+		},
+		{
+			Option:   "dns_update_server",
+			Attr:     "DNSUpdateServer",
+			Scopable: true,
+			Example:  "10.0.0.53:53",
+			Text:     "The address of the DNS server receiving the dynamic updates, in ``<addr>[:<port>]`` format. The port defaults to 53.",
+		},
+		{
+			Option:   "dns_update_zone",
+			Attr:     "DNSUpdateZone",
+			Scopable: true,
+			Example:  "svc.example.com",
+			Text:     "The DNS zone to register the records into.",
+		},
+		{
+			Option:   "dns_update_sec",
+			Attr:     "DNSUpdateSec",
+			Scopable: true,
+			Example:  "system/sec/dnsupdate",
+			Text:     "The sec object hosting the TSIG key used to sign the dynamic updates. The sec object must have the ``tsig_name`` and ``tsig_secret`` keys, and optionally the ``tsig_algorithm`` key (defaults to ``hmac-sha256``). A sec name without namespace is looked up in the namespace of the object. If not set, the updates are not signed.",
+		},
+		{
+			Option:    "dns_update_ttl",
+			Attr:      "DNSUpdateTTL",
+			Scopable:  true,
+			Converter: converters.Int,
+			Default:   "60",
+			Text:      "The time to live, in seconds, of the registered records.",
 		},
 		{
 			Option:    "check_carrier",
@@ -164,6 +210,13 @@ func (t T) Manifest() *manifest.T {
 			Text:      "A whitespace-separated list of ``<port>/<protocol>[:<host port>]`` describing socket services that mandate a SRV exposition. With <host_port> set, the ip.cni driver configures port mappings too.",
 		},
 	}...)
+	m.AddContext([]manifest.Context{
+		{
+			Key:  "path",
+			Attr: "Path",
+			Ref:  "object.path",
+		},
+	}...)
 	return m
 }
 
@@ -173,6 +226,7 @@ func (t *T) StatusInfo() map[string]interface{} {
 	data["ipaddr"] = t.ipaddr()
 	data["ipdev"] = t.IpDev
 	data["netmask"] = netmask
+	data["expose"] = t.Expose
 	return data
 }
 
@@ -190,6 +244,9 @@ func (t T) Start(ctx context.Context) error {
 	if err := t.arpAnnounce(); err != nil {
 		return err
 	}
+	if err := t.startDNS(ctx); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -201,6 +258,7 @@ func (t T) Stop(ctx context.Context) error {
 	if err := t.stop(); err != nil {
 		return err
 	}
+	t.stopDNS()
 	return nil
 }
 
@@ -0,0 +1,573 @@
+package resvol
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/volsignal"
+	"opensvc.com/opensvc/util/file"
+	"opensvc.com/opensvc/util/proc"
+	"opensvc.com/opensvc/util/usergroup"
+)
+
+type (
+	// dataSpec is a parsed element of the configs and secrets keywords:
+	// <name>/<key>:<volume relative path>:<options>
+	//
+	// The options are a comma-separated list of ro or rw. The ro keys
+	// are installed without write permission.
+	dataSpec struct {
+		Kind     kind.T
+		Name     string
+		Key      string
+		Dst      string
+		Options  string
+		ReadOnly bool
+	}
+
+	dataStore interface {
+		Exists() bool
+		ConfigFile() string
+		Decode(object.OptsDecode) ([]byte, error)
+		Keys(object.OptsKeys) ([]string, error)
+	}
+)
+
+const (
+	defaultConfigPerm os.FileMode = 0644
+	defaultSecretPerm os.FileMode = 0600
+	defaultDirPerm    os.FileMode = 0755
+)
+
+func parseDataSpec(s string, k kind.T) (dataSpec, error) {
+	spec := dataSpec{Kind: k}
+	l := strings.SplitN(s, ":", 3)
+	nameKey := strings.SplitN(l[0], "/", 2)
+	if len(nameKey) != 2 || nameKey[0] == "" || nameKey[1] == "" {
+		return spec, fmt.Errorf("invalid %s data spec %s: expected <name>/<key>:<path>:<options>", k, s)
+	}
+	spec.Name = nameKey[0]
+	spec.Key = nameKey[1]
+	if len(l) > 1 {
+		spec.Dst = l[1]
+	}
+	if len(l) > 2 {
+		spec.Options = l[2]
+	}
+	for _, opt := range strings.Split(spec.Options, ",") {
+		switch opt {
+		case "":
+		case "ro":
+			spec.ReadOnly = true
+		case "rw":
+			spec.ReadOnly = false
+		default:
+			return spec, fmt.Errorf("invalid %s data spec %s: unsupported option %s, expected ro or rw", k, s, opt)
+		}
+	}
+	return spec, nil
+}
+
+// isGlob returns true if the key is a pattern matching multiple keys.
+func (t dataSpec) isGlob() bool {
+	return strings.ContainsAny(t.Key, "*?[")
+}
+
+// keyDir returns the leading directory part of the key pattern not
+// containing glob characters. This part is not reproduced in the volume.
+func (t dataSpec) keyDir() string {
+	i := strings.IndexAny(t.Key, "*?[")
+	if i < 0 {
+		return ""
+	}
+	j := strings.LastIndex(t.Key[:i], "/")
+	if j < 0 {
+		return ""
+	}
+	return t.Key[:j+1]
+}
+
+// dstPath returns the volume relative path of the file hosting the key.
+func (t dataSpec) dstPath(key string) string {
+	switch {
+	case t.isGlob():
+		return filepath.Join(t.Dst, strings.TrimPrefix(key, t.keyDir()))
+	case t.Dst == "":
+		return key
+	case strings.HasSuffix(t.Dst, "/"):
+		return filepath.Join(t.Dst, filepath.Base(key))
+	default:
+		return t.Dst
+	}
+}
+
+func (t T) dataSpecs() ([]dataSpec, error) {
+	l := make([]dataSpec, 0)
+	for _, s := range t.Configs {
+		spec, err := parseDataSpec(s, kind.Cfg)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, spec)
+	}
+	for _, s := range t.Secrets {
+		spec, err := parseDataSpec(s, kind.Sec)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, spec)
+	}
+	return l, nil
+}
+
+func (t T) dataStore(spec dataSpec) (dataStore, error) {
+	p, err := path.New(spec.Name, t.Path.Namespace, spec.Kind.String())
+	if err != nil {
+		return nil, err
+	}
+	o, ok := object.NewFromPath(p, object.WithVolatile(true)).(dataStore)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a keystore", p)
+	}
+	if !o.Exists() {
+		return nil, fmt.Errorf("%s does not exist", p)
+	}
+	return o, nil
+}
+
+func (t T) hasData() bool {
+	return len(t.Configs) > 0 || len(t.Secrets) > 0 || len(t.Directories) > 0
+}
+
+// dataMtimes returns the modification times of the configuration files of
+// the keystores referenced by the configs and secrets keywords.
+func (t T) dataMtimes() (map[string]time.Time, error) {
+	m := make(map[string]time.Time)
+	specs, err := t.dataSpecs()
+	if err != nil {
+		return nil, err
+	}
+	for _, spec := range specs {
+		o, err := t.dataStore(spec)
+		if err != nil {
+			return nil, err
+		}
+		p := o.ConfigFile()
+		m[p] = file.ModTime(p)
+	}
+	return m, nil
+}
+
+// installData creates the directories and installs the configs and
+// secrets keys in the volume. The files are replaced atomically, and only
+// if their content or permissions changed.
+//
+// It returns true if at least one file or directory was changed.
+func (t T) installData() (bool, error) {
+	if !t.hasData() {
+		return false, nil
+	}
+	mnt := t.MountPoint()
+	if mnt == "" {
+		return false, fmt.Errorf("can not install data: the volume %s has no mount point", t.name())
+	}
+	uid, gid, err := t.owner()
+	if err != nil {
+		return false, err
+	}
+	dirPerm, err := parsePerm(t.DirPerm, defaultDirPerm)
+	if err != nil {
+		return false, err
+	}
+	changed := false
+	if t.DirPerm != "" || t.User != "" || t.Group != "" {
+		if c, err := setMntPermOwner(mnt, dirPerm, uid, gid); err != nil {
+			return changed, err
+		} else {
+			changed = changed || c
+		}
+	}
+	for _, d := range t.Directories {
+		c, err := t.installDir(mnt, d, dirPerm, uid, gid)
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || c
+	}
+	specs, err := t.dataSpecs()
+	if err != nil {
+		return changed, err
+	}
+	for _, spec := range specs {
+		c, err := t.installDataSpec(mnt, spec, dirPerm, uid, gid)
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || c
+	}
+	return changed, nil
+}
+
+func (t T) installDataSpec(mnt string, spec dataSpec, dirPerm os.FileMode, uid, gid int) (bool, error) {
+	dftPerm := defaultConfigPerm
+	if spec.Kind == kind.Sec {
+		dftPerm = defaultSecretPerm
+	}
+	perm, err := parsePerm(t.Perm, dftPerm)
+	if err != nil {
+		return false, err
+	}
+	if spec.ReadOnly {
+		perm &^= 0222
+	}
+	o, err := t.dataStore(spec)
+	if err != nil {
+		return false, err
+	}
+	keys := []string{spec.Key}
+	if spec.isGlob() {
+		if keys, err = o.Keys(object.OptsKeys{Match: spec.Key}); err != nil {
+			return false, err
+		}
+	}
+	changed := false
+	for _, key := range keys {
+		b, err := o.Decode(object.OptsDecode{Key: key})
+		if err != nil {
+			return changed, errors.Wrapf(err, "%s/%s", spec.Name, key)
+		}
+		rel := spec.dstPath(key)
+		p, err := volumePath(mnt, rel)
+		if err != nil {
+			return changed, err
+		}
+		if p == mnt {
+			return changed, fmt.Errorf("%s/%s: the volume path %s is not a file", spec.Name, key, rel)
+		}
+		c, err := t.installFileAt(mnt, rel, b, perm, dirPerm, uid, gid)
+		if err != nil {
+			return changed, errors.Wrapf(err, "%s", p)
+		}
+		if c {
+			t.Log().Info().Str("key", spec.Name+"/"+key).Str("file", p).Msg("installed")
+		}
+		changed = changed || c
+	}
+	return changed, nil
+}
+
+// volumePath returns the absolute path of a volume relative path,
+// refusing to escape the volume. The path is only used for messages: the
+// volume files are accessed through openVolumeDir, so the symlinks
+// planted in the volume are not followed.
+func volumePath(mnt, rel string) (string, error) {
+	p := filepath.Join(mnt, filepath.Clean("/"+rel))
+	if p != mnt && !strings.HasPrefix(p, mnt+string(os.PathSeparator)) {
+		return "", fmt.Errorf("path %s is not in the volume", rel)
+	}
+	return p, nil
+}
+
+// volumePathElements returns the cleaned elements of a volume relative
+// path.
+func volumePathElements(rel string) []string {
+	l := make([]string, 0)
+	for _, e := range strings.Split(filepath.Clean("/"+rel), "/") {
+		if e != "" {
+			l = append(l, e)
+		}
+	}
+	return l
+}
+
+// openDirNoFollow opens the directory name in the directory dirfd,
+// refusing symlinks.
+func openDirNoFollow(dirfd int, name string) (int, error) {
+	fd, err := unix.Openat(dirfd, name, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	switch err {
+	case nil:
+		return fd, nil
+	case unix.ELOOP, unix.ENOTDIR:
+		return -1, fmt.Errorf("%s is a symlink or not a directory", name)
+	default:
+		return -1, err
+	}
+}
+
+// openVolumeDir returns a file descriptor of the volume relative directory
+// rel, created with perm if missing. Each path element is opened relative
+// to its parent without following symlinks, so a symlink planted in the
+// volume, like etc -> /etc, can not make the node write out of the volume.
+// The permissions and ownership are applied to the created directories
+// and to the rel directory.
+//
+// The caller must close the returned file descriptor.
+func (t T) openVolumeDir(mnt, rel string, perm os.FileMode, uid, gid int) (int, bool, error) {
+	fd, err := unix.Open(mnt, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return -1, false, errors.Wrapf(err, "%s", mnt)
+	}
+	changed := false
+	p := mnt
+	elements := volumePathElements(rel)
+	for i, e := range elements {
+		p = filepath.Join(p, e)
+		created := false
+		nfd, err := openDirNoFollow(fd, e)
+		if err == unix.ENOENT {
+			if err := unix.Mkdirat(fd, e, uint32(perm)); err != nil {
+				unix.Close(fd)
+				return -1, changed, errors.Wrapf(err, "%s", p)
+			}
+			t.Log().Info().Str("dir", p).Msg("installed")
+			created = true
+			nfd, err = openDirNoFollow(fd, e)
+		}
+		unix.Close(fd)
+		if err != nil {
+			return -1, changed, errors.Wrapf(err, "%s", p)
+		}
+		fd = nfd
+		changed = changed || created
+		if created || i == len(elements)-1 {
+			c, err := setPermOwner(fd, perm, uid, gid)
+			if err != nil {
+				unix.Close(fd)
+				return -1, changed, errors.Wrapf(err, "%s", p)
+			}
+			changed = changed || c
+		}
+	}
+	return fd, changed, nil
+}
+
+func (t T) installDir(mnt, rel string, perm os.FileMode, uid, gid int) (bool, error) {
+	if _, err := volumePath(mnt, rel); err != nil {
+		return false, err
+	}
+	fd, changed, err := t.openVolumeDir(mnt, rel, perm, uid, gid)
+	if err != nil {
+		return changed, err
+	}
+	unix.Close(fd)
+	return changed, nil
+}
+
+// installFileAt installs the volume relative file rel, creating its
+// parent directories with dirPerm.
+func (t T) installFileAt(mnt, rel string, b []byte, perm, dirPerm os.FileMode, uid, gid int) (bool, error) {
+	dir, name := filepath.Split(filepath.Clean("/" + rel))
+	dirfd, changed, err := t.openVolumeDir(mnt, dir, dirPerm, uid, gid)
+	if err != nil {
+		return changed, err
+	}
+	defer unix.Close(dirfd)
+	c, err := installFile(dirfd, name, b, perm, uid, gid)
+	return changed || c, err
+}
+
+// installFile installs the file name in the directory dirfd. The file is
+// replaced atomically, and only if its content or permissions changed.
+// A symlink or a non-regular file in place of the file is refused.
+func installFile(dirfd int, name string, b []byte, perm os.FileMode, uid, gid int) (bool, error) {
+	fd, err := unix.Openat(dirfd, name, unix.O_RDONLY|unix.O_NOFOLLOW|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	switch err {
+	case nil:
+		f := os.NewFile(uintptr(fd), name)
+		defer f.Close()
+		if err := checkRegular(fd, name); err != nil {
+			return false, err
+		}
+		if current, err := ioutil.ReadAll(f); err == nil && bytes.Equal(current, b) {
+			return setPermOwner(fd, perm, uid, gid)
+		}
+	case unix.ENOENT:
+	case unix.ELOOP:
+		return false, fmt.Errorf("%s is a symlink", name)
+	default:
+		return false, err
+	}
+	tmp := fmt.Sprintf(".%s.%d", name, time.Now().UnixNano())
+	fd, err = unix.Openat(dirfd, tmp, unix.O_WRONLY|unix.O_CREAT|unix.O_EXCL|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0600)
+	if err != nil {
+		return false, errors.Wrapf(err, "%s", tmp)
+	}
+	f := os.NewFile(uintptr(fd), tmp)
+	defer unix.Unlinkat(dirfd, tmp, 0)
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return false, err
+	}
+	if _, err := setPermOwner(fd, perm, uid, gid); err != nil {
+		f.Close()
+		return false, err
+	}
+	if err := f.Close(); err != nil {
+		return false, err
+	}
+	if err := unix.Renameat(dirfd, tmp, dirfd, name); err != nil {
+		return false, errors.Wrapf(err, "%s", name)
+	}
+	return true, nil
+}
+
+func checkRegular(fd int, name string) error {
+	var st unix.Stat_t
+	if err := unix.Fstat(fd, &st); err != nil {
+		return err
+	}
+	if st.Mode&unix.S_IFMT != unix.S_IFREG {
+		return fmt.Errorf("%s is not a regular file", name)
+	}
+	return nil
+}
+
+// setMntPermOwner applies the permissions and ownership to the volume
+// mount point.
+func setMntPermOwner(mnt string, perm os.FileMode, uid, gid int) (bool, error) {
+	fd, err := unix.Open(mnt, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return false, errors.Wrapf(err, "%s", mnt)
+	}
+	defer unix.Close(fd)
+	return setPermOwner(fd, perm, uid, gid)
+}
+
+// setPermOwner applies the permissions and ownership to the open file if
+// they differ. A -1 uid or gid is not applied.
+func setPermOwner(fd int, perm os.FileMode, uid, gid int) (bool, error) {
+	changed := false
+	var st unix.Stat_t
+	if err := unix.Fstat(fd, &st); err != nil {
+		return false, err
+	}
+	if os.FileMode(st.Mode).Perm() != perm {
+		if err := unix.Fchmod(fd, uint32(perm)); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	if uid < 0 && gid < 0 {
+		return changed, nil
+	}
+	if (uid < 0 || int(st.Uid) == uid) && (gid < 0 || int(st.Gid) == gid) {
+		return changed, nil
+	}
+	if err := unix.Fchown(fd, uid, gid); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (t T) owner() (int, int, error) {
+	uid, gid := -1, -1
+	if t.User != "" {
+		i, err := usergroup.UidFromS(t.User)
+		if err != nil {
+			return uid, gid, err
+		}
+		uid = int(i)
+	}
+	if t.Group != "" {
+		i, err := usergroup.GidFromS(t.Group)
+		if err != nil {
+			return uid, gid, err
+		}
+		gid = int(i)
+	}
+	return uid, gid, nil
+}
+
+func parsePerm(s string, dft os.FileMode) (os.FileMode, error) {
+	if s == "" {
+		return dft, nil
+	}
+	i, err := strconv.ParseUint(s, 8, 32)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid octal permissions %s", s)
+	}
+	return os.FileMode(i).Perm(), nil
+}
+
+// sendSignals delivers the signals configured by the signal keyword to
+// the processes of the listed resources. The processes are found by their
+// OPENSVC_ID and OPENSVC_RID environment variables.
+func (t T) sendSignals() {
+	for sig, rids := range volsignal.Parse(t.Signal) {
+		envs := make([][]string, 0)
+		idEnv := "OPENSVC_ID=" + t.ObjectID.String()
+		if len(rids) == 0 {
+			envs = append(envs, []string{idEnv})
+		}
+		for _, rid := range rids {
+			envs = append(envs, []string{idEnv, "OPENSVC_RID=" + rid})
+		}
+		for _, env := range envs {
+			pids, err := proc.FindByEnv(env...)
+			if err != nil {
+				t.Log().Warn().Err(err).Msgf("find processes to send %s", sig)
+				continue
+			}
+			for _, pid := range pids {
+				if pid == os.Getpid() {
+					continue
+				}
+				t.Log().Info().Int("pid", pid).Strs("env", env).Msgf("send %s", sig)
+				if err := syscall.Kill(pid, sig); err != nil {
+					t.Log().Warn().Err(err).Int("pid", pid).Msgf("send %s", sig)
+				}
+			}
+		}
+	}
+}
+
+// RefreshData re-installs the configs and secrets keys changed since the
+// last installation, and sends the configured signals if files changed.
+//
+// The keys are re-installed only if the resource is up and the keystores
+// configuration files changed since the last call.
+func (t *T) RefreshData() (bool, error) {
+	if !t.hasData() || !t.flagInstalled() {
+		return false, nil
+	}
+	mtimes, err := t.dataMtimes()
+	if err != nil {
+		return false, err
+	}
+	if t.lastDataMtimes != nil && sameMtimes(mtimes, t.lastDataMtimes) {
+		return false, nil
+	}
+	changed, err := t.installData()
+	if err != nil {
+		return changed, err
+	}
+	t.lastDataMtimes = mtimes
+	if changed {
+		t.sendSignals()
+	}
+	return changed, nil
+}
+
+func sameMtimes(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || !w.Equal(v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package resvol
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+	"opensvc.com/opensvc/core/kind"
+)
+
+func TestDataSpecDstPath(t *testing.T) {
+	cases := []struct {
+		spec string
+		key  string
+		dst  string
+	}{
+		{"conf/mycnf:/etc/mysql/my.cnf:ro", "mycnf", "/etc/mysql/my.cnf"},
+		{"conf/mycnf", "mycnf", "mycnf"},
+		{"conf/a/b:etc/", "a/b", "etc/b"},
+		{"cert/*:certs", "pem", "certs/pem"},
+		{"cert/tls/*:certs/", "tls/sub/key", "certs/sub/key"},
+	}
+	for _, c := range cases {
+		spec, err := parseDataSpec(c.spec, kind.Cfg)
+		require.NoError(t, err, c.spec)
+		assert.Equal(t, c.dst, spec.dstPath(c.key), c.spec)
+	}
+}
+
+func TestParseDataSpecErrors(t *testing.T) {
+	for _, s := range []string{"conf", "conf/", "/key:dst"} {
+		_, err := parseDataSpec(s, kind.Sec)
+		assert.Errorf(t, err, "%s", s)
+	}
+}
+
+func TestVolumePath(t *testing.T) {
+	p, err := volumePath("/mnt", "/etc/../a")
+	require.NoError(t, err)
+	assert.Equal(t, "/mnt/a", p)
+	p, err = volumePath("/mnt", "../../etc/passwd")
+	require.NoError(t, err)
+	assert.Equal(t, "/mnt/etc/passwd", p)
+}
+
+func TestParseDataSpecOptions(t *testing.T) {
+	spec, err := parseDataSpec("conf/mycnf:/etc/mysql/my.cnf:ro", kind.Cfg)
+	require.NoError(t, err)
+	assert.True(t, spec.ReadOnly)
+	spec, err = parseDataSpec("conf/mycnf:/etc/mysql/my.cnf:rw", kind.Cfg)
+	require.NoError(t, err)
+	assert.False(t, spec.ReadOnly)
+	_, err = parseDataSpec("conf/mycnf:/etc/mysql/my.cnf:exec", kind.Cfg)
+	assert.Error(t, err)
+}
+
+func openTestDir(t *testing.T, dir string) int {
+	fd, err := unix.Open(dir, unix.O_RDONLY|unix.O_DIRECTORY, 0)
+	require.NoError(t, err)
+	return fd
+}
+
+func TestInstallFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resvol")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dirfd := openTestDir(t, dir)
+	defer unix.Close(dirfd)
+	p := filepath.Join(dir, "f")
+	changed, err := installFile(dirfd, "f", []byte("a"), 0600, -1, -1)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	changed, err = installFile(dirfd, "f", []byte("a"), 0600, -1, -1)
+	require.NoError(t, err)
+	assert.False(t, changed, "same content and perms")
+	changed, err = installFile(dirfd, "f", []byte("a"), 0640, -1, -1)
+	require.NoError(t, err)
+	assert.True(t, changed, "perms change")
+	changed, err = installFile(dirfd, "f", []byte("b"), 0640, -1, -1)
+	require.NoError(t, err)
+	assert.True(t, changed, "content change")
+	b, _ := ioutil.ReadFile(p)
+	assert.Equal(t, "b", string(b))
+	fi, _ := os.Stat(p)
+	assert.Equal(t, os.FileMode(0640), fi.Mode().Perm())
+	l, _ := ioutil.ReadDir(dir)
+	assert.Len(t, l, 1, "no temporary file left")
+}
+
+func TestInstallSymlinkEscape(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resvol")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	mnt := filepath.Join(dir, "mnt")
+	outside := filepath.Join(dir, "outside")
+	require.NoError(t, os.Mkdir(mnt, 0755))
+	require.NoError(t, os.Mkdir(outside, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(outside, "passwd"), []byte("root"), 0644))
+	require.NoError(t, os.Symlink(outside, filepath.Join(mnt, "etc")))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "passwd"), filepath.Join(mnt, "passwd")))
+	r := T{}
+
+	t.Run("directory symlink", func(t *testing.T) {
+		_, err := r.installFileAt(mnt, "etc/passwd", []byte("x"), 0600, 0755, -1, -1)
+		assert.Error(t, err)
+		_, err = r.installDir(mnt, "etc/sub", 0700, -1, -1)
+		assert.Error(t, err)
+		_, err = os.Stat(filepath.Join(outside, "sub"))
+		assert.True(t, os.IsNotExist(err), "no directory created out of the volume")
+	})
+	t.Run("file symlink", func(t *testing.T) {
+		_, err := r.installFileAt(mnt, "passwd", []byte("x"), 0600, 0755, -1, -1)
+		assert.Error(t, err)
+	})
+	t.Run("outside is untouched", func(t *testing.T) {
+		b, _ := ioutil.ReadFile(filepath.Join(outside, "passwd"))
+		assert.Equal(t, "root", string(b))
+		fi, _ := os.Stat(filepath.Join(outside, "passwd"))
+		assert.Equal(t, os.FileMode(0644), fi.Mode().Perm())
+		fi, _ = os.Stat(outside)
+		assert.Equal(t, os.FileMode(0755), fi.Mode().Perm())
+	})
+	t.Run("regular install creates parents", func(t *testing.T) {
+		changed, err := r.installFileAt(mnt, "a/b/c", []byte("x"), 0400, 0750, -1, -1)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		fi, err := os.Stat(filepath.Join(mnt, "a", "b"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0750), fi.Mode().Perm())
+		fi, err = os.Stat(filepath.Join(mnt, "a", "b", "c"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0400), fi.Mode().Perm())
+	})
+}
@@ -22,10 +22,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/opensvc/fcntllock"
 	"github.com/opensvc/flock"
+	"github.com/pkg/errors"
 	"opensvc.com/opensvc/core/actioncontext"
 	"opensvc.com/opensvc/core/actionrollback"
 	"opensvc.com/opensvc/core/drivergroup"
@@ -44,6 +47,7 @@ import (
 	"opensvc.com/opensvc/util/converters"
 	"opensvc.com/opensvc/util/device"
 	"opensvc.com/opensvc/util/file"
+	"opensvc.com/opensvc/util/key"
 	"opensvc.com/opensvc/util/xsession"
 )
 
@@ -69,10 +73,16 @@ type (
 		Perm        string   `json:"perm"`
 		DirPerm     string   `json:"dirperm"`
 		Signal      string   `json:"signal"`
+		CloneFrom   string   `json:"clone_from"`
 
 		Path     path.T
+		ObjectID uuid.UUID
 		Topology topology.T
 		Nodes    []string
+
+		// lastDataMtimes caches the keystores configuration files
+		// modification times at the last RefreshData call.
+		lastDataMtimes map[string]time.Time
 	}
 )
 
@@ -146,7 +156,7 @@ func (t T) Manifest() *manifest.T {
 			Attr:      "Configs",
 			Scopable:  true,
 			Converter: converters.Shlex,
-			Text:      "The whitespace separated list of ``<config name>/<key>:<volume relative path>:<options>``.",
+			Text:      "The whitespace separated list of ``<config name>/<key>:<volume relative path>:<options>``. The options are ``ro`` or ``rw``. The ``ro`` keys are installed without write permission. The symlinks found in the volume are not followed.",
 			Example:   "conf/mycnf:/etc/mysql/my.cnf:ro conf/sysctl:/etc/sysctl.d/01-db.conf",
 		},
 		{
@@ -156,7 +166,7 @@ func (t T) Manifest() *manifest.T {
 			Types:     []string{"shm"},
 			Converter: converters.Shlex,
 			Default:   "",
-			Text:      "The whitespace separated list of ``<secret name>/<key>:<volume relative path>:<options>``.",
+			Text:      "The whitespace separated list of ``<secret name>/<key>:<volume relative path>:<options>``. The options are ``ro`` or ``rw``. The ``ro`` keys are installed without write permission. The symlinks found in the volume are not followed.",
 			Example:   "cert/pem:server.pem cert/key:server.key",
 		},
 		{
@@ -203,6 +213,14 @@ func (t T) Manifest() *manifest.T {
 			Text:     "A <signal>:<target> whitespace separated list, where signal is a signal name or number (ex. 1, hup or sighup), and target is the comma separated list of resource ids to send the signal to (ex: container#1,container#2). If only the signal is specified, all candidate resources will be signaled. This keyword is usually used to reload daemons on certicate or configuration files changes.",
 			Example:  "hup:container#1",
 		},
+		{
+			Option:       "clone_from",
+			Attr:         "CloneFrom",
+			Scopable:     true,
+			Provisioning: true,
+			Text:         "A ``<vol path>@<snapshot name>`` reference to the volume snapshot to clone the volume data from on provision. The source volume must be in the object namespace, which is the default namespace of the path. The volume is allocated in the pool of the source volume, unless the ``pool`` keyword is set, in which case it must designate the same pool.",
+			Example:      "vol/db-vol-1@daily",
+		},
 	}...)
 	m.AddContext([]manifest.Context{
 		{
@@ -215,6 +233,11 @@ func (t T) Manifest() *manifest.T {
 			Attr: "Path",
 			Ref:  "object.path",
 		},
+		{
+			Key:  "object_id",
+			Attr: "ObjectID",
+			Ref:  "object.id",
+		},
 		{
 			Key:  "topology",
 			Attr: "Topology",
@@ -272,6 +295,9 @@ func (t T) Start(ctx context.Context) error {
 	actionrollback.Register(ctx, func() error {
 		return t.stopVolume(ctx, volume, false)
 	})
+	if _, err = t.installData(); err != nil {
+		return err
+	}
 	if err = t.startFlag(ctx); err != nil {
 		return err
 	}
@@ -322,6 +348,11 @@ func (t T) name() string {
 	return t.Path.Name + "-vol-" + t.ResourceID.Index()
 }
 
+// VolName returns the name of the volume object.
+func (t T) VolName() string {
+	return t.name()
+}
+
 func (t *T) Status(ctx context.Context) status.T {
 	volume, err := t.volume()
 	if err != nil {
@@ -454,9 +485,17 @@ func (t *T) poolLookup(withUsage bool) (*pool.Lookup, error) {
 	l := pool.NewLookup(node)
 	l.Name = t.Pool
 	l.Type = t.PoolType
+	if l.Name == "" && t.CloneFrom != "" {
+		src, _, err := t.cloneSource()
+		if err != nil {
+			return nil, err
+		}
+		l.Name = src.Config().GetString(key.Parse("pool"))
+	}
 	l.Size = float64(*t.Size)
 	l.Format = t.Format
 	l.Shared = t.Shared
+	l.Namespace = t.Path.Namespace
 	l.Access, err = volaccess.Parse(t.Access)
 	if err != nil {
 		return nil, err
@@ -484,6 +523,14 @@ func (t T) Label() string {
 	return t.Name
 }
 
+// StatusInfo exposes the volume name in the instance status, so the
+// volume references of the object can be read from the cluster status.
+func (t *T) StatusInfo() map[string]interface{} {
+	return map[string]interface{}{
+		"name": t.Name,
+	}
+}
+
 func (t T) ProvisionLeader(ctx context.Context) error {
 	volume, err := t.volume()
 	if err != nil {
@@ -496,9 +543,54 @@ func (t T) ProvisionLeader(ctx context.Context) error {
 	if volume, err = t.createVolume(volume); err != nil {
 		return err
 	}
+	if err = t.cloneVolume(volume); err != nil {
+		return err
+	}
 	return volume.Provision(object.OptsProvision{})
 }
 
+// cloneSource returns the source volume and snapshot name referenced
+// by the clone_from keyword.
+func (t T) cloneSource() (*object.Vol, string, error) {
+	l := strings.SplitN(t.CloneFrom, "@", 2)
+	if len(l) != 2 || l[1] == "" {
+		return nil, "", fmt.Errorf("invalid clone_from value '%s': expected <vol path>@<snapshot name>", t.CloneFrom)
+	}
+	if err := pool.ValidateSnapshotName(l[1]); err != nil {
+		return nil, "", errors.Wrapf(err, "invalid clone_from value '%s'", t.CloneFrom)
+	}
+	p, err := path.ParseIn(l[0], t.Path.Namespace)
+	if err != nil {
+		return nil, "", err
+	}
+	if p.Kind != kind.Vol {
+		return nil, "", fmt.Errorf("invalid clone_from value '%s': %s is not a vol", t.CloneFrom, p)
+	}
+	if p.Namespace != t.Path.Namespace {
+		// don't let an object read the volumes of another namespace
+		return nil, "", fmt.Errorf("invalid clone_from value '%s': %s is not in the %s namespace", t.CloneFrom, p, t.Path.Namespace)
+	}
+	src := object.NewVol(p)
+	if !src.Exists() {
+		return nil, "", fmt.Errorf("clone_from volume %s does not exist", p)
+	}
+	return src, l[1], nil
+}
+
+// cloneVolume creates the volume data from the clone_from snapshot, if
+// set, so the volume provisioning finds the data already allocated.
+func (t T) cloneVolume(volume *object.Vol) error {
+	if t.CloneFrom == "" {
+		return nil
+	}
+	src, name, err := t.cloneSource()
+	if err != nil {
+		return err
+	}
+	t.Log().Info().Msgf("clone %s from %s", volume.Path, t.CloneFrom)
+	return src.SnapshotClone(name, volume)
+}
+
 func (t T) UnprovisionLeader(ctx context.Context) error {
 	volume, err := t.volume()
 	if err != nil {
@@ -511,6 +603,22 @@ func (t T) UnprovisionLeader(ctx context.Context) error {
 	return volume.Unprovision(object.OptsUnprovision{})
 }
 
+// Resize resizes the volume to size bytes. The shrink safety check is
+// the caller responsibility.
+func (t *T) Resize(ctx context.Context, size int64) error {
+	volume, err := t.volume()
+	if err != nil {
+		return err
+	}
+	if !volume.Exists() {
+		return fmt.Errorf("volume %s does not exist", volume.Path)
+	}
+	options := object.OptsResize{}
+	options.Size = fmt.Sprintf("%d", size)
+	options.Force = true
+	return volume.Resize(options)
+}
+
 func (t T) Provisioned() (provisioned.T, error) {
 	volume, err := t.volume()
 	if err != nil {
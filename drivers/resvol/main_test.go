@@ -0,0 +1,28 @@
+package resvol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"opensvc.com/opensvc/core/path"
+)
+
+func TestCloneSourceErrors(t *testing.T) {
+	p, _ := path.Parse("prod/svc/db")
+	for _, s := range []string{
+		"vol/db-vol-1",
+		"vol/db-vol-1@",
+		"svc/db@daily",
+		"test/vol/db-vol-1@daily",
+		"root/vol/db-vol-1@daily",
+		"vol/db-vol-1@../x",
+		"vol/db-vol-1@../../db-vol-2",
+	} {
+		r := T{Path: p, CloneFrom: s}
+		_, _, err := r.cloneSource()
+		assert.Error(t, err, s)
+	}
+	r := T{Path: p, CloneFrom: "test/vol/db-vol-1@daily"}
+	_, _, err := r.cloneSource()
+	assert.Contains(t, err.Error(), "not in the prod namespace")
+}
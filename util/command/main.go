@@ -241,7 +241,12 @@ func (t *T) Cmd() *exec.Cmd {
 	return t.cmd
 }
 
+// ExitCode returns the exit code of the exited process, or -1 if the
+// process did not start or did not exit.
 func (t *T) ExitCode() int {
+	if t.cmd == nil {
+		return -1
+	}
 	return t.cmd.ProcessState.ExitCode()
 }
 
@@ -3,6 +3,7 @@ package converters
 import (
 	"errors"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -86,13 +87,26 @@ func (t TBool) Convert(s string) (interface{}, error) {
 	if s == "" {
 		return false, nil
 	}
-	return strconv.ParseBool(s)
+	return strconv.ParseBool(t.UpgradeValue(s))
 }
 
 func (t TBool) String() string {
 	return "bool"
 }
 
+// UpgradeValue returns the yes, y, on, no, n and off legacy boolean
+// values as true or false.
+func (t TBool) UpgradeValue(s string) string {
+	switch strings.ToLower(s) {
+	case "yes", "y", "on":
+		return "true"
+	case "no", "n", "off":
+		return "false"
+	default:
+		return s
+	}
+}
+
 //
 func (t TList) Convert(s string) (interface{}, error) {
 	return strings.Fields(s), nil
@@ -137,11 +151,22 @@ func (t TShlex) String() string {
 	return "shlex"
 }
 
+var (
+	longDurationRegexp = regexp.MustCompile(`([0-9]+)([dwy])`)
+	longDurationHours  = map[string]int{
+		"d": 24,
+		"w": 7 * 24,
+		"y": 365 * 24,
+	}
+)
+
 //
 // ToDuration convert duration string to *time.Duration
 //
 // nil is returned when duration is unset
 // Default unit is second when not specified
+// In addition to the time.ParseDuration units, the d (day), w (week) and
+// y (365 days) units are supported.
 //
 func (t TDuration) Convert(s string) (interface{}, error) {
 	return t.convert(s)
@@ -154,6 +179,11 @@ func (t TDuration) convert(s string) (*time.Duration, error) {
 	if _, err := strconv.Atoi(s); err == nil {
 		s = s + "s"
 	}
+	s = longDurationRegexp.ReplaceAllStringFunc(s, func(m string) string {
+		l := longDurationRegexp.FindStringSubmatch(m)
+		n, _ := strconv.Atoi(l[1])
+		return strconv.Itoa(n*longDurationHours[l[2]]) + "h"
+	})
 	duration, err := time.ParseDuration(s)
 	if err != nil {
 		return nil, err
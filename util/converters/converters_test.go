@@ -3,6 +3,7 @@ package converters
 import (
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 var (
@@ -61,3 +62,45 @@ func TestSizeConvert(t *testing.T) {
 		}
 	})
 }
+
+func TestDurationConvert(t *testing.T) {
+	validDurations := map[string]time.Duration{
+		"10":    10 * time.Second,
+		"1m30s": 90 * time.Second,
+		"2d":    48 * time.Hour,
+		"1w":    7 * 24 * time.Hour,
+		"1y":    365 * 24 * time.Hour,
+		"1d12h": 36 * time.Hour,
+	}
+	for s, expected := range validDurations {
+		t.Run(s, func(t *testing.T) {
+			result, err := Duration.Convert(s)
+			assert.Nilf(t, err, s)
+			assert.Equalf(t, expected, *result.(*time.Duration), s)
+		})
+	}
+	t.Run("invalid duration return error", func(t *testing.T) {
+		_, err := Duration.Convert("1x")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestBoolConvert(t *testing.T) {
+	for s, expected := range map[string]bool{
+		"":      false,
+		"true":  true,
+		"False": false,
+		"1":     true,
+		"yes":   true,
+		"Off":   false,
+		"n":     false,
+	} {
+		result, err := Bool.Convert(s)
+		assert.Nilf(t, err, s)
+		assert.Equalf(t, expected, result, s)
+	}
+	_, err := Bool.Convert("maybe")
+	assert.NotNil(t, err)
+	assert.Equal(t, "true", Bool.UpgradeValue("YES"))
+	assert.Equal(t, "1", Bool.UpgradeValue("1"))
+}
@@ -0,0 +1,269 @@
+// Package dnsupdate implements RFC2136 dynamic updates of A and AAAA
+// records, optionally signed with a TSIG key, and a helper waiting for
+// a name to resolve to an expected address.
+package dnsupdate
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	T struct {
+		server        string
+		zone          string
+		ttl           uint32
+		timeout       time.Duration
+		tsigName      string
+		tsigAlgorithm string
+		tsigSecret    string
+	}
+)
+
+const (
+	DefaultTTL           = 60
+	DefaultTimeout       = 5 * time.Second
+	DefaultTSIGAlgorithm = "hmac-sha256"
+)
+
+var (
+	tsigAlgorithms = map[string]string{
+		"hmac-md5":    dns.HmacMD5,
+		"hmac-sha1":   dns.HmacSHA1,
+		"hmac-sha256": dns.HmacSHA256,
+		"hmac-sha512": dns.HmacSHA512,
+	}
+)
+
+// New allocates a dynamic update client.
+func New(opts ...funcopt.O) *T {
+	t := &T{
+		ttl:           DefaultTTL,
+		timeout:       DefaultTimeout,
+		tsigAlgorithm: DefaultTSIGAlgorithm,
+	}
+	_ = funcopt.Apply(t, opts...)
+	return t
+}
+
+// WithServer sets the address of the dns server receiving the updates.
+// The port defaults to 53 if not specified.
+func WithServer(s string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.server = s
+		return nil
+	})
+}
+
+// WithZone sets the name of the zone to update.
+func WithZone(s string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.zone = s
+		return nil
+	})
+}
+
+// WithTTL sets the ttl of the registered records.
+func WithTTL(ttl uint32) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.ttl = ttl
+		return nil
+	})
+}
+
+// WithTimeout sets the timeout of each exchange with the dns server.
+func WithTimeout(timeout time.Duration) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.timeout = timeout
+		return nil
+	})
+}
+
+// WithTSIG sets the key used to sign the updates. The secret is base64
+// encoded, like in the bind key files. An empty algorithm selects the
+// hmac-sha256 algorithm.
+func WithTSIG(name, algorithm, secret string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.tsigName = name
+		if algorithm != "" {
+			t.tsigAlgorithm = algorithm
+		}
+		t.tsigSecret = secret
+		return nil
+	})
+}
+
+// Server returns the dns server address, with the port.
+func (t T) Server() string {
+	return ServerAddr(t.server)
+}
+
+// ServerAddr returns <s> with the default dns port appended if no port
+// is specified.
+func ServerAddr(s string) string {
+	if _, _, err := net.SplitHostPort(s); err == nil {
+		return s
+	}
+	return net.JoinHostPort(s, "53")
+}
+
+// RecordName returns the fqdn of the record <name> in the zone.
+// <name> is returned as a fqdn if already ending with a dot.
+func (t T) RecordName(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	if t.zone == "" {
+		return dns.Fqdn(name)
+	}
+	return dns.Fqdn(name + "." + strings.TrimSuffix(t.zone, "."))
+}
+
+func rrType(ip net.IP) uint16 {
+	if ip.To4() != nil {
+		return dns.TypeA
+	}
+	return dns.TypeAAAA
+}
+
+func (t T) rr(name string, ip net.IP, ttl uint32) dns.RR {
+	hdr := dns.RR_Header{
+		Name:   t.RecordName(name),
+		Rrtype: rrType(ip),
+		Class:  dns.ClassINET,
+		Ttl:    ttl,
+	}
+	if hdr.Rrtype == dns.TypeA {
+		return &dns.A{Hdr: hdr, A: ip.To4()}
+	}
+	return &dns.AAAA{Hdr: hdr, AAAA: ip.To16()}
+}
+
+// Register adds the A or AAAA record of <name> pointing to <ip>. A
+// record pointing to <ip> with another ttl is replaced. The records of
+// <name> pointing to other addresses, like the ones registered by the
+// other nodes of a flex service, are kept.
+func (t T) Register(name string, ip net.IP) error {
+	if ip == nil {
+		return fmt.Errorf("register %s: no ip address", name)
+	}
+	m := t.newMsg()
+	m.Remove([]dns.RR{t.rr(name, ip, 0)})
+	m.Insert([]dns.RR{t.rr(name, ip, t.ttl)})
+	return t.exchange(m)
+}
+
+// Unregister removes the A or AAAA record of <name> pointing to <ip>.
+func (t T) Unregister(name string, ip net.IP) error {
+	if ip == nil {
+		return fmt.Errorf("unregister %s: no ip address", name)
+	}
+	m := t.newMsg()
+	m.Remove([]dns.RR{t.rr(name, ip, 0)})
+	return t.exchange(m)
+}
+
+func (t T) newMsg() *dns.Msg {
+	m := new(dns.Msg)
+	m.SetUpdate(dns.Fqdn(t.zone))
+	return m
+}
+
+func (t T) exchange(m *dns.Msg) error {
+	if t.server == "" {
+		return fmt.Errorf("no dns server to send the update to")
+	}
+	if t.zone == "" {
+		return fmt.Errorf("no dns zone to update")
+	}
+	c := new(dns.Client)
+	c.Net = "tcp"
+	c.Timeout = t.timeout
+	if t.tsigName != "" {
+		algorithm, ok := tsigAlgorithms[t.tsigAlgorithm]
+		if !ok {
+			return fmt.Errorf("unsupported tsig algorithm: %s", t.tsigAlgorithm)
+		}
+		keyName := dns.Fqdn(t.tsigName)
+		c.TsigSecret = map[string]string{keyName: t.tsigSecret}
+		m.SetTsig(keyName, algorithm, 300, time.Now().Unix())
+	}
+	r, _, err := c.Exchange(m, t.Server())
+	if err != nil {
+		return err
+	}
+	if r.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("dns update refused by %s: %s", t.Server(), dns.RcodeToString[r.Rcode])
+	}
+	return nil
+}
+
+// Lookup returns the A and AAAA addresses of <name>. If <server> is
+// empty, the system resolver is used. Otherwise the queries are sent to
+// <server>.
+func Lookup(ctx context.Context, server string, name string) ([]net.IP, error) {
+	if server == "" {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		l := make([]net.IP, len(addrs))
+		for i, addr := range addrs {
+			l[i] = addr.IP
+		}
+		return l, nil
+	}
+	l := make([]net.IP, 0)
+	c := new(dns.Client)
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		m := new(dns.Msg)
+		m.SetQuestion(dns.Fqdn(name), qtype)
+		r, _, err := c.ExchangeContext(ctx, m, ServerAddr(server))
+		if err != nil {
+			return l, err
+		}
+		for _, rr := range r.Answer {
+			switch o := rr.(type) {
+			case *dns.A:
+				l = append(l, o.A)
+			case *dns.AAAA:
+				l = append(l, o.AAAA)
+			}
+		}
+	}
+	return l, nil
+}
+
+// Wait returns when <name> resolves to <ip>, or with an error when
+// <timeout> expires.
+func Wait(ctx context.Context, server string, name string, ip net.IP, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		if l, err := Lookup(ctx, server, name); err == nil {
+			for _, e := range l {
+				if e.Equal(ip) {
+					return nil
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s did not resolve to %s within %s", name, ip, timeout)
+		case <-ticker.C:
+		}
+	}
+}
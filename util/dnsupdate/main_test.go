@@ -0,0 +1,207 @@
+package dnsupdate
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testKeyName = "update-key."
+	testSecret  = "c2VjcmV0c2VjcmV0c2VjcmV0c2VjcmV0"
+)
+
+// stub is a minimal authoritative dns server accepting signed updates
+// and answering A/AAAA queries from its in-memory records.
+type stub struct {
+	sync.Mutex
+	records map[string][]dns.RR
+	addr    string
+	servers []*dns.Server
+}
+
+func newStub(t *testing.T) *stub {
+	s := &stub{records: make(map[string][]dns.RR)}
+	secret := map[string]string{testKeyName: testSecret}
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	l, err := net.Listen("tcp", pc.LocalAddr().String())
+	require.NoError(t, err)
+	s.addr = pc.LocalAddr().String()
+
+	accept := func(dh dns.Header) dns.MsgAcceptAction {
+		return dns.MsgAccept
+	}
+	udp := &dns.Server{PacketConn: pc, Handler: s, TsigSecret: secret, MsgAcceptFunc: accept}
+	tcp := &dns.Server{Listener: l, Handler: s, TsigSecret: secret, MsgAcceptFunc: accept}
+	s.servers = []*dns.Server{udp, tcp}
+	for _, srv := range s.servers {
+		started := make(chan bool)
+		srv.NotifyStartedFunc = func() { close(started) }
+		go func(srv *dns.Server) { _ = srv.ActivateAndServe() }(srv)
+		<-started
+	}
+	return s
+}
+
+func (s *stub) shutdown() {
+	for _, srv := range s.servers {
+		_ = srv.Shutdown()
+	}
+}
+
+func (s *stub) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	s.Lock()
+	defer s.Unlock()
+	m := new(dns.Msg)
+	m.SetReply(r)
+	switch r.Opcode {
+	case dns.OpcodeUpdate:
+		if r.IsTsig() == nil || w.TsigStatus() != nil {
+			m.Rcode = dns.RcodeRefused
+			break
+		}
+		for _, rr := range r.Ns {
+			hdr := rr.Header()
+			switch {
+			case hdr.Class == dns.ClassANY:
+				l := make([]dns.RR, 0)
+				for _, e := range s.records[hdr.Name] {
+					if e.Header().Rrtype != hdr.Rrtype {
+						l = append(l, e)
+					}
+				}
+				s.records[hdr.Name] = l
+			case hdr.Class == dns.ClassNONE:
+				l := make([]dns.RR, 0)
+				for _, e := range s.records[hdr.Name] {
+					c := dns.Copy(rr)
+					c.Header().Class = dns.ClassINET
+					c.Header().Ttl = e.Header().Ttl
+					if !dns.IsDuplicate(e, c) {
+						l = append(l, e)
+					}
+				}
+				s.records[hdr.Name] = l
+			default:
+				s.records[hdr.Name] = append(s.records[hdr.Name], rr)
+			}
+		}
+		m.SetTsig(testKeyName, dns.HmacSHA256, 300, time.Now().Unix())
+	default:
+		q := r.Question[0]
+		for _, rr := range s.records[q.Name] {
+			if rr.Header().Rrtype == q.Qtype {
+				m.Answer = append(m.Answer, rr)
+			}
+		}
+	}
+	_ = w.WriteMsg(m)
+}
+
+func TestRegisterUnregister(t *testing.T) {
+	s := newStub(t)
+	defer s.shutdown()
+	ip := net.ParseIP("10.0.0.1")
+	c := New(
+		WithServer(s.addr),
+		WithZone("example.com"),
+		WithTSIG("update-key", "hmac-sha256", testSecret),
+	)
+	ctx := context.Background()
+
+	require.NoError(t, c.Register("svc1", ip))
+	l, err := Lookup(ctx, s.addr, "svc1.example.com")
+	require.NoError(t, err)
+	require.Len(t, l, 1)
+	assert.True(t, l[0].Equal(ip))
+
+	t.Run("register is idempotent", func(t *testing.T) {
+		require.NoError(t, c.Register("svc1", ip))
+		l, err := Lookup(ctx, s.addr, "svc1.example.com")
+		require.NoError(t, err)
+		require.Len(t, l, 1)
+		assert.True(t, l[0].Equal(ip))
+	})
+
+	t.Run("register keeps the other nodes records", func(t *testing.T) {
+		ip2 := net.ParseIP("10.0.0.2")
+		require.NoError(t, c.Register("svc1", ip2))
+		l, err := Lookup(ctx, s.addr, "svc1.example.com")
+		require.NoError(t, err)
+		assert.Len(t, l, 2)
+		require.NoError(t, c.Unregister("svc1", ip2))
+		l, err = Lookup(ctx, s.addr, "svc1.example.com")
+		require.NoError(t, err)
+		require.Len(t, l, 1)
+		assert.True(t, l[0].Equal(ip))
+	})
+
+	t.Run("aaaa record", func(t *testing.T) {
+		ip6 := net.ParseIP("fd00::1")
+		require.NoError(t, c.Register("svc1", ip6))
+		l, err := Lookup(ctx, s.addr, "svc1.example.com")
+		require.NoError(t, err)
+		assert.Len(t, l, 2)
+		require.NoError(t, c.Unregister("svc1", ip6))
+	})
+
+	require.NoError(t, c.Unregister("svc1", ip))
+	l, err = Lookup(ctx, s.addr, "svc1.example.com")
+	require.NoError(t, err)
+	assert.Len(t, l, 0)
+}
+
+func TestRegisterBadKey(t *testing.T) {
+	s := newStub(t)
+	defer s.shutdown()
+	c := New(
+		WithServer(s.addr),
+		WithZone("example.com"),
+		WithTSIG("update-key", "hmac-sha256", "YmFkc2VjcmV0"),
+	)
+	assert.Error(t, c.Register("svc1", net.ParseIP("10.0.0.1")))
+}
+
+func TestRegisterUnsigned(t *testing.T) {
+	s := newStub(t)
+	defer s.shutdown()
+	c := New(WithServer(s.addr), WithZone("example.com"))
+	assert.Error(t, c.Register("svc1", net.ParseIP("10.0.0.1")))
+}
+
+func TestWait(t *testing.T) {
+	s := newStub(t)
+	defer s.shutdown()
+	ip := net.ParseIP("10.0.0.1")
+	c := New(
+		WithServer(s.addr),
+		WithZone("example.com"),
+		WithTSIG("update-key", "", testSecret),
+	)
+	ctx := context.Background()
+
+	err := Wait(ctx, s.addr, "svc1.example.com", ip, 1500*time.Millisecond)
+	assert.Error(t, err, "wait must time out when the record is not registered")
+
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+		_ = c.Register("svc1", ip)
+	}()
+	assert.NoError(t, Wait(ctx, s.addr, "svc1.example.com", ip, 5*time.Second))
+}
+
+func TestRecordName(t *testing.T) {
+	c := New(WithZone("example.com."))
+	assert.Equal(t, "svc1.example.com.", c.RecordName("svc1"))
+	assert.Equal(t, "svc1.other.", c.RecordName("svc1.other."))
+	assert.Equal(t, "10.0.0.1:53", ServerAddr("10.0.0.1"))
+	assert.Equal(t, "10.0.0.1:5353", ServerAddr("10.0.0.1:5353"))
+}
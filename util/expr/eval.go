@@ -0,0 +1,290 @@
+package expr
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Resolver returns the value of a reference found in an expression.
+type Resolver func(name string) (string, error)
+
+var (
+	// ErrSyntax is returned when an expression can not be parsed.
+	ErrSyntax = errors.New("expression syntax error")
+
+	// ErrEval is returned when an expression can not be evaluated, for
+	// example on a division by zero or an arithmetic on strings.
+	ErrEval = errors.New("expression evaluation error")
+)
+
+// Eval returns the string value of the expression. The reference
+// resolution errors are returned unwrapped.
+func Eval(n Node, resolve Resolver) (string, error) {
+	v, err := n.eval(resolve)
+	if e, ok := err.(resolveError); ok {
+		return "", e.error
+	} else if err != nil {
+		return "", err
+	}
+	return v.String(), nil
+}
+
+func (t literalNode) eval(_ Resolver) (value, error) {
+	return t.v, nil
+}
+
+func (t identNode) eval(resolve Resolver) (value, error) {
+	if resolve == nil {
+		return value{}, errors.Wrapf(ErrEval, "can not resolve %s", t.name)
+	}
+	s, err := resolve(t.name)
+	if err != nil {
+		return value{}, resolveError{err}
+	}
+	return stringValue(s), nil
+}
+
+// resolveError marks the reference resolution errors, so the default
+// function can fallback, and Eval can return them unwrapped.
+type resolveError struct {
+	error
+}
+
+func (t unaryNode) eval(resolve Resolver) (value, error) {
+	x, err := t.x.eval(resolve)
+	if err != nil {
+		return value{}, err
+	}
+	if t.op == "!" {
+		return boolValue(!x.truth()), nil
+	}
+	n, ok := x.numeric()
+	if !ok {
+		return value{}, errors.Wrapf(ErrEval, "can not negate a %s", x.kind)
+	}
+	n.i, n.f = -n.i, -n.f
+	return n, nil
+}
+
+func (t ternaryNode) eval(resolve Resolver) (value, error) {
+	cond, err := t.cond.eval(resolve)
+	if err != nil {
+		return value{}, err
+	}
+	if cond.truth() {
+		return t.a.eval(resolve)
+	}
+	return t.b.eval(resolve)
+}
+
+func (t indexNode) eval(resolve Resolver) (value, error) {
+	x, err := t.x.eval(resolve)
+	if err != nil {
+		return value{}, err
+	}
+	i, err := t.i.eval(resolve)
+	if err != nil {
+		return value{}, err
+	}
+	n, ok := i.numeric()
+	if !ok || n.kind != kindInt {
+		return value{}, errors.Wrapf(ErrEval, "list index must be an integer, got '%s'", i)
+	}
+	l := x.list()
+	idx := int(n.i)
+	if idx < 0 {
+		idx += len(l)
+	}
+	if idx < 0 || idx >= len(l) {
+		return value{}, errors.Wrapf(ErrEval, "list index %d out of range, the list has %d elements", n.i, len(l))
+	}
+	return stringValue(l[idx]), nil
+}
+
+func (t binaryNode) eval(resolve Resolver) (value, error) {
+	x, err := t.x.eval(resolve)
+	if err != nil {
+		return value{}, err
+	}
+	// short-circuit the logical operators
+	switch t.op {
+	case "&&":
+		if !x.truth() {
+			return boolValue(false), nil
+		}
+	case "||":
+		if x.truth() {
+			return boolValue(true), nil
+		}
+	}
+	y, err := t.y.eval(resolve)
+	if err != nil {
+		return value{}, err
+	}
+	switch t.op {
+	case "&&", "||":
+		return boolValue(y.truth()), nil
+	case "==", "!=", "<", "<=", ">", ">=":
+		return compare(t.op, x, y)
+	case "+":
+		if x.kind == kindList || y.kind == kindList {
+			return listValue(append(append([]string{}, x.list()...), y.list()...)), nil
+		}
+		nx, okx := x.numeric()
+		ny, oky := y.numeric()
+		if !okx || !oky {
+			return stringValue(x.String() + y.String()), nil
+		}
+		return arith(t.op, nx, ny)
+	default:
+		nx, okx := x.numeric()
+		ny, oky := y.numeric()
+		if !okx || !oky {
+			return value{}, errors.Wrapf(ErrEval, "operator %s expects numbers, sizes or durations, got '%s' and '%s'", t.op, x, y)
+		}
+		return arith(t.op, nx, ny)
+	}
+}
+
+// unit returns the kind of unit of a numeric value, or kindInt for the
+// numbers without unit.
+func (t value) unit() valueKind {
+	switch t.kind {
+	case kindSize, kindDuration:
+		return t.kind
+	default:
+		return kindInt
+	}
+}
+
+// magnitude returns the value as a float. A number without unit used with
+// a duration is a number of seconds, as in the duration keywords.
+func (t value) magnitude(unit valueKind) float64 {
+	switch {
+	case t.kind == kindFloat && unit == kindDuration:
+		return t.f * float64(time.Second)
+	case t.kind == kindFloat:
+		return t.f
+	case t.kind == kindInt && unit == kindDuration:
+		return float64(t.i) * float64(time.Second)
+	default:
+		return float64(t.i)
+	}
+}
+
+// arith applies the arithmetic operator to numbers, sizes and durations.
+// Sizes and durations can be added and subtracted to values of the same
+// unit or to numbers, multiplied and divided by numbers. Dividing values
+// of the same unit returns a number.
+func arith(op string, x, y value) (value, error) {
+	ux, uy := x.unit(), y.unit()
+	if ux != kindInt && uy != kindInt && ux != uy {
+		return value{}, errors.Wrapf(ErrEval, "can not mix a %s and a %s", ux, uy)
+	}
+	unit := ux
+	if unit == kindInt {
+		unit = uy
+	}
+	switch op {
+	case "*":
+		if ux != kindInt && uy != kindInt {
+			return value{}, errors.Wrapf(ErrEval, "can not multiply a %s by a %s", ux, uy)
+		}
+		// the number multiplies the unit value, it is not a
+		// number of seconds.
+		return result(unit, x.magnitude(ux)*y.magnitude(uy), x, y), nil
+	case "/":
+		if ux == kindInt && uy != kindInt {
+			return value{}, errors.Wrapf(ErrEval, "can not divide a number by a %s", uy)
+		}
+		d := y.magnitude(uy)
+		if d == 0 {
+			return value{}, errors.Wrapf(ErrEval, "division by zero")
+		}
+		if ux == uy {
+			unit = kindInt
+		}
+		n := x.magnitude(ux) / d
+		if unit == kindInt && n != math.Trunc(n) {
+			return value{kind: kindFloat, f: n}, nil
+		}
+		return result(unit, n, x, y), nil
+	case "%":
+		d := y.magnitude(unit)
+		if d == 0 {
+			return value{}, errors.Wrapf(ErrEval, "division by zero")
+		}
+		return result(unit, math.Mod(x.magnitude(unit), d), x, y), nil
+	case "-":
+		return result(unit, x.magnitude(unit)-y.magnitude(unit), x, y), nil
+	default:
+		return result(unit, x.magnitude(unit)+y.magnitude(unit), x, y), nil
+	}
+}
+
+// result returns the numeric value of unit. The sizes and durations are
+// truncated to an integer, and the numbers are floats only if an operand
+// is a float.
+func result(unit valueKind, n float64, x, y value) value {
+	switch {
+	case unit != kindInt:
+		return value{kind: unit, i: int64(n)}
+	case x.kind == kindFloat || y.kind == kindFloat:
+		return value{kind: kindFloat, f: n}
+	default:
+		return intValue(int64(n))
+	}
+}
+
+// compare applies the comparison operator. The values are compared as
+// numbers if both are numeric, as booleans if one is a boolean, else as
+// strings.
+func compare(op string, x, y value) (value, error) {
+	var c int
+	nx, okx := x.numeric()
+	ny, oky := y.numeric()
+	switch {
+	case okx && oky:
+		ux, uy := nx.unit(), ny.unit()
+		if ux != kindInt && uy != kindInt && ux != uy {
+			return value{}, errors.Wrapf(ErrEval, "can not compare a %s and a %s", ux, uy)
+		}
+		unit := ux
+		if unit == kindInt {
+			unit = uy
+		}
+		a, b := nx.magnitude(unit), ny.magnitude(unit)
+		switch {
+		case a < b:
+			c = -1
+		case a > b:
+			c = 1
+		}
+	case x.kind == kindBool || y.kind == kindBool:
+		if op != "==" && op != "!=" {
+			return value{}, errors.Wrapf(ErrEval, "operator %s does not apply to booleans", op)
+		}
+		if x.truth() != y.truth() {
+			c = 1
+		}
+	default:
+		c = strings.Compare(x.String(), y.String())
+	}
+	switch op {
+	case "==":
+		return boolValue(c == 0), nil
+	case "!=":
+		return boolValue(c != 0), nil
+	case "<":
+		return boolValue(c < 0), nil
+	case "<=":
+		return boolValue(c <= 0), nil
+	case ">":
+		return boolValue(c > 0), nil
+	default:
+		return boolValue(c >= 0), nil
+	}
+}
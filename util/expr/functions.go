@@ -0,0 +1,127 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"opensvc.com/opensvc/util/xstrings"
+)
+
+type (
+	function struct {
+		minArgs int
+		maxArgs int
+		fn      func([]value) (value, error)
+	}
+)
+
+// functions is the table of the functions usable in expressions, indexed
+// by name. The default function is evaluated by callNode.eval, because its
+// arguments are evaluated lazily.
+var functions = map[string]function{
+	"upper":      stringFunction(strings.ToUpper),
+	"lower":      stringFunction(strings.ToLower),
+	"capitalize": stringFunction(xstrings.Capitalize),
+	"title":      stringFunction(strings.Title),
+	"swapcase":   stringFunction(xstrings.SwapCase),
+	"replace": {
+		minArgs: 3,
+		maxArgs: 3,
+		fn: func(args []value) (value, error) {
+			return stringValue(strings.ReplaceAll(args[0].String(), args[1].String(), args[2].String())), nil
+		},
+	},
+	"split": {
+		minArgs: 1,
+		maxArgs: 2,
+		fn: func(args []value) (value, error) {
+			s := args[0].String()
+			if len(args) == 1 {
+				return listValue(strings.Fields(s)), nil
+			}
+			sep := args[1].String()
+			if sep == "" {
+				return value{}, errors.Wrapf(ErrEval, "split separator is empty")
+			}
+			if s == "" {
+				return listValue([]string{}), nil
+			}
+			return listValue(strings.Split(s, sep)), nil
+		},
+	},
+	"join": {
+		minArgs: 1,
+		maxArgs: 2,
+		fn: func(args []value) (value, error) {
+			sep := " "
+			if len(args) == 2 {
+				sep = args[1].String()
+			}
+			return stringValue(strings.Join(args[0].list(), sep)), nil
+		},
+	},
+	"len": {
+		minArgs: 1,
+		maxArgs: 1,
+		fn: func(args []value) (value, error) {
+			// the references values are lists, like the list keywords
+			return intValue(int64(len(args[0].list()))), nil
+		},
+	},
+	"default": {
+		minArgs: 2,
+		maxArgs: 2,
+	},
+}
+
+func stringFunction(f func(string) string) function {
+	return function{
+		minArgs: 1,
+		maxArgs: 1,
+		fn: func(args []value) (value, error) {
+			return stringValue(f(args[0].String())), nil
+		},
+	}
+}
+
+// arity returns the accepted number of arguments, for error messages.
+func (t function) arity() string {
+	if t.minArgs == t.maxArgs {
+		return strconv.Itoa(t.minArgs)
+	}
+	return fmt.Sprintf("%d to %d", t.minArgs, t.maxArgs)
+}
+
+func (t callNode) eval(resolve Resolver) (value, error) {
+	if t.name == "default" {
+		return t.evalDefault(resolve)
+	}
+	args := make([]value, len(t.args))
+	for i, arg := range t.args {
+		v, err := arg.eval(resolve)
+		if err != nil {
+			return value{}, err
+		}
+		args[i] = v
+	}
+	return functions[t.name].fn(args)
+}
+
+// evalDefault returns the first argument value, or the second argument
+// value if the first is empty or references an unresolvable value.
+func (t callNode) evalDefault(resolve Resolver) (value, error) {
+	v, err := t.args[0].eval(resolve)
+	switch err.(type) {
+	case nil:
+		if !v.isEmpty() {
+			return v, nil
+		}
+	case resolveError:
+	default:
+		return value{}, err
+	}
+	return t.args[1].eval(resolve)
+}
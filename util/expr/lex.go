@@ -0,0 +1,121 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	tokenKind int
+
+	token struct {
+		kind tokenKind
+		text string
+		pos  int
+	}
+)
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOp
+)
+
+// operators is the list of operators and punctuations, the longest first
+// so the lexer matches "<=" before "<".
+var operators = []string{
+	"==", "!=", "<=", ">=", "&&", "||",
+	"+", "-", "*", "/", "%", "<", ">", "!", "?", ":", "(", ")", "[", "]", ",",
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	default:
+		return fmt.Sprintf("'%s'", t.text)
+	}
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return r == '_' || r == '.' || r == '#' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// lex splits the expression source into tokens. The {<ref>} references
+// are returned as identifiers, so the expressions can use the same
+// reference syntax as the keyword values.
+func lex(s string) ([]token, error) {
+	l := make([]token, 0)
+	r := []rune(s)
+	for i := 0; i < len(r); {
+		c := r[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(r) && (unicode.IsDigit(r[j]) || unicode.IsLetter(r[j]) || r[j] == '.') {
+				j++
+			}
+			l = append(l, token{kind: tokenNumber, text: string(r[i:j]), pos: i})
+			i = j
+		case isIdentStart(c):
+			j := i
+			for j < len(r) && isIdentPart(r[j]) {
+				j++
+			}
+			l = append(l, token{kind: tokenIdent, text: string(r[i:j]), pos: i})
+			i = j
+		case c == '{':
+			j := i + 1
+			for j < len(r) && r[j] != '}' {
+				j++
+			}
+			if j == len(r) {
+				return nil, errors.Wrapf(ErrSyntax, "position %d: unterminated reference", i)
+			}
+			l = append(l, token{kind: tokenIdent, text: string(r[i+1 : j]), pos: i})
+			i = j + 1
+		case c == '"' || c == '\'':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(r) && r[j] != c; j++ {
+				if r[j] == '\\' && j+1 < len(r) {
+					j++
+				}
+				b.WriteRune(r[j])
+			}
+			if j == len(r) {
+				return nil, errors.Wrapf(ErrSyntax, "position %d: unterminated string", i)
+			}
+			l = append(l, token{kind: tokenString, text: b.String(), pos: i})
+			i = j + 1
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(string(r[i:]), o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, errors.Wrapf(ErrSyntax, "position %d: unexpected character '%c'", i, c)
+			}
+			l = append(l, token{kind: tokenOp, text: op, pos: i})
+			i += len([]rune(op))
+		}
+	}
+	l = append(l, token{kind: tokenEOF, pos: len(r)})
+	return l, nil
+}
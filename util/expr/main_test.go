@@ -0,0 +1,204 @@
+package expr
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testResolver(name string) (string, error) {
+	switch name {
+	case "pool.size":
+		return "10G", nil
+	case "vol.size":
+		return "10m", nil
+	case "node.env":
+		return "PRD", nil
+	case "nodes":
+		return "n1 n2 n3", nil
+	case "fs#1.mnt":
+		return "/srv/svc1/data", nil
+	case "empty":
+		return "", nil
+	default:
+		return "", fmt.Errorf("unknown reference %s", name)
+	}
+}
+
+func TestEval(t *testing.T) {
+	cases := map[string]string{
+		`1 + 2 * 3`:                              "7",
+		`(1 + 2) * 3`:                            "9",
+		`7 / 2`:                                  "3.5",
+		`8 / 2`:                                  "4",
+		`7 % 4`:                                  "3",
+		`-2 + 1`:                                 "-1",
+		`1.5 * 2`:                                "3",
+		`{pool.size} / 2`:                        "5368709120",
+		`pool.size / 2`:                          "5368709120",
+		`1G + 512M`:                              "1610612736",
+		`2G / 512M`:                              "4",
+		`1h30m / 2`:                              "45m0s",
+		`1h + 30`:                                "1h0m30s",
+		`90min / 1h`:                             "1.5",
+		`1h > 59min`:                             "true",
+		`10min * 3`:                              "30m0s",
+		`1h30m > 1h29m59s`:                       "true",
+		`512m + 512m`:                            "1073741824",
+		`1.5m`:                                   "1572864",
+		`{vol.size} / 2`:                         "5242880",
+		`vol.size + 1G`:                          "1084227584",
+		`node.env == "PRD" ? "high" : "low"`:     "high",
+		`node.env != "PRD" ? "high" : "low"`:     "low",
+		`1 < 2 && 2 < 1`:                         "false",
+		`1 < 2 || missing`:                       "true",
+		`!false`:                                 "true",
+		`"a" + "b"`:                              "ab",
+		`upper(node.env)`:                        "PRD",
+		`lower(node.env)`:                        "prd",
+		`capitalize("foo")`:                      "Foo",
+		`replace(fs#1.mnt, "/srv", "/data")`:     "/data/svc1/data",
+		`split(fs#1.mnt, "/")[2]`:                "svc1",
+		`join(split(fs#1.mnt, "/"), "-")`:        "-srv-svc1-data",
+		`join(nodes, ",")`:                       "n1,n2,n3",
+		`nodes[0]`:                               "n1",
+		`nodes[-1]`:                              "n3",
+		`len(nodes)`:                             "3",
+		`len("a b c")`:                           "3",
+		`default(missing, "blue")`:               "blue",
+		`default(empty, "blue")`:                 "blue",
+		`default(node.env, "blue")`:              "PRD",
+		`default({empty}, nodes[1])`:             "n2",
+		`nodes + split("n4")`:                    "n1 n2 n3 n4",
+		`node.env == "PRD" ? pool.size / 2 : 1G`: "5368709120",
+	}
+	for s, expected := range cases {
+		t.Run(s, func(t *testing.T) {
+			n, err := Parse(s)
+			require.NoError(t, err)
+			v, err := Eval(n, testResolver)
+			require.NoError(t, err)
+			assert.Equal(t, expected, v)
+		})
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	cases := []string{
+		`1 / 0`,
+		`1G + 1h`,
+		`10m + 1h`,
+		`1h * 1h`,
+		`2 / 1h`,
+		`"a" * 2`,
+		`nodes[3]`,
+		`nodes["a"]`,
+		`true < false`,
+	}
+	for _, s := range cases {
+		t.Run(s, func(t *testing.T) {
+			n, err := Parse(s)
+			require.NoError(t, err)
+			_, err = Eval(n, testResolver)
+			assert.Equal(t, ErrEval, errors.Cause(err))
+		})
+	}
+	t.Run("resolver errors are returned unwrapped", func(t *testing.T) {
+		errResolve := errors.New("resolve")
+		n, err := Parse(`1 + missing`)
+		require.NoError(t, err)
+		_, err = Eval(n, func(string) (string, error) { return "", errResolve })
+		assert.Equal(t, errResolve, err)
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []string{
+		``,
+		`1 +`,
+		`(1 + 2`,
+		`1 2`,
+		`"abc`,
+		`{abc`,
+		`a ? b`,
+		`1x`,
+		`nope(1)`,
+		`replace("a")`,
+		`@`,
+	}
+	for _, s := range cases {
+		t.Run(s, func(t *testing.T) {
+			_, err := Parse(s)
+			assert.Equal(t, ErrSyntax, errors.Cause(err))
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	t.Run("text only", func(t *testing.T) {
+		l, err := Split("/srv/{namespace}/{name}")
+		require.NoError(t, err)
+		require.Len(t, l, 1)
+		assert.Nil(t, l[0].Expr)
+		assert.Equal(t, "/srv/{namespace}/{name}", l[0].Text)
+	})
+	t.Run("shell, awk and json blocks are text", func(t *testing.T) {
+		for _, s := range []string{
+			"echo ${FOO} && f() { true; }",
+			"awk '{print $1}'",
+			`{"a": 1, "b": [1, 2]}`,
+			"${a == b}",
+			`sh -c "{ true && echo ok; }"`,
+			"{ test -f /tmp/x || touch /tmp/x; }",
+			"{ a ? b; }",
+			"{node.env == }",
+			"{print(a)}",
+		} {
+			l, err := Split(s)
+			require.NoError(t, err, s)
+			require.Len(t, l, 1, s)
+			assert.Nil(t, l[0].Expr, s)
+			assert.Equal(t, s, l[0].Text)
+		}
+	})
+	t.Run("expressions", func(t *testing.T) {
+		l, err := Split(`size=$(({pool.size} / (1 + 1))) prio={node.env == "PRD" ? "a}" : "b"}.`)
+		require.NoError(t, err)
+		require.Len(t, l, 5)
+		assert.Equal(t, "size=", l[0].Text)
+		assert.Equal(t, "$(({pool.size} / (1 + 1)))", l[1].Text)
+		assert.NotNil(t, l[1].Expr)
+		assert.Equal(t, " prio=", l[2].Text)
+		assert.Equal(t, `{node.env == "PRD" ? "a}" : "b"}`, l[3].Text)
+		assert.NotNil(t, l[3].Expr)
+		assert.Equal(t, ".", l[4].Text)
+	})
+	t.Run("$((...)) expressions", func(t *testing.T) {
+		l, err := Split(`prio=$((node.env == "PRD" ? "a))" : "b")).`)
+		require.NoError(t, err)
+		require.Len(t, l, 3)
+		assert.Equal(t, `$((node.env == "PRD" ? "a))" : "b"))`, l[1].Text)
+		assert.NotNil(t, l[1].Expr)
+	})
+	t.Run("function call", func(t *testing.T) {
+		l, err := Split(`{default({env.color}, "blue")}`)
+		require.NoError(t, err)
+		require.Len(t, l, 1)
+		v, err := Eval(l[0].Expr, testResolver)
+		require.NoError(t, err)
+		assert.Equal(t, "blue", v)
+	})
+	t.Run("parse errors", func(t *testing.T) {
+		for _, s := range []string{
+			"$((1 +))",
+			"$((1 + 2)",
+			"$((node.env == ))",
+		} {
+			_, err := Split(s)
+			assert.Equal(t, ErrSyntax, errors.Cause(err), s)
+		}
+	})
+}
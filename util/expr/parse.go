@@ -0,0 +1,267 @@
+package expr
+
+import (
+	"github.com/pkg/errors"
+)
+
+type (
+	// Node is a parsed expression.
+	Node interface {
+		eval(Resolver) (value, error)
+	}
+
+	literalNode struct {
+		v value
+	}
+
+	identNode struct {
+		name string
+	}
+
+	unaryNode struct {
+		op string
+		x  Node
+	}
+
+	binaryNode struct {
+		op   string
+		x, y Node
+	}
+
+	ternaryNode struct {
+		cond, a, b Node
+	}
+
+	callNode struct {
+		name string
+		args []Node
+	}
+
+	indexNode struct {
+		x, i Node
+	}
+
+	parser struct {
+		tokens []token
+		i      int
+	}
+)
+
+// Parse returns the expression parsed from s.
+//
+// The operators, from the lowest to the highest precedence, are:
+//
+//	c ? a : b       conditional
+//	||              logical or
+//	&&              logical and
+//	== != < <= > >= comparison
+//	+ -             addition, subtraction or concatenation
+//	* / %           multiplication, division, modulo
+//	- !             negation, logical not
+//	x[i]            list indexing
+//
+// The operands are numbers, sizes like 10G, 512m or 512MiB, durations like
+// 90min or 1h30m, quoted strings, true, false, function calls and references to other
+// keywords or well-known values, written bare like node.env or fs#1.size,
+// or braced like {sec:name/key}.
+func Parse(s string) (Node, error) {
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	n, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.unexpected(tok)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.i]
+	if tok.kind != tokenEOF {
+		p.i++
+	}
+	return tok
+}
+
+// accept consumes and returns the next token if it is one of the ops.
+func (p *parser) accept(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokenOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.i++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		return errors.Wrapf(ErrSyntax, "position %d: expected '%s', got %s", p.peek().pos, op, p.peek())
+	}
+	return nil
+}
+
+func (p *parser) unexpected(tok token) error {
+	return errors.Wrapf(ErrSyntax, "position %d: unexpected %s", tok.pos, tok)
+}
+
+func (p *parser) parseTernary() (Node, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("?"); !ok {
+		return cond, nil
+	}
+	a, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	b, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	return ternaryNode{cond: cond, a: a, b: b}, nil
+}
+
+// binaryLevels is the list of binary operators, by increasing precedence.
+var binaryLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) parseBinary(level int) (Node, error) {
+	if level == len(binaryLevels) {
+		return p.parseUnary()
+	}
+	x, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(binaryLevels[level]...)
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = binaryNode{op: op, x: x, y: y}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if op, ok := p.accept("-", "!"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: op, x: x}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (Node, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("["); !ok {
+			return x, nil
+		}
+		i, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		x = indexNode{x: x, i: i}
+	}
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		v, err := parseLiteral(tok.text)
+		if err != nil {
+			return nil, errors.Wrapf(ErrSyntax, "position %d: %s", tok.pos, err)
+		}
+		return literalNode{v: v}, nil
+	case tokenString:
+		return literalNode{v: stringValue(tok.text)}, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return literalNode{v: boolValue(true)}, nil
+		case "false":
+			return literalNode{v: boolValue(false)}, nil
+		}
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(tok)
+		}
+		return identNode{name: tok.text}, nil
+	case tokenOp:
+		if tok.text == "(" {
+			x, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		}
+	}
+	return nil, p.unexpected(tok)
+}
+
+func (p *parser) parseCall(tok token) (Node, error) {
+	f, ok := functions[tok.text]
+	if !ok {
+		return nil, errors.Wrapf(ErrSyntax, "position %d: unknown function %s", tok.pos, tok.text)
+	}
+	n := callNode{name: tok.text}
+	if _, ok := p.accept(")"); !ok {
+		for {
+			arg, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			n.args = append(n.args, arg)
+			if _, ok := p.accept(","); ok {
+				continue
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	if len(n.args) < f.minArgs || len(n.args) > f.maxArgs {
+		return nil, errors.Wrapf(ErrSyntax, "position %d: %s expects %s arguments, got %d", tok.pos, tok.text, f.arity(), len(n.args))
+	}
+	return n, nil
+}
@@ -0,0 +1,115 @@
+package expr
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// Segment is a part of a keyword value. Expr is nil for the text
+	// segments, and Text is the expression source, delimiters included,
+	// for the expression segments.
+	Segment struct {
+		Text string
+		Expr Node
+	}
+)
+
+// Split returns the text and expression segments of s. The expressions
+// are the $((...)) blocks, and the {...} blocks not preceded by a $ whose
+// content parses as an expression using an operator, an index or a
+// function call, for example:
+//
+//	$(({pool.size} / 2))
+//	{node.env == "PRD" ? "high" : "low"}
+//	{default({env.color}, "blue")}
+//
+// The other {...} blocks, like the references, the shell brace groups,
+// the awk or json blocks, are kept as text.
+//
+// An error is returned if a $((...)) expression can not be parsed.
+func Split(s string) ([]Segment, error) {
+	l := make([]Segment, 0)
+	text := 0
+	for i := 0; i < len(s); {
+		var (
+			n   Node
+			end int
+		)
+		switch {
+		case strings.HasPrefix(s[i:], "$(("):
+			var ok bool
+			end, ok = closingIndex(s, i+3, '(', ')')
+			if !ok || end+1 >= len(s) || s[end+1] != ')' {
+				return nil, errors.Wrapf(ErrSyntax, "position %d: unterminated $((", i)
+			}
+			end++
+			var err error
+			if n, err = Parse(s[i+3 : end-1]); err != nil {
+				return nil, errors.Wrapf(err, "%s", s[i:end+1])
+			}
+		case s[i] == '{' && (i == 0 || s[i-1] != '$'):
+			var ok bool
+			if end, ok = closingIndex(s, i+1, '{', '}'); ok {
+				n = braceExpression(s[i+1 : end])
+			}
+		}
+		if n == nil {
+			i++
+			continue
+		}
+		if i > text {
+			l = append(l, Segment{Text: s[text:i]})
+		}
+		l = append(l, Segment{Text: s[i : end+1], Expr: n})
+		i = end + 1
+		text = i
+	}
+	if text < len(s) {
+		l = append(l, Segment{Text: s[text:]})
+	}
+	return l, nil
+}
+
+// braceExpression returns the expression parsed from a {...} block
+// content, or nil if the content does not parse or is a bare reference
+// or literal.
+func braceExpression(s string) Node {
+	n, err := Parse(s)
+	if err != nil {
+		return nil
+	}
+	switch n.(type) {
+	case identNode, literalNode:
+		return nil
+	}
+	return n
+}
+
+// closingIndex returns the index of the close byte matching an opening
+// already consumed before start, skipping the quoted strings and the
+// nested open/close pairs.
+func closingIndex(s string, start int, open, close byte) (int, bool) {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\'':
+			j := i + 1
+			for ; j < len(s) && s[j] != c; j++ {
+				if s[j] == '\\' {
+					j++
+				}
+			}
+			i = j
+		case open:
+			depth++
+		case close:
+			if depth == 0 {
+				return i, true
+			}
+			depth--
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,177 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"opensvc.com/opensvc/util/converters"
+	"opensvc.com/opensvc/util/sizeconv"
+)
+
+type (
+	valueKind int
+
+	// value is the result of an expression evaluation. Sizes are byte
+	// counts and durations are nanosecond counts, stored in i.
+	value struct {
+		kind valueKind
+		s    string
+		i    int64
+		f    float64
+		b    bool
+		l    []string
+	}
+)
+
+const (
+	kindString valueKind = iota
+	kindInt
+	kindFloat
+	kindBool
+	kindList
+	kindSize
+	kindDuration
+)
+
+var (
+	// durationRegexp matches the literals made only of duration units.
+	// The other literals with a unit, like 10M or 2GiB, are sizes.
+	durationRegexp = regexp.MustCompile(`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|min|s|m|h|d|w|y))+$`)
+
+	// mebibytesRegexp matches the literals with a bare m unit, which are
+	// MiB sizes like in the size keywords, not minutes. The minutes are
+	// written min, or m in a mixed duration like 1h30m.
+	mebibytesRegexp = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?m$`)
+)
+
+func stringValue(s string) value {
+	return value{kind: kindString, s: s}
+}
+
+func boolValue(b bool) value {
+	return value{kind: kindBool, b: b}
+}
+
+func intValue(i int64) value {
+	return value{kind: kindInt, i: i}
+}
+
+func listValue(l []string) value {
+	return value{kind: kindList, l: l}
+}
+
+// parseLiteral returns the number, size or duration value of s.
+func parseLiteral(s string) (value, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return intValue(i), nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return value{kind: kindFloat, f: f}, nil
+	}
+	if durationRegexp.MatchString(s) && !mebibytesRegexp.MatchString(s) {
+		d, err := converters.Duration.Convert(strings.ReplaceAll(s, "min", "m"))
+		if err != nil {
+			return value{}, err
+		}
+		return value{kind: kindDuration, i: int64(*d.(*time.Duration))}, nil
+	}
+	if i, err := sizeconv.FromSize(s); err == nil {
+		return value{kind: kindSize, i: i}, nil
+	}
+	return value{}, fmt.Errorf("invalid number, size or duration '%s'", s)
+}
+
+// String returns the value as written in a keyword value. Sizes are
+// byte counts, and durations use the h, m and s units.
+func (t value) String() string {
+	switch t.kind {
+	case kindInt, kindSize:
+		return strconv.FormatInt(t.i, 10)
+	case kindFloat:
+		return strconv.FormatFloat(t.f, 'f', -1, 64)
+	case kindBool:
+		return strconv.FormatBool(t.b)
+	case kindList:
+		return strings.Join(t.l, " ")
+	case kindDuration:
+		return time.Duration(t.i).String()
+	default:
+		return t.s
+	}
+}
+
+// numeric returns the value as a number, size or duration. The strings,
+// like the values of references, are parsed as literals.
+func (t value) numeric() (value, bool) {
+	switch t.kind {
+	case kindInt, kindFloat, kindSize, kindDuration:
+		return t, true
+	case kindString:
+		v, err := parseLiteral(strings.TrimSpace(t.s))
+		return v, err == nil
+	default:
+		return t, false
+	}
+}
+
+// list returns the value as a list. The strings are split on spaces, like
+// the list keyword values.
+func (t value) list() []string {
+	switch t.kind {
+	case kindList:
+		return t.l
+	default:
+		return strings.Fields(t.String())
+	}
+}
+
+// truth returns the value as a boolean. The empty strings, lists and the
+// zero numbers are false, like the strings parsed as a false boolean.
+func (t value) truth() bool {
+	switch t.kind {
+	case kindBool:
+		return t.b
+	case kindInt, kindSize, kindDuration:
+		return t.i != 0
+	case kindFloat:
+		return t.f != 0
+	case kindList:
+		return len(t.l) > 0
+	default:
+		if b, err := converters.Bool.Convert(t.s); err == nil {
+			return b.(bool)
+		}
+		return t.s != ""
+	}
+}
+
+func (t value) isEmpty() bool {
+	switch t.kind {
+	case kindString:
+		return t.s == ""
+	case kindList:
+		return len(t.l) == 0
+	default:
+		return false
+	}
+}
+
+func (t valueKind) String() string {
+	switch t {
+	case kindInt, kindFloat:
+		return "number"
+	case kindBool:
+		return "boolean"
+	case kindList:
+		return "list"
+	case kindSize:
+		return "size"
+	case kindDuration:
+		return "duration"
+	default:
+		return "string"
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/command"
 )
 
 func extCanFSCK() error {
@@ -63,3 +66,46 @@ func xMKFS(x string, s string) error {
 		return fmt.Errorf("%s exit code %d", cmd, exitCode)
 	}
 }
+
+// extResize resizes the filesystem on the dev device to size bytes.
+// Growing is supported online, shrinking requires the filesystem to be
+// unmounted. An unmounted filesystem is checked before the resize, as
+// resize2fs refuses to resize a filesystem not checked since its last
+// mount.
+func extResize(log *zerolog.Logger, dev string, mnt string, size int64) error {
+	if _, err := exec.LookPath("resize2fs"); err != nil {
+		return fmt.Errorf("resize2fs not found")
+	}
+	if mnt == "" {
+		if err := extForcedFSCK(log, dev); err != nil {
+			return err
+		}
+	}
+	cmd := command.New(
+		command.WithName("resize2fs"),
+		command.WithVarArgs(dev, fmt.Sprintf("%dK", size/1024)),
+		command.WithLogger(log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	return cmd.Run()
+}
+
+// extForcedFSCK checks the unmounted filesystem on the dev device, even
+// if it seems clean. The exit code 1 means errors were corrected.
+func extForcedFSCK(log *zerolog.Logger, dev string) error {
+	if err := extCanFSCK(); err != nil {
+		return fmt.Errorf("e2fsck not found")
+	}
+	cmd := command.New(
+		command.WithName("e2fsck"),
+		command.WithVarArgs("-f", "-p", dev),
+		command.WithLogger(log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+		command.WithIgnoredExitCodes(0, 1),
+	)
+	return cmd.Run()
+}
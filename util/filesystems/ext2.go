@@ -30,3 +30,7 @@ func (t T_Ext2) IsFormated(s string) (bool, error) {
 func (t T_Ext2) MKFS(s string) error {
 	return xMKFS("mkfs.ext2", s)
 }
+
+func (t T_Ext2) Resize(dev string, mnt string, size int64) error {
+	return extResize(t.log, dev, mnt, size)
+}
@@ -0,0 +1,62 @@
+package filesystems
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// extTestImage returns the path of a sizeMB file formatted as ext4,
+// flagged as mounted since its last check.
+func extTestImage(t *testing.T, sizeMB int64) string {
+	for _, s := range []string{"mkfs.ext4", "debugfs", "tune2fs", "e2fsck", "resize2fs"} {
+		if _, err := exec.LookPath(s); err != nil {
+			t.Skipf("%s not found", s)
+		}
+	}
+	p := filepath.Join(t.TempDir(), "img")
+	require.NoError(t, ioutil.WriteFile(p, nil, 0600))
+	require.NoError(t, os.Truncate(p, sizeMB*1024*1024))
+	require.NoError(t, exec.Command("mkfs.ext4", "-F", "-q", "-b", "1024", p).Run())
+	require.NoError(t, exec.Command("debugfs", "-w", "-R", "ssv lastcheck 20200101000000", p).Run())
+	require.NoError(t, exec.Command("debugfs", "-w", "-R", "ssv mtime now", p).Run())
+	return p
+}
+
+// extTestSize returns the size in bytes of the ext filesystem in p.
+func extTestSize(t *testing.T, p string) int64 {
+	b, err := exec.Command("tune2fs", "-l", p).Output()
+	require.NoError(t, err)
+	get := func(name string) int64 {
+		m := regexp.MustCompile(fmt.Sprintf(`(?m)^%s:\s+(\d+)$`, name)).FindSubmatch(b)
+		require.NotNil(t, m, name)
+		i, err := strconv.ParseInt(string(m[1]), 10, 64)
+		require.NoError(t, err)
+		return i
+	}
+	return get("Block count") * get("Block size")
+}
+
+func TestExtResizeOffline(t *testing.T) {
+	log := zerolog.Nop()
+	t.Run("grow", func(t *testing.T) {
+		p := extTestImage(t, 32)
+		require.NoError(t, os.Truncate(p, 64*1024*1024))
+		require.NoError(t, extResize(&log, p, "", 64*1024*1024))
+		assert.Equal(t, int64(64*1024*1024), extTestSize(t, p))
+	})
+	t.Run("shrink", func(t *testing.T) {
+		p := extTestImage(t, 64)
+		require.NoError(t, extResize(&log, p, "", 48*1024*1024))
+		assert.Equal(t, int64(48*1024*1024), extTestSize(t, p))
+	})
+}
@@ -2,6 +2,7 @@ package filesystems
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/rs/zerolog"
 	"opensvc.com/opensvc/util/device"
@@ -47,6 +48,12 @@ type (
 	MKFSer interface {
 		MKFS(string, []string) error
 	}
+	// Resizer is implemented by filesystems able to change their size
+	// to a size in bytes. The mount point is empty if the filesystem is
+	// not mounted.
+	Resizer interface {
+		Resize(dev string, mnt string, size int64) error
+	}
 )
 
 var (
@@ -194,6 +201,16 @@ func DevicesFormated(fs interface{}, dl subDeviceLister) (bool, error) {
 	return true, nil
 }
 
+// Resize changes the size of the filesystem to size bytes, if the
+// filesystem implements the Resizer interface.
+func Resize(fs interface{}, dev string, mnt string, size int64) error {
+	i, ok := fs.(Resizer)
+	if !ok {
+		return fmt.Errorf("%s does not support resizing", fs)
+	}
+	return i.Resize(dev, mnt, size)
+}
+
 func FromType(s string) I {
 	if t, ok := db[s]; ok {
 		return t.(I)
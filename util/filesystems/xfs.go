@@ -7,6 +7,7 @@ import (
 
 	"github.com/rs/zerolog"
 	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/df"
 )
 
 type (
@@ -54,3 +55,30 @@ func (t T_XFS) MKFS(devpath string, args []string) error {
 	)
 	return cmd.Run()
 }
+
+// Resize grows the mounted filesystem to the size of its device, as xfs
+// filesystems can only be grown, and only online.
+func (t T_XFS) Resize(dev string, mnt string, size int64) error {
+	if mnt == "" {
+		return fmt.Errorf("xfs on %s must be mounted to be resized", dev)
+	}
+	if _, err := exec.LookPath("xfs_growfs"); err != nil {
+		return fmt.Errorf("xfs_growfs not found")
+	}
+	entries, err := df.MountUsage(mnt)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 && entries[0].Total*1024 > size {
+		return fmt.Errorf("xfs on %s can not be shrunk", dev)
+	}
+	cmd := command.New(
+		command.WithName("xfs_growfs"),
+		command.WithVarArgs(mnt),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	return cmd.Run()
+}
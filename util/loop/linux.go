@@ -151,6 +151,23 @@ func (t T) Delete(devPath string) error {
 
 }
 
+// SetCapacity makes the loop device reread the size of its backing file.
+func (t T) SetCapacity(devPath string) error {
+	cmd := command.New(
+		command.WithName(losetup),
+		command.WithVarArgs("-c", devPath),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	cmd.Run()
+	if cmd.ExitCode() != 0 {
+		return fmt.Errorf("%s error %d", cmd, cmd.ExitCode())
+	}
+	return nil
+}
+
 func (t InfoEntries) File(s string) *InfoEntry {
 	for _, i := range t {
 		if i.BackFile == s {
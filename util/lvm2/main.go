@@ -5,6 +5,7 @@ package lvm2
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -27,7 +28,7 @@ type (
 		LVName          string `json:"lv_name"`
 		VGName          string `json:"vg_name"`
 		LVAttr          string `json:"lv_attr"`
-		LVSize          string `json:"lv_name"`
+		LVSize          string `json:"lv_size"`
 		Origin          string `json:"origin"`
 		DataPercent     string `json:"data_percent"`
 		CopyPercent     string `json:"copy_percent"`
@@ -36,6 +37,7 @@ type (
 		ConvertPV       string `json:"convert_pv"`
 		MirrorLog       string `json:"mirror_log"`
 		Devices         string `json:"devices"`
+		LVTime          string `json:"lv_time"`
 	}
 	driver struct{}
 	LV     struct {
@@ -96,8 +98,12 @@ func NewLV(vg string, lv string, opts ...funcopt.O) *LV {
 }
 func WithLogger(log *zerolog.Logger) funcopt.O {
 	return funcopt.F(func(i interface{}) error {
-		t := i.(*LV)
-		t.log = log
+		switch t := i.(type) {
+		case *LV:
+			t.log = log
+		case *VG:
+			t.log = log
+		}
 		return nil
 	})
 }
@@ -258,6 +264,142 @@ func (t *LV) Create(size string, args []string) error {
 	return nil
 }
 
+// CreateThin creates a thin logical volume of virtual size in the thin
+// pool logical volume of the volume group.
+func (t *LV) CreateThin(size string, pool string, args []string) error {
+	if i, err := sizeconv.FromSize(size); err == nil {
+		size = fmt.Sprintf("%dB", i)
+	}
+	cmd := command.New(
+		command.WithName("lvcreate"),
+		command.WithArgs(append(args, "--yes", "-V", size, "--thinpool", pool, "-n", t.LVName, t.VGName)),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	cmd.Run()
+	if cmd.ExitCode() != 0 {
+		return fmt.Errorf("%s error %d", cmd, cmd.ExitCode())
+	}
+	return nil
+}
+
+// CreateSnapshot creates a snapshot logical volume of the logical volume.
+// The size is empty for the snapshots of thin logical volumes, and is
+// either a lvcreate -L size or a -l extents percentage (ex: 10%ORIGIN)
+// for the snapshots of thick logical volumes. The snapshot is created
+// with the activation skip flag unset, so it can be activated like any
+// other logical volume.
+func (t *LV) CreateSnapshot(name string, size string) error {
+	args := []string{"--yes", "-s", "-kn", "-n", name}
+	switch {
+	case size == "":
+	case strings.Contains(size, "%"):
+		args = append(args, "-l", size)
+	default:
+		if i, err := sizeconv.FromSize(size); err == nil {
+			size = fmt.Sprintf("%dB", i)
+		}
+		args = append(args, "-L", size)
+	}
+	cmd := command.New(
+		command.WithName("lvcreate"),
+		command.WithArgs(append(args, t.FQN())),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	cmd.Run()
+	if cmd.ExitCode() != 0 {
+		return fmt.Errorf("%s error %d", cmd, cmd.ExitCode())
+	}
+	return nil
+}
+
+// Merge merges the snapshot logical volume into its origin. If the
+// origin is open, the merge is deferred to its next activation.
+func (t *LV) Merge() error {
+	cmd := command.New(
+		command.WithName("lvconvert"),
+		command.WithVarArgs("--merge", t.FQN()),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	cmd.Run()
+	if cmd.ExitCode() != 0 {
+		return fmt.Errorf("%s error %d", cmd, cmd.ExitCode())
+	}
+	return nil
+}
+
+// Resize sets the logical volume size. The filesystem it hosts, if any,
+// must be shrunk before the logical volume.
+func (t *LV) Resize(size string) error {
+	if i, err := sizeconv.FromSize(size); err == nil {
+		size = fmt.Sprintf("%dB", i)
+	}
+	cmd := command.New(
+		command.WithName("lvresize"),
+		command.WithVarArgs("--yes", "-f", "-L", size, t.FQN()),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	cmd.Run()
+	if cmd.ExitCode() != 0 {
+		return fmt.Errorf("%s error %d", cmd, cmd.ExitCode())
+	}
+	return nil
+}
+
+// Usage returns the size in bytes of the logical volume, and the
+// percentage of it used by data, for thin pools and snapshots.
+func (t *LV) Usage() (int64, float64, error) {
+	data := LVData{}
+	fqn := t.FQN()
+	cmd := command.New(
+		command.WithName("lvs"),
+		command.WithVarArgs("-o", "lv_name,vg_name,lv_size,data_percent", "--units", "b", "--nosuffix", "--reportformat", "json", fqn),
+		command.WithLogger(t.log),
+		command.WithStdoutLogLevel(zerolog.DebugLevel),
+		command.WithStderrLogLevel(zerolog.DebugLevel),
+		command.WithBufferedStdout(),
+	)
+	if err := cmd.Run(); err != nil {
+		if cmd.ExitCode() == 5 {
+			return 0, 0, errors.Wrap(ErrExist, fqn)
+		}
+		return 0, 0, err
+	}
+	if err := json.Unmarshal(cmd.Stdout(), &data); err != nil {
+		return 0, 0, err
+	}
+	if len(data.Report) != 1 || len(data.Report[0].LV) != 1 {
+		return 0, 0, errors.Wrap(ErrExist, fqn)
+	}
+	return parseUsage(data.Report[0].LV[0].LVSize, data.Report[0].LV[0].DataPercent)
+}
+
+func parseUsage(size, percent string) (int64, float64, error) {
+	i, err := strconv.ParseInt(strings.TrimSpace(size), 10, 64)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "parse size %s", size)
+	}
+	if strings.TrimSpace(percent) == "" {
+		return i, 0, nil
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(percent), 64)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "parse data percent %s", percent)
+	}
+	return i, f, nil
+}
+
 func (t *LV) Wipe() error {
 	path := t.DevPath()
 	if !file.Exists(path) {
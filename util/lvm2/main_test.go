@@ -0,0 +1,35 @@
+// +build linux
+
+package lvm2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUsage(t *testing.T) {
+	size, percent, err := parseUsage("  1073741824", "12.50")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1073741824), size)
+	assert.Equal(t, 12.5, percent)
+
+	size, percent, err = parseUsage("4194304", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4194304), size)
+	assert.Equal(t, 0.0, percent)
+
+	_, _, err = parseUsage("<1.00g", "")
+	assert.Error(t, err)
+}
+
+func TestLVInfoTime(t *testing.T) {
+	tm, err := LVInfo{LVTime: "2021-03-04 10:11:12 +0100"}.Time()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 3, 4, 9, 11, 12, 0, time.UTC), tm.UTC())
+
+	_, err = LVInfo{}.Time()
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+// +build linux
+
+package lvm2
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	VGData struct {
+		Report []VGReport `json:"report"`
+	}
+	VGReport struct {
+		VG []VGInfo `json:"vg"`
+	}
+	VGInfo struct {
+		VGName string `json:"vg_name"`
+		VGSize string `json:"vg_size"`
+		VGFree string `json:"vg_free"`
+	}
+	VG struct {
+		driver
+		VGName string
+		log    *zerolog.Logger
+	}
+)
+
+var (
+	ErrVGExist = errors.New("vg does not exist")
+)
+
+func NewVG(vg string, opts ...funcopt.O) *VG {
+	t := VG{
+		VGName: vg,
+	}
+	_ = funcopt.Apply(&t, opts...)
+	return &t
+}
+
+func (t *VG) Show() (*VGInfo, error) {
+	data := VGData{}
+	cmd := command.New(
+		command.WithName("vgs"),
+		command.WithVarArgs("-o", "vg_name,vg_size,vg_free", "--units", "b", "--nosuffix", "--reportformat", "json", t.VGName),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.DebugLevel),
+		command.WithStdoutLogLevel(zerolog.DebugLevel),
+		command.WithStderrLogLevel(zerolog.DebugLevel),
+		command.WithBufferedStdout(),
+	)
+	if err := cmd.Run(); err != nil {
+		if cmd.ExitCode() == 5 {
+			return nil, errors.Wrap(ErrVGExist, t.VGName)
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(cmd.Stdout(), &data); err != nil {
+		return nil, err
+	}
+	if len(data.Report) == 1 && len(data.Report[0].VG) == 1 {
+		return &data.Report[0].VG[0], nil
+	}
+	return nil, errors.Wrap(ErrVGExist, t.VGName)
+}
+
+// Usage returns the size and free space of the volume group, in bytes.
+func (t *VG) Usage() (int64, int64, error) {
+	info, err := t.Show()
+	if err != nil {
+		return 0, 0, err
+	}
+	size, _, err := parseUsage(info.VGSize, "")
+	if err != nil {
+		return 0, 0, err
+	}
+	free, _, err := parseUsage(info.VGFree, "")
+	if err != nil {
+		return 0, 0, err
+	}
+	if free > size {
+		return 0, 0, fmt.Errorf("vg %s free %d is greater than size %d", t.VGName, free, size)
+	}
+	return size, free, nil
+}
+
+// LVs returns the information of the logical volumes of the volume group.
+func (t *VG) LVs() ([]LVInfo, error) {
+	data := LVData{}
+	cmd := command.New(
+		command.WithName("lvs"),
+		command.WithVarArgs("-o", "lv_name,vg_name,lv_attr,lv_size,origin,lv_time", "--units", "b", "--nosuffix", "--reportformat", "json", t.VGName),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.DebugLevel),
+		command.WithStdoutLogLevel(zerolog.DebugLevel),
+		command.WithStderrLogLevel(zerolog.DebugLevel),
+		command.WithBufferedStdout(),
+	)
+	if err := cmd.Run(); err != nil {
+		if cmd.ExitCode() == 5 {
+			return nil, errors.Wrap(ErrVGExist, t.VGName)
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(cmd.Stdout(), &data); err != nil {
+		return nil, err
+	}
+	if len(data.Report) != 1 {
+		return nil, errors.Wrap(ErrVGExist, t.VGName)
+	}
+	return data.Report[0].LV, nil
+}
+
+// Time returns the parsed creation time of the logical volume.
+func (t LVInfo) Time() (time.Time, error) {
+	return time.Parse("2006-01-02 15:04:05 -0700", t.LVTime)
+}
@@ -0,0 +1,39 @@
+// +build linux
+
+package proc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// FindByEnv returns the pids of the processes having all the "k=v"
+// elements in their environment.
+func FindByEnv(env ...string) ([]int, error) {
+	l := make([]int, 0)
+	if len(env) == 0 {
+		return l, nil
+	}
+	self := os.Getpid()
+	matches, err := filepath.Glob("/proc/[0-9]*/environ")
+	if err != nil {
+		return l, err
+	}
+	for _, p := range matches {
+		pid, err := strconv.Atoi(filepath.Base(filepath.Dir(p)))
+		if err != nil || pid == self {
+			continue
+		}
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			// process gone or not readable
+			continue
+		}
+		if hasEnv(b, env) {
+			l = append(l, pid)
+		}
+	}
+	return l, nil
+}
@@ -0,0 +1,31 @@
+// Package proc finds processes by their environment variables.
+//
+// The resource drivers set the OPENSVC_ID and OPENSVC_RID variables in the
+// environment of the processes they start, so these processes can be
+// found later, to send them signals for example.
+package proc
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrNotSupported is returned on operating systems not supported.
+	ErrNotSupported = errors.New("process environment lookup is not supported on this operating system")
+)
+
+// hasEnv returns true if the NUL-separated environment data contains all
+// the "k=v" elements.
+func hasEnv(data []byte, env []string) bool {
+	m := make(map[string]bool)
+	for _, e := range strings.Split(string(data), "\x00") {
+		m[e] = true
+	}
+	for _, e := range env {
+		if !m[e] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,32 @@
+// +build linux
+
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindByEnv(t *testing.T) {
+	cmd := exec.Command("sleep", "10")
+	cmd.Env = append(os.Environ(), "OPENSVC_ID=test-find-by-env", "OPENSVC_RID=app#1")
+	require.NoError(t, cmd.Start())
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	pids, err := FindByEnv("OPENSVC_ID=test-find-by-env", "OPENSVC_RID=app#1")
+	require.NoError(t, err)
+	assert.Equal(t, []int{cmd.Process.Pid}, pids)
+
+	pids, err = FindByEnv("OPENSVC_ID=test-find-by-env", "OPENSVC_RID=app#2")
+	require.NoError(t, err)
+	assert.Len(t, pids, 0)
+}
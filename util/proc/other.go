@@ -0,0 +1,9 @@
+// +build !linux
+
+package proc
+
+// FindByEnv returns the pids of the processes having all the "k=v"
+// elements in their environment.
+func FindByEnv(env ...string) ([]int, error) {
+	return nil, ErrNotSupported
+}
@@ -0,0 +1,56 @@
+// +build linux
+
+// Package reflink clones files sharing their data extents, on the
+// filesystems supporting it (xfs with reflink=1, btrfs, ...).
+package reflink
+
+import (
+	"io/ioutil"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// Clone creates dst as a copy-on-write clone of src.
+// The dst file is removed if the filesystem can not clone src.
+func Clone(src, dst string) error {
+	r, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	info, err := r.Stat()
+	if err != nil {
+		return err
+	}
+	w, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	if err := unix.IoctlFileClone(int(w.Fd()), int(r.Fd())); err != nil {
+		w.Close()
+		os.Remove(dst)
+		return err
+	}
+	return nil
+}
+
+// IsCapable returns true if the filesystem hosting dir supports clones.
+func IsCapable(dir string) bool {
+	src, err := ioutil.TempFile(dir, ".reflink-")
+	if err != nil {
+		return false
+	}
+	defer os.Remove(src.Name())
+	defer src.Close()
+	if _, err := src.Write([]byte{0}); err != nil {
+		return false
+	}
+	dst := src.Name() + ".clone"
+	if err := Clone(src.Name(), dst); err != nil {
+		return false
+	}
+	os.Remove(dst)
+	return true
+}
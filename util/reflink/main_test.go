@@ -0,0 +1,30 @@
+// +build linux
+
+package reflink
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reflink")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	require.NoError(t, ioutil.WriteFile(src, []byte("data"), 0600))
+	if !IsCapable(dir) {
+		require.Error(t, Clone(src, dst))
+		require.NoFileExists(t, dst)
+		t.Skip("reflink not supported by the filesystem hosting", dir)
+	}
+	require.NoError(t, Clone(src, dst))
+	b, err := ioutil.ReadFile(dst)
+	require.NoError(t, err)
+	require.Equal(t, "data", string(b))
+}
@@ -0,0 +1,17 @@
+// +build !linux
+
+package reflink
+
+import "errors"
+
+var errNotSupported = errors.New("reflink is not supported on this os")
+
+// Clone creates dst as a copy-on-write clone of src.
+func Clone(src, dst string) error {
+	return errNotSupported
+}
+
+// IsCapable returns true if the filesystem hosting dir supports clones.
+func IsCapable(dir string) bool {
+	return false
+}